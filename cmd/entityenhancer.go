@@ -26,6 +26,8 @@ import (
 /////////////////////////////////////////////////////////////////////////////
 
 const (
+	accessorPkgPath = "github.com/kelveny/gdbc/pkg/accessor"
+
 	header = `// CODE GENERATED AUTOMATICALLY WITH github.com/kelveny/gdbc entity enhancer
 // THIS FILE SHOULD NOT BE EDITED BY HAND
 package %s
@@ -47,6 +49,16 @@ type {{ .Entity }}TableColumns struct {
 func (e *{{ .Entity }}) TableName() string {
     return "{{ .Table }}"
 }
+{{- if .Discriminator }}
+
+func (e *{{ .Entity }}) DiscriminatorColumn() string {
+    return "{{ .Discriminator }}"
+}
+
+func (e *{{ .Entity }}) DiscriminatorValue() string {
+    return "{{ .DiscriminatorValue }}"
+}
+{{- end }}
 
 func (e *{{ .Entity }}) EntityFields() *{{ .Entity }}EntityFields {
     return &{{ .Entity }}EntityFields {
@@ -64,9 +76,34 @@ func (e *{{ .Entity }}) TableColumns() *{{ .Entity }}TableColumns {
     }
 }
 
+type {{ .Entity }}ColumnCriteria struct {
+    {{- range $index, $f := .Fields }}
+    {{ $f.Name }} accessor.TableColumn
+    {{- end }}
+    {{- range $i, $base := .BaseFields }}
+    {{- range $j, $f := $base.Fields }}
+    {{ $f.Name }} accessor.TableColumn
+    {{- end }}
+    {{- end }}
+}
+
+var {{ .Entity }}Cols = {{ .Entity }}ColumnCriteria {
+    {{- range $index, $f := .Fields }}
+    {{ $f.Name }}: accessor.NewTableColumn("{{ $.Table }}", "{{ $f.Column }}"),
+    {{- end }}
+    {{- range $i, $base := .BaseFields }}
+    {{- range $j, $f := $base.Fields }}
+    {{ $f.Name }}: accessor.NewTableColumn("{{ $base.Table }}", "{{ $f.Column }}"),
+    {{- end }}
+    {{- end }}
+}
+
 type {{ .Entity }}WithUpdateTracker struct {
     {{ .Entity }}
 	trackMap map[string]map[string]bool
+	{{- if .HasJsonFields }}
+	patchMap map[string][]accessor.JsonPatch
+	{{- end }}
 }
 
 func (e *{{ .Entity }}WithUpdateTracker) registerChange(tbl string, col string) {
@@ -84,6 +121,10 @@ func (e *{{ .Entity }}WithUpdateTracker) registerChange(tbl string, col string)
 	}
 }
 
+func (e *{{ .Entity }}WithUpdateTracker) MarkChanged(tbl string, col string) {
+	e.registerChange(tbl, col)
+}
+
 func (e *{{ .Entity }}WithUpdateTracker) ColumnsChanged(tbl ...string) []string {
     cols := []string{}
 
@@ -100,25 +141,91 @@ func (e *{{ .Entity }}WithUpdateTracker) ColumnsChanged(tbl ...string) []string
 
     return cols
 }
+{{- if .HasJsonFields }}
+
+func (e *{{ .Entity }}WithUpdateTracker) registerPatch(tbl string, col string, patch accessor.JsonPatch) {
+	if e.patchMap == nil {
+		e.patchMap = make(map[string][]accessor.JsonPatch)
+	}
+
+	key := tbl + "." + col
+	e.patchMap[key] = append(e.patchMap[key], patch)
+	e.registerChange(tbl, col)
+}
+
+func (e *{{ .Entity }}WithUpdateTracker) JsonPatches(tbl string, col string) []accessor.JsonPatch {
+	return e.patchMap[tbl+"."+col]
+}
+{{- end }}
 
 {{- with $root := . }}
 
 {{ range $index, $f := .Fields }}
-func (e *{{ $root.Entity }}WithUpdateTracker) Set{{ $f.Name }}(val {{ $f.TypeDecl }}) *{{ $root.Entity }}WithUpdateTracker {
+{{- if not $f.Readonly }}
+func (e *{{ $root.Entity }}WithUpdateTracker) Set{{ $f.Name }}(val {{ if $f.NullOf }}{{ $f.NullOf }}{{ else }}{{ $f.TypeDecl }}{{ end }}) *{{ $root.Entity }}WithUpdateTracker {
+    {{- if $f.NullOf }}
+    e.{{ $f.Name }} = accessor.NewNull(val)
+    {{- else }}
     e.{{ $f.Name }} = val
+    {{- end }}
+	{{- if $f.Json }}
+	delete(e.patchMap, "{{ $root.Table }}.{{ $f.Column }}")
+	{{- end }}
 	e.registerChange("{{ $root.Table }}", "{{ $f.Column }}")
     return e
 }
+{{ if $f.NullValue }}
+func (e *{{ $root.Entity }}WithUpdateTracker) Set{{ $f.Name }}Null() *{{ $root.Entity }}WithUpdateTracker {
+	e.{{ $f.Name }} = {{ $f.NullValue }}
+	{{- if $f.Json }}
+	delete(e.patchMap, "{{ $root.Table }}.{{ $f.Column }}")
+	{{- end }}
+	e.registerChange("{{ $root.Table }}", "{{ $f.Column }}")
+	return e
+}
+{{ end }}
+{{- if $f.Json }}
+func (e *{{ $root.Entity }}WithUpdateTracker) Patch{{ $f.Name }}(path []string, val any) *{{ $root.Entity }}WithUpdateTracker {
+	e.registerPatch("{{ $root.Table }}", "{{ $f.Column }}", accessor.JsonPatch{Path: path, Value: val})
+	return e
+}
 {{ end }}
+{{- end }}
+{{- end }}
 
 {{ range $i, $base := .BaseFields }}
 {{ range $j, $f := $base.Fields }}
+{{- if not $f.Readonly }}
 
-func (e *{{ $root.Entity }}WithUpdateTracker) Set{{ $f.Name }}(val {{ $f.TypeDecl }}) *{{ $root.Entity }}WithUpdateTracker {
+func (e *{{ $root.Entity }}WithUpdateTracker) Set{{ $f.Name }}(val {{ if $f.NullOf }}{{ $f.NullOf }}{{ else }}{{ $f.TypeDecl }}{{ end }}) *{{ $root.Entity }}WithUpdateTracker {
+	{{- if $f.NullOf }}
+	e.{{ $f.Name }} = accessor.NewNull(val)
+	{{- else }}
 	e.{{ $f.Name }} = val
+	{{- end }}
+	{{- if $f.Json }}
+	delete(e.patchMap, "{{ $base.Table }}.{{ $f.Column }}")
+	{{- end }}
+	e.registerChange("{{ $base.Table }}", "{{ $f.Column }}")
+	return e
+}
+{{ if $f.NullValue }}
+func (e *{{ $root.Entity }}WithUpdateTracker) Set{{ $f.Name }}Null() *{{ $root.Entity }}WithUpdateTracker {
+	e.{{ $f.Name }} = {{ $f.NullValue }}
+	{{- if $f.Json }}
+	delete(e.patchMap, "{{ $base.Table }}.{{ $f.Column }}")
+	{{- end }}
 	e.registerChange("{{ $base.Table }}", "{{ $f.Column }}")
 	return e
 }
+{{ end }}
+{{- if $f.Json }}
+func (e *{{ $root.Entity }}WithUpdateTracker) Patch{{ $f.Name }}(path []string, val any) *{{ $root.Entity }}WithUpdateTracker {
+	e.registerPatch("{{ $base.Table }}", "{{ $f.Column }}", accessor.JsonPatch{Path: path, Value: val})
+	return e
+}
+{{ end }}
+{{- end }}
 
 {{ end }}
 {{ end }}
@@ -188,6 +295,19 @@ type EntityFieldSpec struct {
 	Name     string
 	Column   string
 	TypeDecl string
+
+	// column is mapped to JSON document (json attribute)
+	Json bool
+
+	// type argument T when field is of accessor.Null[T] type, setter accepts T instead
+	NullOf string
+
+	// column is never written (readonly attribute), no setter is generated
+	Readonly bool
+
+	// literal of field type that is written as NULL, e.g. nil for pointer types. It
+	// is empty if field type is not nullable, otherwise SetXXXNull() is generated
+	NullValue string
 }
 
 type EntitySpec struct {
@@ -249,50 +369,24 @@ func (es *EntitySpec) FlattenFieldSpecs(
 	importSpecs map[string][]gogen.ImportSpec,
 ) (tables []string) {
 	tables = append(tables, tbl)
-	fieldSpecs[tbl] = es.FieldSpecs
-	importSpecs[tbl] = es.Imports
+
+	// base types of single-table inheritance add their fields to the same table
+	fieldSpecs[tbl] = append(fieldSpecs[tbl], es.FieldSpecs...)
+	importSpecs[tbl] = append(importSpecs[tbl], es.Imports...)
 
 	for _, field := range es.TypeSpec.Fields.List {
-		if field.Tag != nil {
-			if strings.HasPrefix(field.Tag.Value, "`db:") {
-				tagValue := strings.Split(strings.Trim(field.Tag.Value, "`"), ":")
-				attrs := strings.Split(strings.Trim(tagValue[1], "\""), ",")
-				if len(attrs) > 1 {
-					for _, v := range attrs[1:] {
-						kv := strings.Split(v, "=")
-						if len(kv) > 1 {
-							if strings.Trim(kv[0], " ") == "table" {
-								tbl := strings.Trim(kv[1], " ")
-
-								name := gosyntax.ExprDeclString(es.TokenFset, field.Type)
-								name = strings.Trim(name, "*") // remove pointer declaration from name
-
-								tokens := strings.Split(name, ".")
-								hostDir := pkgDir
-								var err error
-								if len(tokens) > 1 {
-									name = tokens[1]
-									hostDir, err = resolveImportHostDir(modSpec, es.Imports, tokens[0])
-									if err != nil {
-										logger.Log(logger.ERROR, "Can not resolve import of package %s in %s", tokens[0], pkgDir)
-									}
-								}
-
-								baseSpec := lookupEntitySpec(hostDir, name)
-								if baseSpec == nil {
-									// perform lazy registration for cross-package code generation
-									scanDir(modSpec, hostDir, scanPredicate, buildEntityRegistry)
-								}
-
-								baseSpec = lookupEntitySpec(hostDir, name)
-								if baseSpec != nil {
-									tables = append(tables, baseSpec.FlattenFieldSpecs(modSpec, baseSpec.PkgHostDir, tbl, fieldSpecs, importSpecs)...)
-								} else {
-									logger.Log(logger.ERROR, "Can not find entity %s in %s", name, hostDir)
-								}
-							}
-						}
-					}
+		if field.Tag != nil && len(field.Names) == 0 {
+			attrs := attributesFromTag(field.Tag.Value)
+
+			// join attribute is left to accessor, it renames key columns of the table, which
+			// are not mapped by fields and have no generated columns or change tracking
+			if baseTbl, ok := attrs["table"]; ok {
+				if baseSpec := es.lookupBaseEntitySpec(modSpec, pkgDir, field); baseSpec != nil {
+					tables = append(tables, baseSpec.FlattenFieldSpecs(modSpec, baseSpec.PkgHostDir, strings.Trim(baseTbl, " "), fieldSpecs, importSpecs)...)
+				}
+			} else if _, ok := attrs["discriminator"]; ok {
+				if baseSpec := es.lookupBaseEntitySpec(modSpec, pkgDir, field); baseSpec != nil {
+					tables = append(tables, baseSpec.FlattenFieldSpecs(modSpec, baseSpec.PkgHostDir, tbl, fieldSpecs, importSpecs)[1:]...)
 				}
 			}
 		}
@@ -300,6 +394,51 @@ func (es *EntitySpec) FlattenFieldSpecs(
 	return
 }
 
+// lookupBaseEntitySpec returns entity spec of type of embedded field
+func (es *EntitySpec) lookupBaseEntitySpec(modSpec *ModuleSpec, pkgDir string, field *ast.Field) *EntitySpec {
+	name := gosyntax.ExprDeclString(es.TokenFset, field.Type)
+	name = strings.Trim(name, "*") // remove pointer declaration from name
+
+	tokens := strings.Split(name, ".")
+	hostDir := pkgDir
+	var err error
+	if len(tokens) > 1 {
+		name = tokens[1]
+		hostDir, err = resolveImportHostDir(modSpec, es.Imports, tokens[0])
+		if err != nil {
+			logger.Log(logger.ERROR, "Can not resolve import of package %s in %s", tokens[0], pkgDir)
+		}
+	}
+
+	baseSpec := lookupEntitySpec(hostDir, name)
+	if baseSpec == nil {
+		// perform lazy registration for cross-package code generation
+		scanDir(modSpec, hostDir, scanPredicate, buildEntityRegistry)
+	}
+
+	baseSpec = lookupEntitySpec(hostDir, name)
+	if baseSpec == nil {
+		logger.Log(logger.ERROR, "Can not find entity %s in %s", name, hostDir)
+	}
+
+	return baseSpec
+}
+
+// discriminatorOf returns discriminator column and value if entity type is mapped by
+// single-table inheritance, e.g. `db:",discriminator=kind,value=manager"`
+func discriminatorOf(entity *ast.StructType) (string, string) {
+	for _, field := range entity.Fields.List {
+		if field.Tag != nil && len(field.Names) == 0 {
+			attrs := attributesFromTag(field.Tag.Value)
+			if col, ok := attrs["discriminator"]; ok {
+				return strings.Trim(col, " "), strings.Trim(attrs["value"], " ")
+			}
+		}
+	}
+
+	return "", ""
+}
+
 /////////////////////////////////////////////////////////////////////////////
 
 // must be public for it to be used in loading YAML configuration
@@ -490,6 +629,34 @@ func columnFromTag(tag string) string {
 	return ""
 }
 
+func attributesFromTag(tag string) map[string]string {
+	attrs := map[string]string{}
+
+	if strings.HasPrefix(tag, "`db:") {
+		s, err := strconv.Unquote(tag)
+		if err != nil {
+			return attrs
+		}
+
+		s, err = strconv.Unquote(strings.TrimPrefix(s, "db:"))
+		if err != nil {
+			return attrs
+		}
+
+		tokens := strings.Split(s, ",")
+		for _, token := range tokens[1:] {
+			kv := strings.SplitN(strings.Trim(token, " "), "=", 2)
+			if len(kv) == 2 {
+				attrs[kv[0]] = kv[1]
+			} else {
+				attrs[kv[0]] = ""
+			}
+		}
+	}
+
+	return attrs
+}
+
 func isEntityStruct(st *ast.StructType) bool {
 	for _, field := range st.Fields.List {
 		if field.Tag != nil {
@@ -508,11 +675,21 @@ func getEntityFields(fset *token.FileSet, entity *ast.StructType) []EntityFieldS
 		if field.Tag != nil {
 			col := columnFromTag(field.Tag.Value)
 			if col != "" {
-				fields = append(fields, EntityFieldSpec{
+				attrs := attributesFromTag(field.Tag.Value)
+				_, isJson := attrs["json"]
+				_, isReadonly := attrs["readonly"]
+
+				spec := EntityFieldSpec{
 					Name:     field.Names[0].Name,
 					Column:   col,
 					TypeDecl: gosyntax.ExprDeclString(fset, field.Type),
-				})
+					Json:     isJson,
+					NullOf:   nullTypeArg(fset, field.Type),
+					Readonly: isReadonly,
+				}
+				spec.NullValue = nullValue(field.Type, spec.TypeDecl)
+
+				fields = append(fields, spec)
 			}
 		}
 	}
@@ -520,6 +697,44 @@ func getEntityFields(fset *token.FileSet, entity *ast.StructType) []EntityFieldS
 	return fields
 }
 
+// nullTypeArg returns T if expr declares accessor.Null[T] type
+func nullTypeArg(fset *token.FileSet, expr ast.Expr) string {
+	if idx, ok := expr.(*ast.IndexExpr); ok {
+		if sel, ok := idx.X.(*ast.SelectorExpr); ok && sel.Sel.Name == "Null" {
+			if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Name == filepath.Base(accessorPkgPath) {
+				return gosyntax.ExprDeclString(fset, idx.Index)
+			}
+		}
+	}
+
+	return ""
+}
+
+// nullValue returns literal of type declared by expr that is written as NULL, it supports
+// pointers, maps, slices, interfaces, accessor.Null[T] and sql.NullXXX types
+func nullValue(expr ast.Expr, typeDecl string) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr, *ast.MapType, *ast.InterfaceType:
+		return "nil"
+	case *ast.ArrayType:
+		if t.Len == nil {
+			return "nil"
+		}
+	case *ast.IndexExpr:
+		if sel, ok := t.X.(*ast.SelectorExpr); ok && sel.Sel.Name == "Null" {
+			if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Name == filepath.Base(accessorPkgPath) {
+				return typeDecl + "{}"
+			}
+		}
+	case *ast.SelectorExpr:
+		if pkg, ok := t.X.(*ast.Ident); ok && pkg.Name == "sql" && strings.HasPrefix(t.Sel.Name, "Null") {
+			return typeDecl + "{}"
+		}
+	}
+
+	return ""
+}
+
 func isImportSpecInSlice(slice []gogen.ImportSpec, spec gogen.ImportSpec) bool {
 	for _, specInSlice := range slice {
 		if specInSlice.Name == spec.Name && specInSlice.Path == spec.Path {
@@ -535,14 +750,12 @@ func mergeBaseImports(
 	tables []string,
 	flattenImports map[string][]gogen.ImportSpec,
 ) []gogen.ImportSpec {
-	for i, tbl := range tables {
-		// process imports from base entities only
-		if i > 0 {
-			specs := flattenImports[tbl]
-			for _, specInBase := range specs {
-				if !isImportSpecInSlice(imports, specInBase) {
-					imports = append(imports, specInBase)
-				}
+	// base entities of single-table inheritance may bring imports into the entity table
+	for _, tbl := range tables {
+		specs := flattenImports[tbl]
+		for _, specInBase := range specs {
+			if !isImportSpecInSlice(imports, specInBase) {
+				imports = append(imports, specInBase)
 			}
 		}
 	}
@@ -554,7 +767,7 @@ func generate(
 	writer io.Writer,
 	pkgDir string,
 	file *ast.File,
-	_ *ast.StructType,
+	entity *ast.StructType,
 	tables []string,
 	fields map[string][]EntityFieldSpec,
 	flattenImports map[string][]gogen.ImportSpec,
@@ -570,11 +783,12 @@ func generate(
 	imports := gogen.GetFileImports(file)
 	if cleanImports {
 		imports = gogen.CleanImports(file, nil)
+	} else {
+		// generated column criteria are built on top of accessor.TableColumn
+		imports = gogen.AppendImportSpec(imports, "", accessorPkgPath)
 	}
 
-	if len(tables) > 1 {
-		imports = mergeBaseImports(imports, tables, flattenImports)
-	}
+	imports = mergeBaseImports(imports, tables, flattenImports)
 	gogen.WriteImportDecls(writer, imports)
 
 	baseFields := []struct {
@@ -594,6 +808,15 @@ func generate(
 		}
 	}
 
+	hasJsonFields := false
+	for _, tbl := range tables {
+		for _, f := range fields[tbl] {
+			hasJsonFields = hasJsonFields || f.Json
+		}
+	}
+
+	discriminator, discriminatorValue := discriminatorOf(entity)
+
 	// generate code
 	binding := struct {
 		Entity     string
@@ -603,11 +826,17 @@ func generate(
 			Table  string
 			Fields []EntityFieldSpec
 		}
+		HasJsonFields      bool
+		Discriminator      string
+		DiscriminatorValue string
 	}{
-		Entity:     option.Entity,
-		Table:      option.Table,
-		Fields:     fields[option.Table],
-		BaseFields: baseFields,
+		Entity:             option.Entity,
+		Table:              option.Table,
+		Fields:             fields[option.Table],
+		BaseFields:         baseFields,
+		HasJsonFields:      hasJsonFields,
+		Discriminator:      discriminator,
+		DiscriminatorValue: discriminatorValue,
 	}
 	t := template.Must(template.New("EntityEnhancer").
 		Parse(entityenhancerTemplate))
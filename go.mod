@@ -4,7 +4,6 @@ go 1.18
 
 require (
 	github.com/Masterminds/squirrel v1.5.4
-	github.com/barkimedes/go-deepcopy v0.0.0-20220514131651-17c30cfc62df
 	github.com/jmoiron/sqlx v1.3.5
 	github.com/kelveny/mockcompose v0.1.9
 	github.com/lib/pq v1.10.9
@@ -28,8 +28,20 @@
 //  Create(ctx context.Context, entity any, tbl string, idFields ...string) error
 //  Read(ctx context.Context, entity any, tbl string, idFields ...string) error
 //  Update(ctx context.Context, entity any, tbl string, idFields ...string) (sql.Result, error)
+//  UpdateReturning(ctx context.Context, entity any, tbl string, idFields ...string) error
+//
+//  Track(entity any) error
+//  Untrack(entity any)
 //  Delete(ctx context.Context, entity any, tbl string, idFields ...string) (sql.Result, error)
 //
+//  ReadMany(ctx context.Context, destSlice any, tbl string, ids []any, idFields ...string) error
+//  DeleteMany(ctx context.Context, entity any, tbl string, ids []any, idFields ...string) (sql.Result, error)
+//
+//  UpdateWhere(ctx context.Context, entity any, tbl string, setMap map[string]any, where Sqlizer, idFields ...string) (sql.Result, error)
+//  UpdateWhereReturning(ctx context.Context, dest any, tbl string, setMap map[string]any, where Sqlizer, idFields ...string) error
+//  DeleteWhere(ctx context.Context, entity any, tbl string, where Sqlizer, idFields ...string) (sql.Result, error)
+//  DeleteWhereReturning(ctx context.Context, dest any, tbl string, where Sqlizer, idFields ...string) error
+//
 //  EntityGet(
 //    ctx context.Context,
 //    dest any,
@@ -45,6 +57,14 @@
 //    idFields ...string,
 //  ) error
 //
+//  FindByExample(ctx context.Context, dest any, example any, tbl string, opts *ExampleOptions) error
+//
+//  Count(ctx context.Context, entity any, tbl string, sqlizer func(builder squirrel.SelectBuilder) Sqlizer, idFields ...string) (int64, error)
+//  Exists(ctx context.Context, entity any, tbl string, sqlizer func(builder squirrel.SelectBuilder) Sqlizer, idFields ...string) (bool, error)
+//
+//  ReadPolymorphic(ctx context.Context, id any, root any, idFields ...string) (any, error)
+//  SelectPolymorphic(ctx context.Context, root any, sqlizer func(builder squirrel.SelectBuilder) Sqlizer, idFields ...string) ([]any, error)
+//
 // 2. public helper functions
 //    ExecTx(
 //        ctx context.Context,
@@ -56,6 +76,33 @@
 //  Column(v any, fieldName string) string
 //  Columns(v any) []string
 //
+//  Sum[T Number](ctx, a *Accessor, entity any, tbl string, col string, sqlizer, idFields ...string) (T, error)
+//  Min[T Number](ctx, a *Accessor, entity any, tbl string, col string, sqlizer, idFields ...string) (T, bool, error)
+//  Max[T Number](ctx, a *Accessor, entity any, tbl string, col string, sqlizer, idFields ...string) (T, bool, error)
+//
+//  RegisterAudit(entities ...any)
+//  UnregisterAudit(entities ...any)
+//  WithActor(ctx context.Context, actor string) context.Context
+//  ActorFromContext(ctx context.Context) string
+//
+//  Enqueue(ctx context.Context, topic string, payload any) error
+//
+//  RegisterHierarchy(root TableNamer, subtypes ...TableNamer) error
+//  UnregisterHierarchy(root TableNamer)
+//
+//  RegisterDeleteStrategy(strategy DeleteStrategy, entities ...any)
+//  UnregisterDeleteStrategy(entities ...any)
+//  WithDeleteStrategy(ctx context.Context, strategy DeleteStrategy) context.Context
+//
+//  NewSession(db *sqlx.DB) *Session
+//
+//  FromContext(ctx context.Context, fallbackDB *sqlx.DB) (*Accessor, error)
+//  NewTxRunner(db *sqlx.DB) TxRunner
+//
+//  RegisterCache(cache EntityCache, entities ...any)
+//  UnregisterCache(entities ...any)
+//  NewLRUCache(size int, ttl time.Duration) *LRUCache
+//
 // 3. Accessor itself is not thread-safe, however, its underlying backend musts be thread-safe.
 // 4. Accessor assumes manipulation of Dabatabse entity objects, columns of corresponding
 //    column mappings should exist in entity type (in Go struct tag "db")
@@ -63,6 +110,32 @@
 // 6. Accessor bridges github.com/jmoiron/sqlx and github.com/Masterminds/squirrel, allows
 //      generic database CRUD and SELECT operations with regular literal query binding, named query binding
 //      querying by example and programmatical query binding.
+// 7. Create() and Update() maintain time columns tagged with autoCreateTime and autoUpdateTime
+//      (e.g. `db:"created_at,autoCreateTime"`), timestamps are taken from Clock in UTC.
+// 8. Create(), Update() and Delete() of entities opted in by RegisterAudit or audit column attribute
+//      write audit records into AuditTable in the same transaction, see WithActor.
+// 9. Columns tagged with encrypted attribute (e.g. `db:"email,encrypted"`) are encrypted by FieldEncryptor
+//      on writes and decrypted transparently on reads.
+// 10. Fields tagged with json attribute (e.g. `db:"profile,json"`) are mapped as JSON documents, patches
+//      registered by JsonPatchTracker are applied with jsonb_set (Postgres) or json_set (SQLite).
+// 11. Nil pointers are written as NULL, pointers to zero values are written as zero values unless
+//      ZeroPointerAsNull is set. Null[T] maps NULL-able columns of primitive types.
+// 12. Columns tagged with readonly attribute are never written, insertonly columns are written by Create()
+//      only, and updateonly columns are written by Update() only.
+// 13. Create() omits zero-valued columns tagged with omitempty attribute so that column defaults apply,
+//      see also CreateChangedColumnsOnly.
+// 14. Embedded types tagged with discriminator attribute (e.g. `db:",discriminator=kind,value=manager"`) map
+//      single-table inheritance, Create() sets the discriminator column and reads only match rows of the subtype.
+// 15. Tables in the inheritance chain are joined on ID columns of the same names, unless embedded types declare
+//      the mapping with join attribute (e.g. `db:",table=person,join=person_id:id"`).
+// 16. On Postgres, Create() of composite entities inserts all tables in the inheritance chain with a single
+//      statement of chained data-modifying CTEs, other databases take one statement per table plus a read-back.
+// 17. Delete() of composite entities deletes each table in the inheritance chain, relies on ON DELETE CASCADE,
+//      or takes a single CTE statement on Postgres, see DeleteStrategy. Results report rows affected per table.
+// 18. Read() and EntityGet() of entities registered by RegisterCache go through EntityCache, writes invalidate
+//      cached entities sharing rows with them, after commit when made in ExecTx.
+// 19. ExecTx carries its accessor in context, FromContext and TxRunner join the ambient transaction,
+//      see Propagation.
 //
 package accessor
 
@@ -78,8 +151,6 @@ import (
 	"github.com/Masterminds/squirrel"
 	"github.com/jmoiron/sqlx"
 	"github.com/jmoiron/sqlx/reflectx"
-
-	cpy "github.com/barkimedes/go-deepcopy"
 )
 
 type Sqlizer interface {
@@ -99,6 +170,15 @@ type EntityMappingSchema struct {
 	// embedded mappings
 	BaseMappings []*EntityMappingSchema
 
+	// single-table inheritance, rows of entity type are distinguished by value of
+	// discriminator column in table TableName (empty if entity type is not mapped this way)
+	Discriminator      string
+	DiscriminatorValue string
+
+	// column of base table -> column of table TableName, when tables are joined on
+	// columns of different names (e.g. `db:",table=person,join=person_id:id"`)
+	JoinColumns map[string]string
+
 	Entity     any
 	EntityType reflect.Type
 }
@@ -136,33 +216,24 @@ func (m *EntityMappingSchema) GetColumnSelectString() string {
 }
 
 func (m *EntityMappingSchema) GetTableJoinString(idColumns ...string) string {
-	tables := m.Tables()
+	schemas := m.Schemas()
 
 	var builder strings.Builder
 
-	for i := 0; i < len(tables)-1; i++ {
+	for i := 0; i < len(schemas)-1; i++ {
 		if i > 0 {
 			builder.WriteString(" JOIN ")
 		}
-		builder.WriteString(tables[i+1] + " ON ")
-		builder.WriteString(getJoinOnString(tables[i], tables[i+1], idColumns...))
+		builder.WriteString(schemas[i+1].TableName + " ON ")
+		builder.WriteString(getKeyJoinOnString(
+			schemas[i].TableName, schemas[i].keyColumns(idColumns),
+			schemas[i+1].TableName, schemas[i+1].keyColumns(idColumns),
+		))
 	}
 
 	return builder.String()
 }
 
-func getJoinOnString(table1, table2 string, idColumns ...string) string {
-	var builder strings.Builder
-
-	for i, id := range idColumns {
-		builder.WriteString(fmt.Sprintf("%s.%s=%s.%s", table1, id, table2, id))
-		if i < len(idColumns)-1 {
-			builder.WriteString(" AND ")
-		}
-	}
-	return builder.String()
-}
-
 type noopSqlResult struct {
 }
 
@@ -176,11 +247,24 @@ func (r noopSqlResult) RowsAffected() (int64, error) {
 
 type Accessor struct {
 	Db sqlx.Ext
+
+	// snapshots taken by Track
+	snapshots *snapshotStore
+
+	// cache invalidations deferred until commit, in transaction of ExecTx
+	cacheTx *cacheTx
+
+	// database the transaction of ExecTx has been started on
+	txDb *sqlx.DB
+
+	// set when a TxRunner that joined the transaction of ExecTx fails
+	rollbackOnly bool
 }
 
 func New(db sqlx.Ext) *Accessor {
 	return &Accessor{
-		Db: db,
+		Db:        db,
+		snapshots: newSnapshotStore(),
 	}
 }
 
@@ -202,6 +286,14 @@ func New(db sqlx.Ext) *Accessor {
 func (a *Accessor) Get(ctx context.Context, dest any, query string, args ...any) error {
 	query = a.Db.Rebind(query)
 
+	if len(destJsonColumns(dest)) > 0 || len(destEncryptedColumns(dest)) > 0 {
+		rows, err := a.queryx(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		return a.scanOne(rows, dest, destTable(ctx, dest))
+	}
+
 	if db, ok := a.Db.(*sqlx.DB); ok {
 		return db.Unsafe().GetContext(ctx, dest, query, args...)
 	} else if tx, ok := a.Db.(*sqlx.Tx); ok {
@@ -224,6 +316,14 @@ func (a *Accessor) Get(ctx context.Context, dest any, query string, args ...any)
 func (a *Accessor) Select(ctx context.Context, dest any, query string, args ...any) error {
 	query = a.Db.Rebind(query)
 
+	if len(destJsonColumns(dest)) > 0 || len(destEncryptedColumns(dest)) > 0 {
+		rows, err := a.queryx(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		return a.scanAll(rows, dest, destTable(ctx, dest))
+	}
+
 	if db, ok := a.Db.(*sqlx.DB); ok {
 		return db.Unsafe().SelectContext(ctx, dest, query, args...)
 	} else if tx, ok := a.Db.(*sqlx.Tx); ok {
@@ -286,35 +386,95 @@ func (a *Accessor) Exec(ctx context.Context, query string, args ...any) (result
    err := accessor.Create(context.Background(), &city, "city")
 */
 func (a *Accessor) Create(ctx context.Context, entity any, tbl string, idFields ...string) error {
+	var err error
+	if cols := auditedColumns(entity); len(cols) > 0 {
+		err = a.auditedCreate(ctx, cols, entity, tbl, idFields...)
+	} else {
+		err = a.createEntity(ctx, entity, tbl, idFields...)
+	}
+
+	if err == nil {
+		a.invalidateCache(entity, tbl, idFields...)
+	}
+	return err
+}
+
+func (a *Accessor) createEntity(ctx context.Context, entity any, tbl string, idFields ...string) error {
+	touched := a.touchCreateTimestamps(entity)
+
 	s, err := EntitySchema(entity, reflect.TypeOf(entity), tbl)
 	if err != nil {
 		return err
 	}
 
+	var tracker UpdateTracker
+	if CreateChangedColumnsOnly {
+		if tracker, _ = entity.(UpdateTracker); tracker != nil {
+			markChanged(entity, s, touched)
+
+			if !hasTrackedChanges(tracker, s) {
+				tracker = nil
+			}
+		}
+	}
+
 	if len(s.BaseMappings) > 0 {
-		err = a.createComposite(ctx, s, idFields...)
+		if supportsDataModifyingCte(a.Db.DriverName()) && reflect.TypeOf(entity).Kind() == reflect.Ptr {
+			return a.createCompositeWithCte(ctx, s, tracker, idFields...)
+		}
+
+		err = a.createComposite(ctx, s, tracker, idFields...)
 		if err == nil {
-			// Due to deep-copy operation for embedded types, we need
-			// to perform a read-back operation to reflect NULL value
-			// into nil for associated fields
+			// Tables in the inheritance chain are inserted from copies
+			// of entity, perform a read-back operation to reflect values
+			// stored by database (e.g. NULLs, column defaults) into entity
 			if reflect.TypeOf(entity).Kind() == reflect.Ptr {
-				_ = a.Read(ctx, entity, tbl, idFields...)
+				_ = a.readEntity(ctx, entity, tbl, idFields...)
 			}
 		}
 
 		return err
 	}
 
-	return a.create(ctx, entity, s, nil, tbl, idFields...)
+	return a.create(ctx, entity, s, nil, tracker, tbl, idFields...)
 }
 
 func (a *Accessor) create(
 	ctx context.Context,
 	entity any, s *EntityMappingSchema,
 	baseColValueMap map[string]reflect.Value,
+	tracker UpdateTracker,
 	tbl string,
 	idFields ...string,
 ) error {
+	cols, vals, err := a.createMapping(entity, s, baseColValueMap, tracker, tbl, idFields...)
+	if err != nil {
+		return err
+	}
+
+	return a.SqlizerGet(WithTable(ctx, tbl), entity, func(builder squirrel.StatementBuilderType) Sqlizer {
+		return insertReturning(builder, tbl, cols, vals)
+	})
+}
+
+// insertReturning builds INSERT statement that returns the inserted row
+func insertReturning(builder squirrel.StatementBuilderType, tbl string, cols []string, vals []any) Sqlizer {
+	if len(cols) == 0 {
+		// all columns take their defaults
+		return squirrel.Expr(fmt.Sprintf("INSERT INTO %s DEFAULT VALUES RETURNING *", tbl))
+	}
+
+	return builder.Insert(tbl).Columns(cols...).Values(vals...).Suffix("RETURNING *")
+}
+
+// createMapping returns columns and values that Create inserts into table tbl for entity
+func (a *Accessor) createMapping(
+	entity any, s *EntityMappingSchema,
+	baseColValueMap map[string]reflect.Value,
+	tracker UpdateTracker,
+	tbl string,
+	idFields ...string,
+) ([]string, []any, error) {
 	var err error
 
 	idColumns := []string{}
@@ -329,13 +489,13 @@ func (a *Accessor) create(
 			// default id column does not exist, continue insertion without it
 			idColumns, colValueMap, err = a.getMapping(entity)
 			if err != nil {
-				return err
+				return nil, nil, err
 			}
 		}
 	} else {
 		idColumns, colValueMap, err = a.getMapping(entity, idFields...)
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
 	}
 
@@ -349,41 +509,180 @@ func (a *Accessor) create(
 		colLookup[col] = field
 	}
 
-	return a.SqlizerGet(ctx, entity, func(builder squirrel.StatementBuilderType) Sqlizer {
-		b := builder.Insert(tbl)
-		cols, vals := buildCreateMapping(idColumns, colLookup, baseColValueMap, colValueMap)
-		return b.Columns(cols...).Values(vals...).Suffix("RETURNING *")
-	})
+	var colsChanged map[string]bool
+	if tracker != nil {
+		colsChanged = map[string]bool{}
+		for _, col := range tracker.ColumnsChanged(tbl) {
+			colsChanged[col] = true
+		}
+	}
+
+	cols, vals, err := buildCreateMapping(
+		idColumns,
+		s.keyColumns(idColumns),
+		colLookup,
+		baseColValueMap,
+		colValueMap,
+		s.EntityType,
+		tbl,
+		colsChanged,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cols, vals = setDiscriminator(s, cols, vals)
+	return cols, vals, nil
 }
 
 func buildCreateMapping(
 	idColumns []string,
+	keyColumns []string,
 	colFieldLookup map[string]string,
 	baseColValueMap map[string]reflect.Value,
 	colValueMap map[string]reflect.Value,
-) ([]string, []any) {
+	typ reflect.Type,
+	tbl string,
+	colsChanged map[string]bool,
+) ([]string, []any, error) {
 	cols := []string{}
 	vals := []any{}
 
-	for k, v := range baseColValueMap {
-		if stringInSlice(k, idColumns) {
+	skipped := nonInsertableColumns(typ)
+	omitempty := omitemptyColumns(typ)
+
+	// key columns of the table take values of ID columns of the root table
+	for i, k := range idColumns {
+		if v, ok := baseColValueMap[k]; ok && !skipped[k] {
 			if !v.IsZero() {
-				cols = append(cols, k)
-				vals = append(vals, getDriverValue(v))
+				val, err := keyDriverValue(typ, tbl, keyColumns[i], v)
+				if err != nil {
+					return nil, nil, err
+				}
+
+				cols = append(cols, keyColumns[i])
+				vals = append(vals, val)
 			}
 		}
 	}
 
 	for k, v := range colValueMap {
-		if !stringInSlice(k, idColumns) {
+		if !stringInSlice(k, idColumns) && !skipped[k] {
+			// leave column to its default
+			if omitempty[k] && v.IsZero() || colsChanged != nil && !colsChanged[k] {
+				continue
+			}
+
 			if _, ok := colFieldLookup[k]; ok {
+				val, err := driverValue(typ, tbl, k, v)
+				if err != nil {
+					return nil, nil, err
+				}
+
 				cols = append(cols, k)
-				vals = append(vals, getDriverValue(v))
+				vals = append(vals, val)
+			}
+		}
+	}
+
+	return cols, vals, nil
+}
+
+// copyEntity makes a deep copy of entity value, values of struct types with unexported
+// fields (e.g. time.Time) are copied as a whole. Values held by interfaces, e.g. in
+// map[string]any of JSON columns, are copied as well.
+func copyEntity(v any) any {
+	if v == nil {
+		return nil
+	}
+	return copyValue(reflect.ValueOf(v)).Interface()
+}
+
+func copyValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		c := reflect.New(v.Type().Elem())
+		c.Elem().Set(copyValue(v.Elem()))
+		return c
+
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := 0; i < c.NumField(); i++ {
+			if c.Field(i).CanSet() {
+				c.Field(i).Set(copyValue(v.Field(i)))
 			}
 		}
+		return c
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(copyValue(v.Index(i)))
+		}
+		return c
+
+	case reflect.Map:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(iter.Key(), copyValue(iter.Value()))
+		}
+		return c
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		c := reflect.New(v.Type()).Elem()
+		c.Set(copyValue(v.Elem()))
+		return c
+	}
+
+	return v
+}
+
+// mergeColumns sets fields of entity mapped to columns of the table of m with values in src
+func (a *Accessor) mergeColumns(entity any, src any, m *EntityMappingSchema) {
+	dstColValueMap := a.fieldMap(reflect.ValueOf(entity))
+	srcColValueMap := a.fieldMap(reflect.ValueOf(src))
+
+	for _, col := range m.Columns {
+		v, ok := dstColValueMap[col]
+		from, found := srcColValueMap[col]
+		if ok && found && v.CanSet() && from.Type().AssignableTo(v.Type()) {
+			v.Set(from)
+		}
+	}
+}
+
+// setIdValues sets ID fields of entity with values in colValueMap, if entity is addressable
+func (a *Accessor) setIdValues(entity any, colValueMap map[string]reflect.Value, idFields ...string) {
+	if len(idFields) == 0 {
+		idFields = []string{"Id"}
+	}
+
+	idColumns, entityColValueMap, err := a.getMapping(entity, idFields...)
+	if err != nil {
+		return
 	}
 
-	return cols, vals
+	for _, col := range idColumns {
+		v, ok := entityColValueMap[col]
+		src, found := colValueMap[col]
+		if ok && found && v.CanSet() && src.Type().AssignableTo(v.Type()) {
+			v.Set(src)
+		}
+	}
 }
 
 func createPointerValue(original reflect.Value) reflect.Value {
@@ -396,20 +695,19 @@ func createPointerValue(original reflect.Value) reflect.Value {
 	return pointerValue
 }
 
-func (a *Accessor) createComposite(ctx context.Context, s *EntityMappingSchema, idFields ...string) error {
+func (a *Accessor) createComposite(
+	ctx context.Context,
+	s *EntityMappingSchema,
+	tracker UpdateTracker,
+	idFields ...string,
+) error {
 	var baseColValueMap map[string]reflect.Value
 
 	var err error
 	for i, mm := range s.Schemas() {
 		if i < len(s.Schemas())-1 {
-			var pEntity reflect.Value
-			c, err := cpy.Anything(mm.Entity)
-			if err != nil {
-				return err
-			}
-
-			pEntity = createPointerValue(reflect.Indirect(reflect.ValueOf(c)))
-			err = a.create(ctx, pEntity.Interface(), mm, baseColValueMap, mm.TableName, idFields...)
+			pEntity := createPointerValue(reflect.Indirect(reflect.ValueOf(copyEntity(mm.Entity))))
+			err = a.create(ctx, pEntity.Interface(), mm, baseColValueMap, tracker, mm.TableName, idFields...)
 			if err != nil {
 				return err
 			}
@@ -422,7 +720,11 @@ func (a *Accessor) createComposite(ctx context.Context, s *EntityMappingSchema,
 				}
 			}
 		} else {
-			err = a.create(ctx, mm.Entity, mm, baseColValueMap, mm.TableName, idFields...)
+			// tables joined on key columns of different names do not return ID columns
+			// of the root table, carry over values generated for the root table
+			a.setIdValues(mm.Entity, baseColValueMap, idFields...)
+
+			err = a.create(ctx, mm.Entity, mm, baseColValueMap, tracker, mm.TableName, idFields...)
 		}
 
 		if err != nil {
@@ -433,6 +735,9 @@ func (a *Accessor) createComposite(ctx context.Context, s *EntityMappingSchema,
 	return nil
 }
 
+// Read reads entity by its primary key, entities of types registered by RegisterCache
+// are served from the cache when present.
+//
 // Usage example
 /*
    city2 := struct {
@@ -446,6 +751,18 @@ func (a *Accessor) createComposite(ctx context.Context, s *EntityMappingSchema,
    err = accessor.Read(context.Background(), &city2, "city")
 */
 func (a *Accessor) Read(ctx context.Context, entity any, tbl string, idFields ...string) error {
+	if a.readCached(entity, tbl, idFields...) {
+		return nil
+	}
+
+	err := a.readEntity(ctx, entity, tbl, idFields...)
+	if err == nil {
+		a.cacheEntity(entity, tbl)
+	}
+	return err
+}
+
+func (a *Accessor) readEntity(ctx context.Context, entity any, tbl string, idFields ...string) error {
 	if len(idFields) == 0 {
 		idFields = []string{"Id"}
 	}
@@ -465,14 +782,18 @@ func (a *Accessor) Read(ctx context.Context, entity any, tbl string, idFields ..
 	}
 
 	colValueMap = removeNestedCols(colValueMap)
-	return a.SqlizerGet(ctx, entity, func(builder squirrel.StatementBuilderType) Sqlizer {
-		eq := squirrel.Eq{}
-		for k, v := range colValueMap {
-			if stringInSlice(k, idColumns) {
-				eq[k] = getDriverValue(v)
+
+	eq := squirrel.Eq{}
+	for k, v := range colValueMap {
+		if stringInSlice(k, idColumns) {
+			if eq[k], err = keyLookupValue(s.EntityType, tbl, k, v); err != nil {
+				return err
 			}
 		}
-		return builder.Select("*").From(tbl).Where(eq)
+	}
+
+	return a.SqlizerGet(WithTable(ctx, tbl), entity, func(builder squirrel.StatementBuilderType) Sqlizer {
+		return builder.Select("*").From(tbl).Where(withDiscriminator(s, eq))
 	})
 }
 
@@ -483,18 +804,22 @@ func (a *Accessor) readComposite(ctx context.Context, s *EntityMappingSchema, id
 	}
 
 	tables := s.Tables()
-	return a.SqlizerGet(ctx, s.Entity, func(builder squirrel.StatementBuilderType) Sqlizer {
-		eq := squirrel.Eq{}
-		for k, v := range colValueMap {
-			if stringInSlice(k, idColumns) {
-				eq[fmt.Sprintf("%s.%s", tables[0], k)] = getDriverValue(v)
+
+	eq := squirrel.Eq{}
+	for k, v := range colValueMap {
+		if stringInSlice(k, idColumns) {
+			if eq[fmt.Sprintf("%s.%s", tables[0], k)], err = keyLookupValue(s.EntityType, s.TableName, k, v); err != nil {
+				return err
 			}
 		}
+	}
+
+	return a.SqlizerGet(WithTable(ctx, s.TableName), s.Entity, func(builder squirrel.StatementBuilderType) Sqlizer {
 		return builder.
 			Select(s.GetColumnSelectString()).
 			From(tables[0]).
 			Join(s.GetTableJoinString(idColumns...)).
-			Where(eq)
+			Where(withDiscriminator(s, eq))
 	})
 }
 
@@ -520,6 +845,67 @@ func (a *Accessor) readComposite(ctx context.Context, s *EntityMappingSchema, id
    result, err = accessor.Update(context.Background(), ppp, "person", "FirstName", "LastName")
 */
 func (a *Accessor) Update(ctx context.Context, entity any, tbl string, idFields ...string) (sql.Result, error) {
+	if cols := auditedColumns(entity); len(cols) > 0 {
+		result, err := a.auditedUpdate(ctx, cols, entity, tbl, false, idFields...)
+		if err == nil {
+			a.retrack(entity)
+			a.invalidateCache(entity, tbl, idFields...)
+		}
+		return result, err
+	}
+
+	result, err := a.updateEntity(ctx, entity, tbl, false, idFields...)
+	if err == nil {
+		a.retrack(entity)
+		a.invalidateCache(entity, tbl, idFields...)
+	}
+	return result, err
+}
+
+// UpdateReturning works as Update, it also scans rows returned by UPDATE ... RETURNING *
+// back into entity, so that values computed by database (e.g. columns set by triggers or
+// generated columns) are reflected in entity without a read-back. For composite entities,
+// the row returned by each updated table is merged back into entity, tables without
+// changes are not refreshed. It returns sql.ErrNoRows if entity does not exist, and an error
+// if ID fields match more than one row, the update is not committed then.
+//
+// Usage example:
+/*
+   p := &PersonWithUpdateTracker{}
+   p.Id = 1000
+   p.SetFirstName("foo")
+
+   // full_name is a generated column, updated_at is set by trigger
+   err := accessor.UpdateReturning(context.Background(), p, "person")
+*/
+func (a *Accessor) UpdateReturning(ctx context.Context, entity any, tbl string, idFields ...string) error {
+	if reflect.TypeOf(entity).Kind() != reflect.Ptr {
+		return errors.New("must pass a pointer, not a value, to UpdateReturning")
+	}
+
+	var err error
+	if cols := auditedColumns(entity); len(cols) > 0 {
+		_, err = a.auditedUpdate(ctx, cols, entity, tbl, true, idFields...)
+	} else {
+		_, err = a.updateEntity(ctx, entity, tbl, true, idFields...)
+	}
+
+	if err == nil {
+		a.retrack(entity)
+		a.invalidateCache(entity, tbl, idFields...)
+	}
+	return err
+}
+
+// updateEntity updates entity, rows returned by the update are scanned back into entity
+// if returning is set
+func (a *Accessor) updateEntity(
+	ctx context.Context,
+	entity any,
+	tbl string,
+	returning bool,
+	idFields ...string,
+) (sql.Result, error) {
 	if len(idFields) == 0 {
 		idFields = []string{"Id"}
 	}
@@ -529,8 +915,23 @@ func (a *Accessor) Update(ctx context.Context, entity any, tbl string, idFields
 		return nil, err
 	}
 
+	tracker := a.updateTracker(entity, s)
+	if tracker != nil && !hasTrackedChanges(tracker, s) {
+		return noopSqlResult{}, nil
+	}
+
+	if touched := a.touchUpdateTimestamps(entity); len(touched) > 0 {
+		markChanged(tracker, s, touched)
+
+		// refresh schema to pick up values in embedded types
+		s, err = EntitySchema(entity, reflect.TypeOf(entity), tbl)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if len(s.BaseMappings) > 0 {
-		return a.updateComposite(ctx, s, entity.(UpdateTracker), idFields...)
+		return a.updateComposite(ctx, s, tracker, returning, idFields...)
 	}
 
 	idColumns, colValueMap, err := a.getMapping(entity, idFields...)
@@ -538,8 +939,6 @@ func (a *Accessor) Update(ctx context.Context, entity any, tbl string, idFields
 		return nil, errors.New("missing ID columns")
 	}
 
-	tracker, _ := entity.(UpdateTracker)
-
 	if tracker != nil && len(tracker.ColumnsChanged(s.TableName)) == 0 {
 		return noopSqlResult{}, nil
 	}
@@ -549,14 +948,21 @@ func (a *Accessor) Update(ctx context.Context, entity any, tbl string, idFields
 		colFieldLookup[col] = field
 	}
 
+	var dest any
+	if returning {
+		dest = entity
+	}
+
 	return a.execUpdate(
 		ctx,
 		idColumns,
 		colFieldLookup,
 		colValueMap,
 		colValueMap,
-		s.TableName,
+		s,
 		tracker,
+		s.EntityType,
+		dest,
 	)
 }
 
@@ -566,47 +972,150 @@ func (a *Accessor) execUpdate(
 	colFieldLookup map[string]string,
 	baseColValueMap map[string]reflect.Value,
 	colValueMap map[string]reflect.Value,
-	tbl string,
+	m *EntityMappingSchema,
 	tracker UpdateTracker,
+	typ reflect.Type,
+	dest any,
 ) (sql.Result, error) {
+	tbl := m.TableName
 	colValueMap = removeNestedCols(colValueMap)
 
-	return a.SqlizerExec(ctx, func(builder squirrel.StatementBuilderType) Sqlizer {
-		eq := squirrel.Eq{}
-		for k, v := range baseColValueMap {
-			if stringInSlice(k, idColumns) {
-				eq[k] = getDriverValue(v)
-			}
+	var colsChanged []string
+	if tracker != nil {
+		colsChanged = tracker.ColumnsChanged(tbl)
+	}
+
+	skipped := nonUpdatableColumns(typ)
+
+	sets := map[string]any{}
+	for k, v := range colValueMap {
+		if stringInSlice(k, idColumns) || colFieldLookup[k] == "" || skipped[k] {
+			continue
 		}
 
-		q := builder.Update(tbl)
-		if tracker != nil {
-			colsChanged := tracker.ColumnsChanged(tbl)
+		// perform full update if there is no tracker
+		if tracker != nil && !stringInSlice(k, colsChanged) {
+			continue
+		}
+
+		// apply partial updates to json column if there are registered patches
+		if patcher, ok := tracker.(JsonPatchTracker); ok && jsonColumns(typ)[k] {
+			if patches := patcher.JsonPatches(tbl, k); len(patches) > 0 {
+				if _, ok := encryptedColumns(typ)[k]; ok {
+					return nil, fmt.Errorf("encrypted json column %s can not be updated partially", k)
+				}
 
-			for k, v := range colValueMap {
-				if !stringInSlice(k, idColumns) && colFieldLookup[k] != "" && stringInSlice(k, colsChanged) {
-					q = q.Set(k, getDriverValue(v))
+				expr, err := jsonPatchExpr(a.Db.DriverName(), k, patches)
+				if err != nil {
+					return nil, err
 				}
+
+				sets[k] = expr
+				continue
 			}
-		} else {
-			// perform full update
-			for k, v := range colValueMap {
-				if !stringInSlice(k, idColumns) && colFieldLookup[k] != "" {
-					q = q.Set(k, getDriverValue(v))
+		}
+
+		val, err := driverValue(typ, tbl, k, v)
+		if err != nil {
+			return nil, err
+		}
+		sets[k] = val
+	}
+
+	if len(sets) == 0 {
+		return noopSqlResult{}, nil
+	}
+
+	eq := squirrel.Eq{}
+	for k, v := range baseColValueMap {
+		if stringInSlice(k, idColumns) {
+			var err error
+			if eq[k], err = keyLookupValue(typ, tbl, k, v); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if dest != nil {
+		rowsAffected, err := a.execUpdateReturning(
+			ctx,
+			dest,
+			tbl,
+			squirrel.StatementBuilder.Update(tbl).SetMap(sets).Where(withDiscriminator(m, eq)),
+		)
+		if err != nil {
+			return nil, err
+		}
+		return bulkSqlResult{rowsAffected: rowsAffected}, nil
+	}
+
+	return a.SqlizerExec(ctx, func(builder squirrel.StatementBuilderType) Sqlizer {
+		return builder.Update(tbl).SetMap(sets).Where(withDiscriminator(m, eq))
+	})
+}
+
+// execUpdateReturning executes update with RETURNING * and scans the updated row of table
+// tbl back into dest, it returns the number of rows returned. Keys must match a single row,
+// the update is rolled back if more rows are returned, unless it runs in a transaction of
+// the caller, which is responsible to roll back then.
+func (a *Accessor) execUpdateReturning(
+	ctx context.Context,
+	dest any,
+	tbl string,
+	update squirrel.UpdateBuilder,
+) (int64, error) {
+	q, args, err := update.Suffix("RETURNING *").ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var rowsAffected int64
+	err = a.withinTx(ctx, func(ta *Accessor) error {
+		rows, err := ta.queryx(ctx, ta.Db.Rebind(q), args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		// all rows are consumed, so that the count is complete
+		for rows.Next() {
+			rowsAffected++
+			if rowsAffected == 1 {
+				if err := ta.structScan(rows, dest, tbl); err != nil {
+					return err
 				}
 			}
 		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
 
-		return q.Where(eq)
+		switch {
+		case rowsAffected == 0:
+			return sql.ErrNoRows
+		case rowsAffected > 1:
+			return fmt.Errorf("update of table %s returned %d rows, ID fields must match a single row", tbl, rowsAffected)
+		}
+		return nil
 	})
+
+	return rowsAffected, err
 }
 
 func (a *Accessor) updateComposite(
 	ctx context.Context,
 	s *EntityMappingSchema,
 	tracker UpdateTracker,
+	returning bool,
 	idFields ...string,
 ) (sql.Result, error) {
+	// rows of other types sharing the table are left alone
+	if ok, err := a.isOfType(ctx, s, idFields...); err != nil {
+		return nil, err
+	} else if !ok {
+		return noopSqlResult{}, nil
+	}
+
 	var baseColValueMap map[string]reflect.Value
 	var idColumns []string
 
@@ -614,20 +1123,16 @@ func (a *Accessor) updateComposite(
 
 	for i, m := range s.Schemas() {
 		if i < len(s.Schemas())-1 {
-			var pEntity reflect.Value
-			c, err := cpy.Anything(m.Entity)
+			pEntity := createPointerValue(reflect.Indirect(reflect.ValueOf(copyEntity(m.Entity))))
+
+			ids, colValueMap, err := a.getMapping(pEntity.Interface(), idFields...)
 			if err != nil {
 				return nil, err
 			}
 
-			pEntity = createPointerValue(reflect.Indirect(reflect.ValueOf(c)))
-
 			// capture possible returned auto-increment id values
 			if i == 0 {
-				idColumns, baseColValueMap, err = a.getMapping(pEntity.Interface(), idFields...)
-				if err != nil {
-					return nil, err
-				}
+				idColumns, baseColValueMap = ids, colValueMap
 			}
 
 			if tracker != nil && len(tracker.ColumnsChanged(m.TableName)) == 0 {
@@ -639,18 +1144,31 @@ func (a *Accessor) updateComposite(
 				colFieldLookup[col] = field
 			}
 
+			var dest any
+			if returning {
+				dest = pEntity.Interface()
+			}
+
+			keyColumns := m.keyColumns(idColumns)
 			_, err = a.execUpdate(
 				ctx,
-				idColumns,
+				keyColumns,
 				colFieldLookup,
-				baseColValueMap,
-				baseColValueMap,
-				m.TableName,
+				tableKeyValues(baseColValueMap, idColumns, keyColumns),
+				colValueMap,
+				m,
 				tracker,
+				s.EntityType,
+				dest,
 			)
 			if err != nil {
 				return nil, err
 			}
+
+			// merge row returned by the table back into entity
+			if returning {
+				a.mergeColumns(s.Entity, dest, m)
+			}
 		} else {
 			idColumns, colValueMap, err := a.getMapping(m.Entity, idFields...)
 			if err != nil {
@@ -666,14 +1184,22 @@ func (a *Accessor) updateComposite(
 				colFieldLookup[col] = field
 			}
 
+			var dest any
+			if returning {
+				dest = m.Entity
+			}
+
+			keyColumns := m.keyColumns(idColumns)
 			result, err = a.execUpdate(
 				ctx,
-				idColumns,
+				keyColumns,
 				colFieldLookup,
-				baseColValueMap,
+				tableKeyValues(baseColValueMap, idColumns, keyColumns),
 				colValueMap,
-				m.TableName,
+				m,
 				tracker,
+				s.EntityType,
+				dest,
 			)
 			if err != nil {
 				return nil, err
@@ -700,9 +1226,25 @@ func (a *Accessor) updateComposite(
    result, err := accessor.Delete(context.Background(), p, "person", "FirstName", "LastName")
 */
 func (a *Accessor) Delete(ctx context.Context, entity any, tbl string, idFields ...string) (sql.Result, error) {
+	var result sql.Result
+	var err error
+	if cols := auditedColumns(entity); len(cols) > 0 {
+		result, err = a.auditedDelete(ctx, cols, entity, tbl, idFields...)
+	} else {
+		result, err = a.deleteEntity(ctx, entity, tbl, idFields...)
+	}
+
+	if err == nil {
+		a.Untrack(entity)
+		a.invalidateCache(entity, tbl, idFields...)
+	}
+	return result, err
+}
+
+func (a *Accessor) deleteEntity(ctx context.Context, entity any, tbl string, idFields ...string) (sql.Result, error) {
 	// if entity is intended to read back before delete
 	if reflect.TypeOf(entity).Kind() == reflect.Ptr {
-		_ = a.Read(ctx, entity, tbl, idFields...)
+		_ = a.readEntity(ctx, entity, tbl, idFields...)
 	}
 
 	if len(idFields) == 0 {
@@ -715,7 +1257,6 @@ func (a *Accessor) Delete(ctx context.Context, entity any, tbl string, idFields
 	}
 
 	if len(s.BaseMappings) > 0 {
-		// perhaps we can utilize "delete cascade"
 		return a.deleteComposite(ctx, s, idFields...)
 	}
 
@@ -724,54 +1265,92 @@ func (a *Accessor) Delete(ctx context.Context, entity any, tbl string, idFields
 		return nil, errors.New("missing ID columns")
 	}
 
-	return a.execDelete(ctx, colValueMap, tbl, idColumns)
+	return a.execDelete(ctx, colValueMap, s, idColumns)
 }
 
 func (a *Accessor) execDelete(
 	ctx context.Context,
 	colValueMap map[string]reflect.Value,
-	tbl string,
+	m *EntityMappingSchema,
 	idColumns []string,
 ) (sql.Result, error) {
 	colValueMap = removeNestedCols(colValueMap)
-	return a.SqlizerExec(ctx, func(builder squirrel.StatementBuilderType) Sqlizer {
-		eq := squirrel.Eq{}
-		for k, v := range colValueMap {
-			if stringInSlice(k, idColumns) {
-				eq[k] = getDriverValue(v)
+
+	eq := squirrel.Eq{}
+	for k, v := range colValueMap {
+		if stringInSlice(k, idColumns) {
+			var err error
+			if eq[k], err = keyLookupValue(m.EntityType, m.TableName, k, v); err != nil {
+				return nil, err
 			}
 		}
-		return builder.Delete(tbl).Where(eq)
+	}
+
+	return a.SqlizerExec(ctx, func(builder squirrel.StatementBuilderType) Sqlizer {
+		return builder.Delete(m.TableName).Where(withDiscriminator(m, eq))
 	})
 }
 
 func (a *Accessor) deleteComposite(ctx context.Context, s *EntityMappingSchema, idFields ...string) (sql.Result, error) {
-	var err error
-	var r sql.Result
-
 	schemas := s.Schemas()
+
+	// rows of other types sharing the table are left alone
+	if ok, err := a.isOfType(ctx, s, idFields...); err != nil {
+		return nil, err
+	} else if !ok {
+		return CompositeResult{RootTable: schemas[0].TableName, TableRowsAffected: map[string]int64{}}, nil
+	}
+
+	switch deleteStrategyOf(ctx, s.Entity) {
+	case DeleteCascade:
+		// rows in the other tables are deleted by ON DELETE CASCADE
+		schemas = schemas[:1]
+	case DeleteWithCte:
+		if supportsDataModifyingCte(a.Db.DriverName()) {
+			return a.deleteCompositeWithCte(ctx, s, idFields...)
+		}
+	}
+
+	result := CompositeResult{
+		RootTable:         schemas[0].TableName,
+		TableRowsAffected: map[string]int64{},
+	}
+
 	for i := len(schemas) - 1; i >= 0; i-- {
 		m := schemas[i]
 
-		var pEntity reflect.Value
-		c, err := cpy.Anything(m.Entity)
+		keyValueMap, keyColumns, err := a.tableKeys(m, idFields...)
 		if err != nil {
 			return nil, err
 		}
-		pEntity = createPointerValue(reflect.Indirect(reflect.ValueOf(c)))
 
-		idColumns, colValueMap, err := a.getMapping(pEntity.Interface(), idFields...)
+		r, err := a.execDelete(ctx, keyValueMap, m, keyColumns)
 		if err != nil {
-			return nil, errors.New("missing ID columns")
+			return nil, err
 		}
 
-		r, err = a.execDelete(ctx, colValueMap, m.TableName, idColumns)
+		n, err := r.RowsAffected()
 		if err != nil {
 			return nil, err
 		}
+		result.TableRowsAffected[m.TableName] = n
+	}
+
+	return result, nil
+}
+
+// tableKeys returns key columns of the table of m in the inheritance chain and their
+// values in the entity of m
+func (a *Accessor) tableKeys(m *EntityMappingSchema, idFields ...string) (map[string]reflect.Value, []string, error) {
+	pEntity := createPointerValue(reflect.Indirect(reflect.ValueOf(copyEntity(m.Entity))))
+
+	idColumns, colValueMap, err := a.getMapping(pEntity.Interface(), idFields...)
+	if err != nil {
+		return nil, nil, errors.New("missing ID columns")
 	}
 
-	return r, err
+	keyColumns := m.keyColumns(idColumns)
+	return tableKeyValues(colValueMap, idColumns, keyColumns), keyColumns, nil
 }
 
 func (a *Accessor) getMapping(entity any, idFields ...string) (idColumns []string, colValueMap map[string]reflect.Value, outErr error) {
@@ -785,17 +1364,54 @@ func (a *Accessor) getMapping(entity any, idFields ...string) (idColumns []strin
 		return nil, nil, errors.New("missing ID columns")
 	}
 
-	var mapper *reflectx.Mapper
+	// Note: fieldMap does not support the case when entity points to an embedded type
+	// column (tag name) -> reflect.Value mapping
+	colValueMap = a.fieldMap(reflect.ValueOf(entity))
+	return
+}
+
+// fieldMap works as mapper.FieldMap, except that it leaves nil pointers and maps as they
+// are, mapper.FieldMap allocates them, which makes nil pointers indistinguishable from
+// pointers to zero values. Columns of fields behind nil embedded pointers are not mapped.
+func (a *Accessor) fieldMap(v reflect.Value) map[string]reflect.Value {
+	v = reflect.Indirect(v)
+
+	colValueMap := map[string]reflect.Value{}
+	for col, fi := range a.mapper().TypeMap(v.Type()).Names {
+		if f, ok := fieldByIndexes(v, fi.Index); ok {
+			colValueMap[col] = f
+		}
+	}
+
+	return colValueMap
+}
+
+func fieldByIndexes(v reflect.Value, indexes []int) (reflect.Value, bool) {
+	for _, i := range indexes {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, false
+		}
+		v = v.Field(i)
+	}
+
+	return v, true
+}
+
+func (a *Accessor) mapper() *reflectx.Mapper {
 	if db, ok := a.Db.(*sqlx.DB); ok {
-		mapper = db.Mapper
+		return db.Mapper
 	} else if tx, ok := a.Db.(*sqlx.Tx); ok {
-		mapper = tx.Mapper
+		return tx.Mapper
 	}
 
-	// Note: mapper.FieldMap does not support the case when entity points to an embedded type
-	// column (tag name) -> reflect.Value mapping
-	colValueMap = mapper.FieldMap(reflect.ValueOf(entity))
-	return
+	return reflectx.NewMapperFunc("db", sqlx.NameMapper)
 }
 
 // Usage example:
@@ -841,12 +1457,7 @@ func (a *Accessor) NamedGet(ctx context.Context, dest any, query string, arg any
 		return err
 	}
 
-	defer rows.Close()
-	for rows.Next() {
-		return rows.StructScan(dest)
-	}
-
-	return sql.ErrNoRows
+	return a.scanOne(rows, dest, destTable(ctx, dest))
 }
 
 // Usage example
@@ -866,24 +1477,6 @@ func (a *Accessor) NamedSelect(ctx context.Context, dest any, query string, arg
 	var rows *sqlx.Rows
 	var err error
 
-	value := reflect.ValueOf(dest)
-
-	if value.Kind() != reflect.Ptr {
-		return errors.New("must pass a pointer, not a value, to StructScan destination")
-	}
-	if value.IsNil() {
-		return errors.New("nil pointer passed to StructScan destination")
-	}
-	direct := reflect.Indirect(value)
-
-	slice, err := baseType(value.Type(), reflect.Slice)
-	if err != nil {
-		return err
-	}
-	direct.SetLen(0)
-	isPtr := slice.Elem().Kind() == reflect.Ptr
-	base := reflectx.Deref(slice.Elem())
-
 	if db, ok := a.Db.(*sqlx.DB); ok {
 		rows, err = db.Unsafe().NamedQueryContext(ctx, query, arg)
 	} else if tx, ok := a.Db.(*sqlx.Tx); ok {
@@ -896,22 +1489,7 @@ func (a *Accessor) NamedSelect(ctx context.Context, dest any, query string, arg
 		return err
 	}
 
-	defer rows.Close()
-	for rows.Next() {
-		elem := reflect.New(base)
-		err := rows.StructScan(elem.Elem().Addr().Interface())
-		if err != nil {
-			return err
-		}
-
-		if isPtr {
-			direct.Set(reflect.Append(direct, elem))
-		} else {
-			direct.Set(reflect.Append(direct, reflect.Indirect(elem)))
-		}
-	}
-
-	return nil
+	return a.scanAll(rows, dest, destTable(ctx, dest))
 }
 
 func (a *Accessor) NamedExec(ctx context.Context, query string, arg any) (sql.Result, error) {
@@ -1007,9 +1585,7 @@ func (a *Accessor) SqlizerExec(
 		&m,
 		m.TableName(),
 		func(builder squirrel.SelectBuilder) accessor.Sqlizer {
-			return builder.Where(squirrel.Eq{
-				m.Employee.TableName() + "." + m.Employee.EntityFields().Company: "bar.com",
-			})
+			return builder.Where(ManagerCols.Company.Eq("bar.com"))
 		},
 	)
 */
@@ -1029,41 +1605,22 @@ func (a *Accessor) EntityGet(
 		return err
 	}
 
-	if len(s.BaseMappings) > 0 {
-		if len(idFields) == 0 {
-			idFields = []string{"Id"}
-		}
-
-		idColumns, _, err := a.getMapping(s.Entity, idFields...)
-		if err != nil {
-			return err
-		}
-
-		tables := s.Tables()
-		builder := squirrel.StatementBuilder.
-			Select(s.GetColumnSelectString()).
-			From(tables[0]).
-			Join(s.GetTableJoinString(idColumns...))
-
-		q, args, err := sqlizer(builder).ToSql()
-
-		if err != nil {
-			return err
-		}
-
-		return a.Get(ctx, dest, a.Db.Rebind(q), args...)
+	builder, err := a.entitySelectBuilder(s, s.GetColumnSelectString(), idFields...)
+	if err != nil {
+		return err
 	}
 
-	builder := squirrel.StatementBuilder.
-		Select(s.GetColumnSelectString()).
-		From(tbl)
-
 	q, args, err := sqlizer(builder).ToSql()
 	if err != nil {
 		return err
 	}
 
-	return a.Get(ctx, dest, a.Db.Rebind(q), args...)
+	if err = a.Get(WithTable(ctx, tbl), dest, a.Db.Rebind(q), args...); err != nil {
+		return err
+	}
+
+	a.cacheEntity(dest, tbl)
+	return nil
 }
 
 // For composite entity type, EntitySelect can help generate SQL table JOIN statement,
@@ -1076,9 +1633,7 @@ func (a *Accessor) EntityGet(
 		&mgrList,
 		m.TableName(),
 		func(builder squirrel.SelectBuilder) accessor.Sqlizer {
-			return builder.Where(squirrel.Eq{
-				m.Employee.TableName() + "." + m.Employee.EntityFields().Company: "bar.com",
-			})
+			return builder.Where(ManagerCols.Company.Eq("bar.com"))
 		},
 	)
 */
@@ -1111,6 +1666,27 @@ func (a *Accessor) EntitySelect(
 		return err
 	}
 
+	builder, err := a.entitySelectBuilder(s, s.GetColumnSelectString(), idFields...)
+	if err != nil {
+		return err
+	}
+
+	q, args, err := sqlizer(builder).ToSql()
+	if err != nil {
+		return err
+	}
+
+	return a.Select(WithTable(ctx, tbl), dest, a.Db.Rebind(q), args...)
+}
+
+// entitySelectBuilder starts a SELECT statement on the entity table, for composite
+// entity, tables in the inheritance chain are joined on ID columns. Rows of entity type
+// mapped by single-table inheritance are filtered by discriminator column
+func (a *Accessor) entitySelectBuilder(
+	s *EntityMappingSchema,
+	columns string,
+	idFields ...string,
+) (squirrel.SelectBuilder, error) {
 	if len(s.BaseMappings) > 0 {
 		if len(idFields) == 0 {
 			idFields = []string{"Id"}
@@ -1118,38 +1694,25 @@ func (a *Accessor) EntitySelect(
 
 		idColumns, _, err := a.getMapping(s.Entity, idFields...)
 		if err != nil {
-			return err
+			return squirrel.SelectBuilder{}, err
 		}
 
 		tables := s.Tables()
-		builder := squirrel.StatementBuilder.
-			Select(s.GetColumnSelectString()).
+		return squirrel.StatementBuilder.
+			Select(columns).
 			From(tables[0]).
-			Join(s.GetTableJoinString(idColumns...))
-
-		q, args, err := sqlizer(builder).ToSql()
-
-		if err != nil {
-			return err
-		}
-
-		return a.Select(ctx, dest, a.Db.Rebind(q), args...)
-	}
-
-	builder := squirrel.StatementBuilder.
-		Select(s.GetColumnSelectString()).
-		From(tbl)
-
-	q, args, err := sqlizer(builder).ToSql()
-
-	if err != nil {
-		return err
+			Join(s.GetTableJoinString(idColumns...)).
+			Where(withDiscriminator(s, nil)), nil
 	}
 
-	return a.Select(ctx, dest, a.Db.Rebind(q), args...)
+	return squirrel.StatementBuilder.
+		Select(columns).
+		From(s.TableName).
+		Where(withDiscriminator(s, nil)), nil
 }
 
-// ExecTx uses annonymous execution function to achieve crash-safe and implicit transaction commission effect
+// ExecTx uses annonymous execution function to achieve crash-safe and implicit transaction commission effect,
+// the transaction accessor is also carried by ctx passed to execFn, see FromContext
 // Usage example
 /*
    err := ExecTx(context.Background(), s.Db, &sql.TxOptions{}, func(ctx context.Context, accessor *Accessor) error {
@@ -1185,14 +1748,21 @@ func ExecTx(
 		}
 	}()
 
-	outErr = execFn(ctx, New(tx))
+	a := New(tx)
+	a.cacheTx = newCacheTx()
+	a.txDb = db
+	outErr = execFn(withAccessor(ctx, a), a)
+	if outErr == nil && a.rollbackOnly {
+		outErr = ErrRollbackOnly
+	}
+
 	if outErr != nil {
 		err := tx.Rollback()
 		if err != nil {
 			outErr = fmt.Errorf("failed to rollback on error: %w", outErr)
 		}
-	} else {
-		outErr = tx.Commit()
+	} else if outErr = tx.Commit(); outErr == nil {
+		a.cacheTx.commit()
 	}
 
 	return
@@ -1213,6 +1783,13 @@ func entityType(typ reflect.Type) reflect.Type {
 	if typ.NumField() > 0 {
 		field := typ.Field(0)
 		if field.Anonymous {
+			// subtype of single-table inheritance may not declare columns on its own
+			if _, attrs, err := fieldMappedColumnWithAttributes(field, "db"); err == nil {
+				if _, ok := attrs[attrDiscriminator]; ok {
+					return typ
+				}
+			}
+
 			typ = reflectx.Deref(field.Type)
 			if typ.Kind() == reflect.Struct {
 				return entityType(typ)
@@ -1260,11 +1837,52 @@ func EntitySchema(v any, typ reflect.Type, tableName string) (*EntityMappingSche
 				return nil, fmt.Errorf("embedded type %s in type %s should have empty column name", ft.Name(), typ.Name())
 			}
 
-			if baseTable, ok := attrs["table"]; ok {
+			if discriminator, ok := attrs[attrDiscriminator]; ok {
+				if _, ok := attrs["table"]; ok {
+					return nil, fmt.Errorf("embedded type %s in type %s should not have both table and discriminator attributes", ft.Name(), typ.Name())
+				}
+				if _, ok := attrs[attrJoin]; ok {
+					return nil, fmt.Errorf("embedded type %s in type %s should not have join attribute without table attribute", ft.Name(), typ.Name())
+				}
+
+				value, ok := attrs[attrDiscriminatorValue]
+				if discriminator == "" || !ok || value == "" {
+					return nil, fmt.Errorf("embedded type %s in type %s should have discriminator column and value", ft.Name(), typ.Name())
+				}
+
+				// base type shares the same table, merge its mapping
+				fieldValue := reflect.Indirect(reflect.ValueOf(v)).Field(i)
+				baseSchema, err := EntitySchema(fieldValue.Interface(), ft, tableName)
+				if err != nil {
+					return nil, err
+				}
+
+				for field, col := range baseSchema.Columns {
+					m.Columns[field] = col
+				}
+				m.BaseMappings = append(m.BaseMappings, baseSchema.BaseMappings...)
+				m.JoinColumns = baseSchema.JoinColumns
+				m.Discriminator, m.DiscriminatorValue = discriminator, value
+			} else if baseTable, ok := attrs["table"]; ok {
 				if baseTable == tableName {
 					return nil, fmt.Errorf("embedded type %s in type %s should not have the same table mapping", ft.Name(), typ.Name())
 				}
 
+				if join, ok := attrs[attrJoin]; ok {
+					joinColumns, err := parseJoinColumns(join)
+					if err != nil {
+						return nil, fmt.Errorf("embedded type %s in type %s: %w", ft.Name(), typ.Name(), err)
+					}
+
+					// key columns of all tables share ciphertext of encrypted ID columns
+					for col := range joinColumns {
+						if _, ok := encryptedColumns(ft)[col]; ok {
+							return nil, fmt.Errorf("embedded type %s in type %s should not join on encrypted column %s", ft.Name(), typ.Name(), col)
+						}
+					}
+					m.JoinColumns = joinColumns
+				}
+
 				fieldValue := reflect.Indirect(reflect.ValueOf(v)).Field(i)
 				baseSchema, err := EntitySchema(fieldValue.Interface(), ft, baseTable)
 
@@ -1470,6 +2088,34 @@ func removeNestedCols(m map[string]reflect.Value) map[string]reflect.Value {
 	return ret
 }
 
+// driverValue works as getDriverValue, it also applies conversions declared by column
+// attributes in entity type, i.e. json marshaling and encryption. Values of encrypted
+// columns are bound to table tbl of entity type, or the base table of the column.
+func driverValue(entityType reflect.Type, tbl string, col string, v reflect.Value) (any, error) {
+	val, err := plainDriverValue(entityType, col, v)
+	if err != nil {
+		return nil, err
+	}
+
+	if deterministic, ok := encryptedColumns(entityType)[col]; ok {
+		return encryptValue(entityType, tbl, col, val, deterministic)
+	}
+	return val, nil
+}
+
+// plainDriverValue works as driverValue, without encryption
+func plainDriverValue(entityType reflect.Type, col string, v reflect.Value) (any, error) {
+	if jsonColumns(entityType)[col] {
+		val, err := jsonValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("marshal json column %s: %w", col, err)
+		}
+		return val, nil
+	}
+
+	return getDriverValue(v), nil
+}
+
 func getDriverValue(val reflect.Value) any {
 	var v any
 
@@ -1479,7 +2125,7 @@ func getDriverValue(val reflect.Value) any {
 		}
 
 		v = val.Elem().Interface()
-		if reflect.ValueOf(v).IsZero() {
+		if ZeroPointerAsNull && reflect.ValueOf(v).IsZero() {
 			return nil
 		}
 	} else {
@@ -1490,10 +2136,6 @@ func getDriverValue(val reflect.Value) any {
 		if valuer, ok := v.(driver.Valuer); ok {
 			val, err := valuer.Value()
 			if err == nil {
-				// deal with special nil value
-				if val == "<nil>" {
-					return nil
-				}
 				return val
 			}
 		}
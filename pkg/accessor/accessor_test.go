@@ -836,3 +836,51 @@ func (s *AccessorTestSuite) TestEmbeddedColumnMapping() {
 	col = Column(e, "GrandChildAttr")
 	req.Equal("grand_child_attr", col)
 }
+
+type copiedEntity struct {
+	Id        int
+	AddedAt   time.Time
+	UpdatedAt *time.Time
+	Tags      []string
+	Meta      map[string]any
+	Extra     any
+	Null      Null[string]
+	trackMap  map[string]bool
+}
+
+func TestCopyEntity(t *testing.T) {
+	req := require.New(t)
+
+	req.Nil(copyEntity(nil))
+
+	at := time.Date(2023, 1, 1, 8, 0, 0, 0, time.FixedZone("UTC+8", 8*3600))
+	e := &copiedEntity{
+		Id:        1,
+		AddedAt:   at,
+		UpdatedAt: &at,
+		Tags:      []string{"a"},
+		Meta:      map[string]any{"dims": map[string]any{"w": float64(1)}, "list": []any{"x"}},
+		Extra:     map[string]any{"k": "v"},
+		Null:      NewNull("foo"),
+		trackMap:  map[string]bool{"id": true},
+	}
+
+	c := copyEntity(e).(*copiedEntity)
+	req.Equal(e, c)
+	req.NotSame(e, c)
+
+	// structs with unexported fields are copied as a whole
+	req.Equal(at.Location(), c.AddedAt.Location())
+	req.Equal(e.trackMap, c.trackMap)
+
+	// nothing reachable through exported fields is shared
+	req.NotSame(e.UpdatedAt, c.UpdatedAt)
+	c.Tags[0] = "b"
+	c.Meta["dims"].(map[string]any)["w"] = float64(2)
+	c.Meta["list"].([]any)[0] = "y"
+	c.Extra.(map[string]any)["k"] = "w"
+
+	req.Equal([]string{"a"}, e.Tags)
+	req.Equal(map[string]any{"dims": map[string]any{"w": float64(1)}, "list": []any{"x"}}, e.Meta)
+	req.Equal(map[string]any{"k": "v"}, e.Extra)
+}
@@ -0,0 +1,200 @@
+package accessor
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// Number is the set of Go types that numeric aggregates can be scanned into
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// Count returns number of entity rows that satisfy the criteria given by sqlizer,
+// nil sqlizer counts all rows.
+//
+// entity is only used to determine entity type (can be a zero value). For composite
+// entity, the same JOIN as EntitySelect is generated, so that predicates on columns of
+// base tables can be used.
+//
+// Usage example:
+/*
+	n, err := a.Count(
+		context.Background(),
+		Manager{},
+		"manager",
+		func(builder squirrel.SelectBuilder) accessor.Sqlizer {
+			return builder.Where(ManagerCols.Company.Eq("bar.com"))
+		},
+	)
+*/
+func (a *Accessor) Count(
+	ctx context.Context,
+	entity any,
+	tbl string,
+	sqlizer func(builder squirrel.SelectBuilder) Sqlizer,
+	idFields ...string,
+) (int64, error) {
+	var n int64
+
+	err := a.aggregate(ctx, &n, entity, tbl, "COUNT(*)", sqlizer, idFields...)
+	return n, err
+}
+
+// Exists returns true if there is at least one entity row that satisfies the criteria
+// given by sqlizer
+//
+// Usage example:
+/*
+	ok, err := a.Exists(
+		context.Background(),
+		Person{},
+		"person",
+		func(builder squirrel.SelectBuilder) accessor.Sqlizer {
+			return builder.Where(PersonCols.Email.Eq("foo@test"))
+		},
+	)
+*/
+func (a *Accessor) Exists(
+	ctx context.Context,
+	entity any,
+	tbl string,
+	sqlizer func(builder squirrel.SelectBuilder) Sqlizer,
+	idFields ...string,
+) (bool, error) {
+	var one int
+
+	err := a.aggregate(ctx, &one, entity, tbl, "1", func(builder squirrel.SelectBuilder) Sqlizer {
+		builder = builder.Limit(1)
+		if sqlizer != nil {
+			return sqlizer(builder)
+		}
+		return builder
+	}, idFields...)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// Sum returns SUM of the column over entity rows that satisfy the criteria given by sqlizer,
+// zero is returned if no row matches.
+//
+// Usage example:
+/*
+	total, err := accessor.Sum[int](
+		context.Background(),
+		a,
+		Person{},
+		"person",
+		PersonCols.Age.String(),
+		nil,
+	)
+*/
+func Sum[T Number](
+	ctx context.Context,
+	a *Accessor,
+	entity any,
+	tbl string,
+	col string,
+	sqlizer func(builder squirrel.SelectBuilder) Sqlizer,
+	idFields ...string,
+) (T, error) {
+	v, _, err := numericAggregate[T](ctx, a, entity, tbl, "SUM", col, sqlizer, idFields...)
+	return v, err
+}
+
+// Min returns MIN of the column over entity rows that satisfy the criteria given by sqlizer,
+// the returned bool is false if no row matches.
+func Min[T Number](
+	ctx context.Context,
+	a *Accessor,
+	entity any,
+	tbl string,
+	col string,
+	sqlizer func(builder squirrel.SelectBuilder) Sqlizer,
+	idFields ...string,
+) (T, bool, error) {
+	return numericAggregate[T](ctx, a, entity, tbl, "MIN", col, sqlizer, idFields...)
+}
+
+// Max returns MAX of the column over entity rows that satisfy the criteria given by sqlizer,
+// the returned bool is false if no row matches.
+func Max[T Number](
+	ctx context.Context,
+	a *Accessor,
+	entity any,
+	tbl string,
+	col string,
+	sqlizer func(builder squirrel.SelectBuilder) Sqlizer,
+	idFields ...string,
+) (T, bool, error) {
+	return numericAggregate[T](ctx, a, entity, tbl, "MAX", col, sqlizer, idFields...)
+}
+
+func numericAggregate[T Number](
+	ctx context.Context,
+	a *Accessor,
+	entity any,
+	tbl string,
+	fn string,
+	col string,
+	sqlizer func(builder squirrel.SelectBuilder) Sqlizer,
+	idFields ...string,
+) (T, bool, error) {
+	var v *T
+
+	err := a.aggregate(ctx, &v, entity, tbl, fmt.Sprintf("%s(%s)", fn, col), sqlizer, idFields...)
+	if err != nil || v == nil {
+		var zero T
+		return zero, false, err
+	}
+
+	return *v, true, nil
+}
+
+func (a *Accessor) aggregate(
+	ctx context.Context,
+	dest any,
+	entity any,
+	tbl string,
+	selection string,
+	sqlizer func(builder squirrel.SelectBuilder) Sqlizer,
+	idFields ...string,
+) error {
+	if entity == nil {
+		return errors.New("missing entity")
+	}
+
+	s, err := EntitySchema(entity, reflect.TypeOf(entity), tbl)
+	if err != nil {
+		return err
+	}
+
+	builder, err := a.entitySelectBuilder(s, selection, idFields...)
+	if err != nil {
+		return err
+	}
+
+	var q string
+	var args []any
+	if sqlizer != nil {
+		q, args, err = sqlizer(builder).ToSql()
+	} else {
+		q, args, err = builder.ToSql()
+	}
+	if err != nil {
+		return err
+	}
+
+	return a.Get(ctx, dest, a.Db.Rebind(q), args...)
+}
@@ -0,0 +1,105 @@
+package accessor
+
+import (
+	"context"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+)
+
+type PersonWithAge struct {
+	FirstName string `db:"first_name"`
+	LastName  string `db:"last_name"`
+	Email     string `db:"email"`
+	Age       *int   `db:"age"`
+}
+
+func (s *AccessorTestSuite) TestAggregates() {
+	req := require.New(s.T())
+
+	a := New(s.Db)
+	ctx := context.Background()
+
+	n, err := a.Count(ctx, PersonWithAge{}, "person", nil)
+	req.NoError(err)
+	req.Equal(int64(2), n)
+
+	n, err = a.Count(ctx, &PersonWithAge{}, "person", func(builder squirrel.SelectBuilder) Sqlizer {
+		return builder.Where(squirrel.Eq{"first_name": "foo"})
+	})
+	req.NoError(err)
+	req.Equal(int64(1), n)
+
+	ok, err := a.Exists(ctx, PersonWithAge{}, "person", func(builder squirrel.SelectBuilder) Sqlizer {
+		return builder.Where(squirrel.Eq{"email": "bar@test"})
+	})
+	req.NoError(err)
+	req.True(ok)
+
+	ok, err = a.Exists(ctx, PersonWithAge{}, "person", func(builder squirrel.SelectBuilder) Sqlizer {
+		return builder.Where(squirrel.Eq{"email": "none@test"})
+	})
+	req.NoError(err)
+	req.False(ok)
+
+	sum, err := Sum[int](ctx, a, PersonWithAge{}, "person", "age", nil)
+	req.NoError(err)
+	req.Equal(50, sum)
+
+	fsum, err := Sum[float64](ctx, a, PersonWithAge{}, "person", "age", nil)
+	req.NoError(err)
+	req.Equal(50.0, fsum)
+
+	lowest, found, err := Min[int64](ctx, a, PersonWithAge{}, "person", "age", nil)
+	req.NoError(err)
+	req.True(found)
+	req.Equal(int64(20), lowest)
+
+	highest, found, err := Max[int](ctx, a, PersonWithAge{}, "person", "age", func(builder squirrel.SelectBuilder) Sqlizer {
+		return builder.Where(squirrel.Eq{"last_name": "test"})
+	})
+	req.NoError(err)
+	req.True(found)
+	req.Equal(30, highest)
+
+	// no row matches
+	sum, err = Sum[int](ctx, a, PersonWithAge{}, "person", "age", func(builder squirrel.SelectBuilder) Sqlizer {
+		return builder.Where(squirrel.Eq{"last_name": "none"})
+	})
+	req.NoError(err)
+	req.Equal(0, sum)
+
+	_, found, err = Max[int](ctx, a, PersonWithAge{}, "person", "age", func(builder squirrel.SelectBuilder) Sqlizer {
+		return builder.Where(squirrel.Eq{"last_name": "none"})
+	})
+	req.NoError(err)
+	req.False(found)
+}
+
+func (s *AccessorTestSuite) TestAggregatesComposite() {
+	req := require.New(s.T())
+
+	s.setupCompositeTables()
+	defer s.teardownCompositeTables()
+
+	a := New(s.Db)
+	ctx := context.Background()
+
+	// predicates on columns of base tables
+	n, err := a.Count(ctx, GrandChildEntity{}, "grand_child", func(builder squirrel.SelectBuilder) Sqlizer {
+		return builder.Where(squirrel.Eq{"child.child_attr": "red"}).Where(squirrel.NotEq{"base.name": "bar"})
+	})
+	req.NoError(err)
+	req.Equal(int64(1), n)
+
+	ok, err := a.Exists(ctx, GrandChildEntity{}, "grand_child", func(builder squirrel.SelectBuilder) Sqlizer {
+		return builder.Where(squirrel.Eq{"base.name": "baz", "grand_child.grand_child_attr": "pear"})
+	})
+	req.NoError(err)
+	req.True(ok)
+
+	highest, found, err := Max[int](ctx, a, GrandChildEntity{}, "grand_child", "base.id", nil)
+	req.NoError(err)
+	req.True(found)
+	req.Equal(3, highest)
+}
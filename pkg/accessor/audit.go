@@ -0,0 +1,390 @@
+package accessor
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx/reflectx"
+)
+
+const attrAudit = "audit"
+
+// AuditTable is the table audit records are written into, it is expected to have
+// the following columns
+/*
+	CREATE TABLE audit_log (
+		id         serial PRIMARY KEY,
+		table_name text,
+		entity_key text,
+		actor      text,
+		operation  text,
+		changes    text,
+		changed_at timestamp
+	);
+*/
+var AuditTable = "audit_log"
+
+type AuditOperation string
+
+const (
+	AuditCreate AuditOperation = "create"
+	AuditUpdate AuditOperation = "update"
+	AuditDelete AuditOperation = "delete"
+)
+
+// AuditChange records value of a column before and after an entity write, nil
+// values are omitted from the JSON diff
+type AuditChange struct {
+	Old any `json:"old,omitempty"`
+	New any `json:"new,omitempty"`
+}
+
+// AuditRecord maps a row of AuditTable. EntityKey and Changes are JSON objects,
+// primary key column -> value and column -> AuditChange respectively
+type AuditRecord struct {
+	Id        int64          `db:"id"`
+	TableName string         `db:"table_name"`
+	EntityKey string         `db:"entity_key"`
+	Actor     string         `db:"actor"`
+	Operation AuditOperation `db:"operation"`
+	Changes   string         `db:"changes"`
+	ChangedAt sql.NullTime   `db:"changed_at"`
+}
+
+type actorKey struct{}
+
+// WithActor returns a context that carries the actor to be recorded in audit records
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns actor carried by ctx, or empty string if there is none
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+
+var auditRegistry sync.Map
+
+// RegisterAudit opts entity types in auditing of all their mapped columns, including
+// columns inherited from base entities. Entity types can also opt in auditing of
+// individual columns by tagging them with audit attribute, e.g. `db:"salary,audit"`.
+//
+// Create, Update and Delete of audited entities write audit records in the same
+// transaction as the entity write. When accessor is not bound to a transaction,
+// one is started for the write. Set-based writes (UpdateWhere, DeleteWhere, their
+// returning variants and DeleteMany) of audited entities resolve keys of matched
+// entities and read them before and after the write, to write an audit record per
+// entity.
+//
+// Usage example:
+/*
+	accessor.RegisterAudit(Manager{})
+
+	ctx := accessor.WithActor(context.Background(), "jdoe")
+	_, err := a.Update(ctx, m, "manager")
+*/
+func RegisterAudit(entities ...any) {
+	for _, entity := range entities {
+		if typ := registeredEntityType(reflect.TypeOf(entity)); typ != nil {
+			auditRegistry.Store(typ, true)
+		}
+	}
+}
+
+// UnregisterAudit reverts RegisterAudit
+func UnregisterAudit(entities ...any) {
+	for _, entity := range entities {
+		if typ := registeredEntityType(reflect.TypeOf(entity)); typ != nil {
+			auditRegistry.Delete(typ)
+		}
+	}
+}
+
+func registeredEntityType(typ reflect.Type) reflect.Type {
+	typ = reflectx.Deref(typ)
+	if typ.Kind() != reflect.Struct {
+		return nil
+	}
+
+	// entity types wrapped by update trackers share registration with the entity type
+	return entityType(typ)
+}
+
+// auditedColumns returns columns of entity that should be audited, nil if entity is not audited
+func auditedColumns(entity any) []string {
+	if entity == nil {
+		return nil
+	}
+
+	typ := registeredEntityType(reflect.TypeOf(entity))
+	if typ == nil {
+		return nil
+	}
+
+	if _, ok := auditRegistry.Load(typ); ok {
+		return Columns(entity)
+	}
+
+	var cols []string
+	for col, attrs := range entityColumnAttributes(typ) {
+		if _, ok := attrs[attrAudit]; ok {
+			cols = append(cols, col)
+		}
+	}
+	sort.Strings(cols)
+
+	return cols
+}
+
+func (a *Accessor) auditedCreate(ctx context.Context, cols []string, entity any, tbl string, idFields ...string) error {
+	return a.withinTx(ctx, func(ta *Accessor) error {
+		if err := ta.createEntity(ctx, entity, tbl, idFields...); err != nil {
+			return err
+		}
+
+		return ta.writeAudit(ctx, AuditCreate, tbl, entity, cols, nil, ta.auditValues(entity, cols), idFields...)
+	})
+}
+
+func (a *Accessor) auditedUpdate(
+	ctx context.Context,
+	cols []string,
+	entity any,
+	tbl string,
+	returning bool,
+	idFields ...string,
+) (result sql.Result, err error) {
+	err = a.withinTx(ctx, func(ta *Accessor) error {
+		before, err := ta.readBack(ctx, entity, tbl, idFields...)
+		if err != nil {
+			return err
+		}
+
+		result, err = ta.updateEntity(ctx, entity, tbl, returning, idFields...)
+		if err != nil || before == nil {
+			return err
+		}
+
+		after, err := ta.readBack(ctx, entity, tbl, idFields...)
+		if err != nil || after == nil {
+			return err
+		}
+
+		return ta.writeAudit(ctx, AuditUpdate, tbl, entity, cols,
+			ta.auditValues(before, cols), ta.auditValues(after, cols), idFields...)
+	})
+
+	return result, err
+}
+
+func (a *Accessor) auditedDelete(
+	ctx context.Context,
+	cols []string,
+	entity any,
+	tbl string,
+	idFields ...string,
+) (result sql.Result, err error) {
+	err = a.withinTx(ctx, func(ta *Accessor) error {
+		before, err := ta.readBack(ctx, entity, tbl, idFields...)
+		if err != nil {
+			return err
+		}
+
+		result, err = ta.deleteEntity(ctx, entity, tbl, idFields...)
+		if err != nil || before == nil {
+			return err
+		}
+
+		return ta.writeAudit(ctx, AuditDelete, tbl, entity, cols, ta.auditValues(before, cols), nil, idFields...)
+	})
+
+	return result, err
+}
+
+// auditedByKeys runs write of entities of type in s with the given keys, which writes
+// them with set-based statements, and writes an audit record per entity from entities
+// read before and after write. write is run alone if cols is empty.
+func (a *Accessor) auditedByKeys(
+	ctx context.Context,
+	op AuditOperation,
+	cols []string,
+	s *EntityMappingSchema,
+	tbl string,
+	ids []any,
+	write func() error,
+	idFields ...string,
+) error {
+	if len(cols) == 0 {
+		return write()
+	}
+
+	keys, before, err := a.readKeyed(ctx, s, tbl, ids, idFields...)
+	if err != nil {
+		return err
+	}
+
+	if err = write(); err != nil {
+		return err
+	}
+
+	after := map[string]any{}
+	if op == AuditUpdate {
+		if _, after, err = a.readKeyed(ctx, s, tbl, ids, idFields...); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range keys {
+		entity := before[key]
+
+		var afterValues map[string]any
+		if e, ok := after[key]; ok {
+			entity = e
+			afterValues = a.auditValues(e, cols)
+		} else if op == AuditUpdate {
+			continue
+		}
+
+		err = a.writeAudit(ctx, op, tbl, entity, cols, a.auditValues(before[key], cols), afterValues, idFields...)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readKeyed reads entities of type in s by the given keys, it returns keys of entities
+// found in the order they are read and entities by their keys
+func (a *Accessor) readKeyed(
+	ctx context.Context,
+	s *EntityMappingSchema,
+	tbl string,
+	ids []any,
+	idFields ...string,
+) ([]string, map[string]any, error) {
+	if len(idFields) == 0 {
+		idFields = []string{"Id"}
+	}
+
+	slice := reflect.New(reflect.SliceOf(s.EntityType))
+	if err := a.ReadMany(ctx, slice.Interface(), tbl, ids, idFields...); err != nil {
+		return nil, nil, err
+	}
+
+	keys := []string{}
+	entities := map[string]any{}
+	for i := 0; i < slice.Elem().Len(); i++ {
+		entity := slice.Elem().Index(i).Addr().Interface()
+
+		key, err := entityKey(a, entity, idFields)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		keys = append(keys, keyString(key))
+		entities[keyString(key)] = entity
+	}
+
+	return keys, entities, nil
+}
+
+// readBack reads a copy of entity by its primary key, nil is returned if the row does not exist
+func (a *Accessor) readBack(ctx context.Context, entity any, tbl string, idFields ...string) (any, error) {
+	c := createPointerValue(reflect.Indirect(reflect.ValueOf(copyEntity(entity)))).Interface()
+
+	err := a.readEntity(ctx, c, tbl, idFields...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	return c, err
+}
+
+func (a *Accessor) auditValues(entity any, cols []string) map[string]any {
+	colValueMap := removeNestedCols(a.fieldMap(reflect.ValueOf(entity)))
+
+	values := map[string]any{}
+	for _, col := range cols {
+		if v, ok := colValueMap[col]; ok {
+			values[col] = getDriverValue(v)
+		}
+	}
+
+	return values
+}
+
+func (a *Accessor) writeAudit(
+	ctx context.Context,
+	op AuditOperation,
+	tbl string,
+	entity any,
+	cols []string,
+	before map[string]any,
+	after map[string]any,
+	idFields ...string,
+) error {
+	encrypted := encryptedColumns(reflect.TypeOf(entity))
+
+	changes := map[string]AuditChange{}
+	for _, col := range cols {
+		if reflect.DeepEqual(before[col], after[col]) {
+			continue
+		}
+
+		change := AuditChange{Old: before[col], New: after[col]}
+
+		// keep encrypted columns encrypted in audit records
+		if deterministic, ok := encrypted[col]; ok {
+			var err error
+			if change.Old, err = encryptValue(reflect.TypeOf(entity), tbl, col, change.Old, deterministic); err != nil {
+				return err
+			}
+			if change.New, err = encryptValue(reflect.TypeOf(entity), tbl, col, change.New, deterministic); err != nil {
+				return err
+			}
+		}
+
+		changes[col] = change
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	if len(idFields) == 0 {
+		idFields = []string{"Id"}
+	}
+
+	key := map[string]any{}
+	if idColumns, err := entityIdColumns(entity, idFields...); err == nil {
+		for col, v := range a.auditValues(entity, idColumns) {
+			key[col] = v
+		}
+	}
+
+	keyJson, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+
+	changesJson, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+
+	_, err = a.SqlizerExec(ctx, func(builder squirrel.StatementBuilderType) Sqlizer {
+		return builder.Insert(AuditTable).
+			Columns("table_name", "entity_key", "actor", "operation", "changes", "changed_at").
+			Values(tbl, string(keyJson), ActorFromContext(ctx), string(op), string(changesJson), now())
+	})
+
+	return err
+}
@@ -0,0 +1,256 @@
+package accessor
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+)
+
+type AuditedCity struct {
+	Id      int    `db:"id"`
+	Name    string `db:"name"`
+	ZipCode string `db:"zip_code,audit"`
+}
+
+func (s *AccessorTestSuite) TestAudit() {
+	req := require.New(s.T())
+
+	_ = s.Db.MustExec(`
+CREATE TABLE IF NOT EXISTS audit_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    table_name text,
+    entity_key text,
+    actor text,
+    operation text,
+    changes text,
+    changed_at timestamp
+);
+    `)
+	defer func() {
+		_ = s.Db.MustExec(`DROP TABLE IF EXISTS audit_log`)
+		s.setupTestDatabase()
+	}()
+
+	a := New(s.Db)
+	ctx := WithActor(context.Background(), "jdoe")
+
+	records := func() []AuditRecord {
+		var list []AuditRecord
+		req.NoError(a.Select(context.Background(), &list, "SELECT * FROM audit_log ORDER BY id"))
+		return list
+	}
+
+	changes := func(r AuditRecord) map[string]map[string]any {
+		m := map[string]map[string]any{}
+		req.NoError(json.Unmarshal([]byte(r.Changes), &m))
+		return m
+	}
+
+	// column level opt-in
+	city := AuditedCity{Name: "San Jose", ZipCode: "95120"}
+	req.NoError(a.Create(ctx, &city, "city"))
+	req.True(city.Id > 0)
+
+	list := records()
+	req.Equal(1, len(list))
+	req.Equal("city", list[0].TableName)
+	req.Equal("jdoe", list[0].Actor)
+	req.Equal(AuditCreate, list[0].Operation)
+	req.JSONEq(`{"id":`+jsonNumber(city.Id)+`}`, list[0].EntityKey)
+	req.Equal(map[string]map[string]any{"zip_code": {"new": "95120"}}, changes(list[0]))
+
+	// changes to columns that are not audited are not recorded
+	city.Name = "San Francisco"
+	_, err := a.Update(ctx, &city, "city")
+	req.NoError(err)
+	req.Equal(1, len(records()))
+
+	city.ZipCode = "94105"
+	_, err = a.Update(ctx, &city, "city")
+	req.NoError(err)
+
+	list = records()
+	req.Equal(2, len(list))
+	req.Equal(AuditUpdate, list[1].Operation)
+	req.Equal(map[string]map[string]any{"zip_code": {"old": "95120", "new": "94105"}}, changes(list[1]))
+
+	_, err = a.Delete(ctx, AuditedCity{Id: city.Id}, "city")
+	req.NoError(err)
+
+	list = records()
+	req.Equal(3, len(list))
+	req.Equal(AuditDelete, list[2].Operation)
+	req.Equal(map[string]map[string]any{"zip_code": {"old": "94105"}}, changes(list[2]))
+
+	// audit record is written in the same transaction as the entity write
+	city = AuditedCity{Name: "Boston", ZipCode: "02101"}
+	AuditTable = "missing_audit_log"
+	err = a.Create(ctx, &city, "city")
+	AuditTable = "audit_log"
+	req.Error(err)
+
+	n, err := a.Count(context.Background(), AuditedCity{}, "city", nil)
+	req.NoError(err)
+	req.Equal(int64(0), n)
+}
+
+func (s *AccessorTestSuite) TestAuditComposite() {
+	req := require.New(s.T())
+
+	_ = s.Db.MustExec(`
+CREATE TABLE IF NOT EXISTS audit_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    table_name text,
+    entity_key text,
+    actor text,
+    operation text,
+    changes text,
+    changed_at timestamp
+);
+    `)
+	s.setupCompositeTables()
+	defer func() {
+		_ = s.Db.MustExec(`DROP TABLE IF EXISTS audit_log`)
+		s.teardownCompositeTables()
+	}()
+
+	RegisterAudit(GrandChildEntity{})
+	defer UnregisterAudit(GrandChildEntity{})
+
+	a := New(s.Db)
+	ctx := WithActor(context.Background(), "jdoe")
+
+	e := GrandChildEntity{}
+	e.Id = 1
+	req.NoError(a.Read(ctx, &e, "grand_child"))
+
+	e.Name = "qux"
+	e.GrandChildAttr = "plum"
+	_, err := a.Update(ctx, &e, "grand_child")
+	req.NoError(err)
+
+	_, err = a.Delete(ctx, &GrandChildEntity{ChildEntity{BaseEntity{Id: 2}, ""}, ""}, "grand_child")
+	req.NoError(err)
+
+	var list []AuditRecord
+	req.NoError(a.Select(context.Background(), &list, "SELECT * FROM audit_log ORDER BY id"))
+	req.Equal(2, len(list))
+
+	m := map[string]map[string]any{}
+	req.Equal(AuditUpdate, list[0].Operation)
+	req.Equal("grand_child", list[0].TableName)
+	req.JSONEq(`{"id":1}`, list[0].EntityKey)
+	req.NoError(json.Unmarshal([]byte(list[0].Changes), &m))
+	req.Equal(map[string]map[string]any{
+		"name":             {"old": "foo", "new": "qux"},
+		"grand_child_attr": {"old": "apple", "new": "plum"},
+	}, m)
+
+	m = map[string]map[string]any{}
+	req.Equal(AuditDelete, list[1].Operation)
+	req.JSONEq(`{"id":2}`, list[1].EntityKey)
+	req.NoError(json.Unmarshal([]byte(list[1].Changes), &m))
+	req.Equal(map[string]map[string]any{
+		"id":               {"old": float64(2)},
+		"name":             {"old": "bar"},
+		"child_attr":       {"old": "red"},
+		"grand_child_attr": {"old": "cherry"},
+	}, m)
+}
+
+func (s *AccessorTestSuite) TestAuditSetBased() {
+	req := require.New(s.T())
+
+	_ = s.Db.MustExec(`
+CREATE TABLE IF NOT EXISTS audit_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    table_name text,
+    entity_key text,
+    actor text,
+    operation text,
+    changes text,
+    changed_at timestamp
+);
+    `)
+	s.setupCompositeTables()
+	defer func() {
+		_ = s.Db.MustExec(`DROP TABLE IF EXISTS audit_log`)
+		s.teardownCompositeTables()
+		s.setupTestDatabase()
+	}()
+
+	RegisterAudit(GrandChildEntity{})
+	defer UnregisterAudit(GrandChildEntity{})
+
+	a := New(s.Db)
+	ctx := WithActor(context.Background(), "jdoe")
+
+	records := func() []AuditRecord {
+		var list []AuditRecord
+		req.NoError(a.Select(context.Background(), &list, "SELECT * FROM audit_log ORDER BY id"))
+		return list
+	}
+
+	changes := func(r AuditRecord) map[string]map[string]any {
+		m := map[string]map[string]any{}
+		req.NoError(json.Unmarshal([]byte(r.Changes), &m))
+		return m
+	}
+
+	// an audit record per matched entity
+	_, err := a.UpdateWhere(ctx, GrandChildEntity{}, "grand_child",
+		map[string]any{"grand_child_attr": "plum"},
+		squirrel.Eq{"child.child_attr": "red"},
+	)
+	req.NoError(err)
+
+	list := records()
+	req.Equal(2, len(list))
+	for i, old := range []string{"apple", "cherry"} {
+		req.Equal(AuditUpdate, list[i].Operation)
+		req.Equal("jdoe", list[i].Actor)
+		req.JSONEq(`{"id":`+jsonNumber(i+1)+`}`, list[i].EntityKey)
+		req.Equal(map[string]map[string]any{"grand_child_attr": {"old": old, "new": "plum"}}, changes(list[i]))
+	}
+
+	var deleted []GrandChildEntity
+	req.NoError(a.DeleteWhereReturning(ctx, &deleted, "grand_child", squirrel.Eq{"base.name": "foo"}))
+	req.Equal(1, len(deleted))
+
+	_, err = a.DeleteMany(ctx, GrandChildEntity{}, "grand_child", []any{2, 3})
+	req.NoError(err)
+
+	list = records()
+	req.Equal(5, len(list))
+	for i, id := range []int{1, 2, 3} {
+		req.Equal(AuditDelete, list[2+i].Operation)
+		req.JSONEq(`{"id":`+jsonNumber(id)+`}`, list[2+i].EntityKey)
+	}
+	req.Equal(map[string]any{"old": "pear"}, changes(list[4])["grand_child_attr"])
+
+	// flat entities audited by column are written by keys as well
+	city := AuditedCity{Name: "San Jose", ZipCode: "95120"}
+	req.NoError(a.Create(ctx, &city, "city"))
+
+	var updated []AuditedCity
+	req.NoError(a.UpdateWhereReturning(ctx, &updated, "city", map[string]any{"zip_code": "95121"}, squirrel.Eq{"id": city.Id}))
+	req.Equal("95121", updated[0].ZipCode)
+
+	result, err := a.DeleteWhere(ctx, AuditedCity{}, "city", squirrel.Eq{"id": city.Id})
+	req.NoError(err)
+	affected, err := result.RowsAffected()
+	req.NoError(err)
+	req.Equal(int64(1), affected)
+
+	list = records()
+	req.Equal(8, len(list))
+	req.Equal(map[string]map[string]any{"zip_code": {"old": "95120", "new": "95121"}}, changes(list[6]))
+	req.Equal(map[string]map[string]any{"zip_code": {"old": "95121"}}, changes(list[7]))
+}
+
+func jsonNumber(n int) string {
+	b, _ := json.Marshal(n)
+	return string(b)
+}
@@ -0,0 +1,284 @@
+package accessor
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx/reflectx"
+)
+
+// BulkKeyChunkSize limits number of keys that are bound into a single
+// IN (...) predicate by ReadMany and DeleteMany
+var BulkKeyChunkSize = 500
+
+type bulkSqlResult struct {
+	rowsAffected int64
+}
+
+func (r bulkSqlResult) LastInsertId() (int64, error) {
+	return 0, nil
+}
+
+func (r bulkSqlResult) RowsAffected() (int64, error) {
+	return r.rowsAffected, nil
+}
+
+// ReadMany reads entities by a list of primary keys into destSlice.
+//
+// For single column primary key, ids is a list of key values. For composite primary key,
+// each element of ids is a []any that holds key values in the order of idFields.
+// Large key lists are split into chunks of BulkKeyChunkSize keys.
+//
+// Usage example:
+/*
+	var list []Manager
+	err := a.ReadMany(context.Background(), &list, "manager", []any{1000, 1001, 1002})
+
+	// composite primary key
+	var people []Person
+	err = a.ReadMany(context.Background(), &people, "person",
+		[]any{
+			[]any{"foo", "test"},
+			[]any{"bar", "test"},
+		},
+		"FirstName", "LastName",
+	)
+*/
+func (a *Accessor) ReadMany(
+	ctx context.Context,
+	destSlice any,
+	tbl string,
+	ids []any,
+	idFields ...string,
+) error {
+	value := reflect.ValueOf(destSlice)
+	if value.Kind() != reflect.Ptr {
+		return errors.New("must pass a pointer, not a value, to StructScan destination")
+	}
+	if value.IsNil() {
+		return errors.New("nil pointer passed to StructScan destination")
+	}
+	direct := reflect.Indirect(value)
+
+	slice, err := baseType(value.Type(), reflect.Slice)
+	if err != nil {
+		return err
+	}
+	direct.SetLen(0)
+
+	if len(idFields) == 0 {
+		idFields = []string{"Id"}
+	}
+
+	base := reflectx.Deref(slice.Elem())
+	s, err := EntitySchema(reflect.New(base).Interface(), base, tbl)
+	if err != nil {
+		return err
+	}
+
+	idColumns, err := entityIdColumns(s.Entity, idFields...)
+	if err != nil {
+		return err
+	}
+
+	qualifier := s.Tables()[0]
+	return chunkKeys(ids, func(chunk []any) error {
+		where, err := keysPredicate(qualifier, idColumns, chunk)
+		if err != nil {
+			return err
+		}
+
+		chunkSlice := reflect.New(slice)
+		err = a.EntitySelect(ctx, chunkSlice.Interface(), tbl, func(builder squirrel.SelectBuilder) Sqlizer {
+			return builder.Where(where)
+		}, idFields...)
+		if err != nil {
+			return err
+		}
+
+		direct.Set(reflect.AppendSlice(direct, chunkSlice.Elem()))
+		return nil
+	})
+}
+
+// DeleteMany deletes entities by a list of primary keys, it uses the same key
+// list convention as ReadMany.
+//
+// entity is only used to determine entity type (can be a zero value). For composite
+// entity, rows are deleted from leaf table to root table in the inheritance chain,
+// one statement per table per chunk. Entity types that take DeleteCascade (see
+// RegisterDeleteStrategy) are deleted from the root table only, DeleteWithCte falls
+// back to DeleteEachTable. Returned result reports rows affected in the root table.
+//
+// DeleteMany does not start a transaction on its own, use ExecTx to make it atomic.
+// Deletes of audited entities are the exception, they are run in a transaction with
+// their audit records, see RegisterAudit.
+//
+// Usage example:
+/*
+	result, err := a.DeleteMany(context.Background(), Manager{}, "manager", []any{1000, 1001, 1002})
+*/
+func (a *Accessor) DeleteMany(
+	ctx context.Context,
+	entity any,
+	tbl string,
+	ids []any,
+	idFields ...string,
+) (sql.Result, error) {
+	if entity == nil {
+		return nil, errors.New("missing entity")
+	}
+
+	if len(idFields) == 0 {
+		idFields = []string{"Id"}
+	}
+
+	s, err := EntitySchema(entity, reflect.TypeOf(entity), tbl)
+	if err != nil {
+		return nil, err
+	}
+
+	idColumns, err := entityIdColumns(entity, idFields...)
+	if err != nil {
+		return nil, err
+	}
+
+	// rows of other types sharing the table are left alone
+	if ids, err = a.keysOfType(ctx, s, idColumns, ids, idFields...); err != nil {
+		return nil, err
+	}
+
+	var affected int64
+	if cols := auditedColumns(entity); len(cols) > 0 {
+		err = a.withinTx(ctx, func(ta *Accessor) error {
+			return ta.auditedByKeys(ctx, AuditDelete, cols, s, tbl, ids, func() error {
+				affected, err = ta.deleteByKeys(ctx, s, idColumns, ids)
+				return err
+			}, idFields...)
+		})
+	} else {
+		affected, err = a.deleteByKeys(ctx, s, idColumns, ids)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	a.invalidateCacheKeys(s, ids, idFields...)
+	return bulkSqlResult{rowsAffected: affected}, nil
+}
+
+// deleteByKeys deletes rows from leaf table to root table of the entity and
+// returns rows affected in the root table
+func (a *Accessor) deleteByKeys(
+	ctx context.Context,
+	s *EntityMappingSchema,
+	idColumns []string,
+	ids []any,
+) (int64, error) {
+	schemas := s.Schemas()
+	if deleteStrategyOf(ctx, s.Entity) == DeleteCascade {
+		// rows in the other tables are deleted by ON DELETE CASCADE
+		schemas = schemas[:1]
+	}
+
+	var affected int64
+	err := chunkKeys(ids, func(chunk []any) error {
+		for i := len(schemas) - 1; i >= 0; i-- {
+			where, err := keysPredicate("", schemas[i].keyColumns(idColumns), chunk)
+			if err != nil {
+				return err
+			}
+
+			r, err := a.SqlizerExec(ctx, func(builder squirrel.StatementBuilderType) Sqlizer {
+				return builder.Delete(schemas[i].TableName).Where(where)
+			})
+			if err != nil {
+				return err
+			}
+
+			if i == 0 {
+				n, err := r.RowsAffected()
+				if err != nil {
+					return err
+				}
+				affected += n
+			}
+		}
+		return nil
+	})
+
+	return affected, err
+}
+
+func entityIdColumns(entity any, idFields ...string) ([]string, error) {
+	idColumns := []string{}
+	for _, idField := range idFields {
+		col := Column(entity, idField)
+		if col == "" {
+			return nil, errors.New("missing ID columns")
+		}
+		idColumns = append(idColumns, col)
+	}
+	return idColumns, nil
+}
+
+func chunkKeys(ids []any, fn func(chunk []any) error) error {
+	size := BulkKeyChunkSize
+	if size <= 0 {
+		size = len(ids)
+	}
+
+	for start := 0; start < len(ids); start += size {
+		end := start + size
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		if err := fn(ids[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// keysPredicate generates "col IN (...)" for single column key and row-value
+// "(col1,col2) IN ((...),(...))" for composite key
+func keysPredicate(tbl string, idColumns []string, ids []any) (Sqlizer, error) {
+	cols := make([]string, len(idColumns))
+	for i, col := range idColumns {
+		if tbl != "" {
+			cols[i] = fmt.Sprintf("%s.%s", tbl, col)
+		} else {
+			cols[i] = col
+		}
+	}
+
+	if len(cols) == 1 {
+		return squirrel.Eq{cols[0]: ids}, nil
+	}
+
+	var builder strings.Builder
+	args := []any{}
+
+	builder.WriteString("(" + strings.Join(cols, ",") + ") IN (")
+	for i, id := range ids {
+		key, ok := id.([]any)
+		if !ok || len(key) != len(cols) {
+			return nil, fmt.Errorf("composite key should be []any with %d values", len(cols))
+		}
+
+		if i > 0 {
+			builder.WriteString(",")
+		}
+		builder.WriteString("(" + squirrel.Placeholders(len(cols)) + ")")
+		args = append(args, key...)
+	}
+	builder.WriteString(")")
+
+	return squirrel.Expr(builder.String(), args...), nil
+}
@@ -0,0 +1,87 @@
+package accessor
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/require"
+)
+
+func (s *AccessorTestSuite) TestReadManyDeleteMany() {
+	req := require.New(s.T())
+
+	s.setupCompositeTables()
+	defer s.teardownCompositeTables()
+
+	saved := BulkKeyChunkSize
+	BulkKeyChunkSize = 2
+	defer func() {
+		BulkKeyChunkSize = saved
+	}()
+
+	a := New(s.Db)
+	ctx := context.Background()
+
+	var list []GrandChildEntity
+	err := a.ReadMany(ctx, &list, "grand_child", []any{1, 2, 3, 4})
+	req.NoError(err)
+	req.Equal(3, len(list))
+
+	names := map[string]string{}
+	for _, e := range list {
+		names[e.Name] = e.GrandChildAttr
+	}
+	req.Equal(map[string]string{"foo": "apple", "bar": "cherry", "baz": "pear"}, names)
+
+	var plist []*GrandChildEntity
+	err = a.ReadMany(ctx, &plist, "grand_child", []any{})
+	req.NoError(err)
+	req.Equal(0, len(plist))
+
+	result, err := a.DeleteMany(ctx, GrandChildEntity{}, "grand_child", []any{1, 3, 5})
+	req.NoError(err)
+	affected, err := result.RowsAffected()
+	req.NoError(err)
+	req.Equal(int64(2), affected)
+
+	for _, tbl := range []string{"base", "child", "grand_child"} {
+		n := 0
+		err = a.Get(ctx, &n, "SELECT COUNT(*) FROM "+tbl)
+		req.NoError(err)
+		req.Equal(1, n, tbl)
+	}
+
+	err = a.ReadMany(ctx, &plist, "grand_child", []any{1, 2, 3})
+	req.NoError(err)
+	req.Equal(1, len(plist))
+	req.Equal("bar", plist[0].Name)
+}
+
+func (s *AccessorTestSuite) TestReadManyDeleteManyWithCompositeKey() {
+	req := require.New(s.T())
+
+	a := New(s.Db)
+	ctx := context.Background()
+
+	keys := []any{
+		[]any{"foo", "test"},
+		[]any{"bar", "test"},
+		[]any{"baz", "test"},
+	}
+
+	var list []Person
+	err := a.ReadMany(ctx, &list, "person", keys, "FirstName", "LastName")
+	req.NoError(err)
+	req.Equal(2, len(list))
+
+	err = a.ReadMany(ctx, &list, "person", []any{"foo"}, "FirstName", "LastName")
+	req.Error(err)
+
+	result, err := a.DeleteMany(ctx, Person{}, "person", keys, "FirstName", "LastName")
+	req.NoError(err)
+	affected, err := result.RowsAffected()
+	req.NoError(err)
+	req.Equal(int64(2), affected)
+
+	// restore
+	s.setupTestDatabase()
+}
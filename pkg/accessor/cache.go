@@ -0,0 +1,402 @@
+package accessor
+
+import (
+	"container/list"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// CacheKey identifies an entity in EntityCache by the table it is read from and values
+// of its primary key
+type CacheKey struct {
+	Table string
+	Id    string
+}
+
+// EntityCache caches entities read by Read, implementations must be thread-safe
+type EntityCache interface {
+	Get(key CacheKey) (any, bool)
+	Set(key CacheKey, entity any)
+	Delete(key CacheKey)
+	Clear()
+}
+
+var cacheRegistry sync.Map
+
+// root table -> *cachedHierarchy of entities cached from tables rooted at it
+var cachedHierarchies sync.Map
+var cachedHierarchiesMu sync.Mutex
+
+// cachedHierarchy tells tables that entities of an inheritance chain have been cached
+// from, and caches that hold them, it is replaced rather than modified once stored
+type cachedHierarchy struct {
+	tables map[string]bool
+	caches []EntityCache
+}
+
+// add returns a copy of h that also covers entities of table tbl held by cache, or h
+// itself if they are covered already
+func (h *cachedHierarchy) add(tbl string, cache EntityCache) *cachedHierarchy {
+	found := false
+	for _, c := range h.caches {
+		if c == cache {
+			found = true
+			break
+		}
+	}
+	if found && h.tables[tbl] {
+		return h
+	}
+
+	updated := &cachedHierarchy{tables: map[string]bool{tbl: true}, caches: h.caches}
+	for t := range h.tables {
+		updated.tables[t] = true
+	}
+	if !found {
+		updated.caches = append(append([]EntityCache{}, h.caches...), cache)
+	}
+	return updated
+}
+
+// RegisterCache makes Read of entities of given types consult cache by table and primary
+// key, entities found by Read and EntityGet are put in the cache. Create, Update and Delete
+// of entities invalidate cached entities that share rows with them, i.e. entities of all
+// tables in the inheritance chain with the same primary key. UpdateWhere, DeleteWhere and
+// their returning variants clear caches that hold entities of the affected tables.
+//
+// Entities are cached by their primary key, the Id field, entity types without it are not
+// cached. Reads by other ID fields bypass the cache, and writes by other ID fields clear
+// caches that hold entities of the affected tables, as the primary key of the written
+// entity is not known then.
+//
+// Invalidations of writes made by accessors of ExecTx are deferred until the transaction
+// commits, and reads in the transaction bypass cache for entities written in it.
+//
+// Usage example:
+/*
+	accessor.RegisterCache(accessor.NewLRUCache(10000, time.Minute), City{})
+
+	c := City{Id: 1000}
+	err := a.Read(context.Background(), &c, "city")
+*/
+func RegisterCache(cache EntityCache, entities ...any) {
+	for _, entity := range entities {
+		if typ := registeredEntityType(reflect.TypeOf(entity)); typ != nil {
+			cacheRegistry.Store(typ, cache)
+		}
+	}
+}
+
+// UnregisterCache reverts RegisterCache
+func UnregisterCache(entities ...any) {
+	for _, entity := range entities {
+		if typ := registeredEntityType(reflect.TypeOf(entity)); typ != nil {
+			cacheRegistry.Delete(typ)
+		}
+	}
+}
+
+func entityCache(entity any) EntityCache {
+	if typ := registeredEntityType(reflect.TypeOf(entity)); typ != nil {
+		if cache, ok := cacheRegistry.Load(typ); ok {
+			return cache.(EntityCache)
+		}
+	}
+	return nil
+}
+
+// registeredCaches returns all caches registered by RegisterCache
+func registeredCaches() []EntityCache {
+	var caches []EntityCache
+	cacheRegistry.Range(func(_, v any) bool {
+		cache := v.(EntityCache)
+		for _, c := range caches {
+			if c == cache {
+				return true
+			}
+		}
+		caches = append(caches, cache)
+		return true
+	})
+	return caches
+}
+
+// cacheTx keeps cache invalidations of writes made in the transaction of ExecTx
+type cacheTx struct {
+	invalidations []func()
+
+	// root table -> keys of entities written in the transaction, "" for all entities
+	written map[string]map[string]bool
+}
+
+func (t *cacheTx) add(root string, id string, invalidate func()) {
+	t.invalidations = append(t.invalidations, invalidate)
+
+	if t.written[root] == nil {
+		t.written[root] = map[string]bool{}
+	}
+	t.written[root][id] = true
+}
+
+func (t *cacheTx) isWritten(root string, id string) bool {
+	return t.written[root][id] || t.written[root][""]
+}
+
+func newCacheTx() *cacheTx {
+	return &cacheTx{written: map[string]map[string]bool{}}
+}
+
+// commit runs invalidations deferred in the transaction, once it commits
+func (t *cacheTx) commit() {
+	for _, invalidate := range t.invalidations {
+		invalidate()
+	}
+}
+
+// cacheIdentity returns root table and cache key of entity read from table tbl, entities
+// are keyed by their primary key regardless of ID fields they are read or written by
+func (a *Accessor) cacheIdentity(entity any, tbl string) (string, CacheKey, bool) {
+	s, err := EntitySchema(entity, reflect.TypeOf(entity), tbl)
+	if err != nil {
+		return "", CacheKey{}, false
+	}
+
+	key, err := entityKey(a, entity, []string{"Id"})
+	if err != nil {
+		return "", CacheKey{}, false
+	}
+
+	return s.Tables()[0], CacheKey{Table: tbl, Id: keyString(key)}, true
+}
+
+// isPrimaryKey tells if idFields are the primary key entities are cached by
+func isPrimaryKey(idFields []string) bool {
+	return len(idFields) == 0 || len(idFields) == 1 && idFields[0] == "Id"
+}
+
+// readCached reads entity from its cache, it returns false if entity is not cached
+func (a *Accessor) readCached(entity any, tbl string, idFields ...string) bool {
+	cache := entityCache(entity)
+	if cache == nil || !isPrimaryKey(idFields) {
+		return false
+	}
+
+	root, key, ok := a.cacheIdentity(entity, tbl)
+	if !ok || a.cacheTxActive() && a.cacheTx.isWritten(root, key.Id) {
+		return false
+	}
+
+	cached, ok := cache.Get(key)
+	if !ok {
+		return false
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(entity))
+	c := reflect.ValueOf(cached)
+	if !v.CanSet() || c.Type() != v.Type() {
+		return false
+	}
+
+	v.Set(copyValue(c))
+	return true
+}
+
+// cacheEntity puts a copy of entity read from table tbl in its cache
+func (a *Accessor) cacheEntity(entity any, tbl string) {
+	cache := entityCache(entity)
+	if cache == nil {
+		return
+	}
+
+	root, key, ok := a.cacheIdentity(entity, tbl)
+	if !ok || a.cacheTxActive() && a.cacheTx.isWritten(root, key.Id) {
+		return
+	}
+
+	cachedHierarchiesMu.Lock()
+	h, _ := cachedHierarchies.LoadOrStore(root, &cachedHierarchy{})
+	if updated := h.(*cachedHierarchy).add(tbl, cache); updated != h {
+		cachedHierarchies.Store(root, updated)
+	}
+	cachedHierarchiesMu.Unlock()
+
+	cache.Set(key, copyValue(reflect.Indirect(reflect.ValueOf(entity))).Interface())
+}
+
+// invalidateCache invalidates cached entities that share rows with entity written to
+// table tbl by idFields
+func (a *Accessor) invalidateCache(entity any, tbl string, idFields ...string) {
+	if !a.cacheTxActive() && len(registeredCaches()) == 0 {
+		return
+	}
+
+	if !isPrimaryKey(idFields) {
+		if s, err := EntitySchema(entity, reflect.TypeOf(entity), tbl); err == nil {
+			a.invalidateCachedTable(s)
+		}
+		return
+	}
+
+	root, key, ok := a.cacheIdentity(entity, tbl)
+	if !ok {
+		return
+	}
+	a.invalidateCacheKey(root, key.Id)
+}
+
+// invalidateCacheKeys invalidates cached entities of entity type with the given keys of
+// idFields, in the ID list convention of ReadMany
+func (a *Accessor) invalidateCacheKeys(s *EntityMappingSchema, ids []any, idFields ...string) {
+	if !isPrimaryKey(idFields) {
+		a.invalidateCachedTable(s)
+		return
+	}
+
+	for _, id := range ids {
+		key, ok := id.([]any)
+		if !ok {
+			key = []any{id}
+		}
+		a.invalidateCacheKey(s.Tables()[0], keyString(key))
+	}
+}
+
+// invalidateCachedTable invalidates all cached entities of tables in the inheritance chain
+// of entity type
+func (a *Accessor) invalidateCachedTable(s *EntityMappingSchema) {
+	a.invalidateCacheKey(s.Tables()[0], "")
+}
+
+// invalidateCacheKey invalidates cached entities of tables rooted at table root with the
+// given key, caches that hold entities of these tables are cleared if key is empty, other
+// caches are left intact. Invalidation is deferred until commit in transaction of ExecTx.
+func (a *Accessor) invalidateCacheKey(root string, id string) {
+	invalidate := func() {
+		h, ok := cachedHierarchies.Load(root)
+		if !ok {
+			return
+		}
+
+		for _, cache := range h.(*cachedHierarchy).caches {
+			if id == "" {
+				cache.Clear()
+				continue
+			}
+
+			for tbl := range h.(*cachedHierarchy).tables {
+				cache.Delete(CacheKey{Table: tbl, Id: id})
+			}
+		}
+	}
+
+	if a.cacheTxActive() {
+		a.cacheTx.add(root, id, invalidate)
+	} else {
+		invalidate()
+	}
+}
+
+func (a *Accessor) cacheTxActive() bool {
+	return a.cacheTx != nil
+}
+
+// NewLRUCache returns an in-memory EntityCache that holds up to size entities, least
+// recently used entities are evicted first. Entities expire ttl after they are put in
+// the cache, they never expire if ttl is not positive.
+func NewLRUCache(size int, ttl time.Duration) *LRUCache {
+	return &LRUCache{
+		size:    size,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: map[CacheKey]*list.Element{},
+		order:   list.New(),
+	}
+}
+
+// LRUCache is the in-memory EntityCache returned by NewLRUCache
+type LRUCache struct {
+	mu sync.Mutex
+
+	size int
+	ttl  time.Duration
+	now  func() time.Time
+
+	entries map[CacheKey]*list.Element
+	order   *list.List
+}
+
+type lruEntry struct {
+	key     CacheKey
+	entity  any
+	expires time.Time
+}
+
+func (c *LRUCache) Get(key CacheKey) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	e := elem.Value.(*lruEntry)
+	if !e.expires.IsZero() && !c.now().Before(e.expires) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+	return e.entity, true
+}
+
+func (c *LRUCache) Set(key CacheKey, entity any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expires time.Time
+	if c.ttl > 0 {
+		expires = c.now().Add(c.ttl)
+	}
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = &lruEntry{key: key, entity: entity, expires: expires}
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, entity: entity, expires: expires})
+	for c.size > 0 && c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+func (c *LRUCache) Delete(key CacheKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+	}
+}
+
+func (c *LRUCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = map[CacheKey]*list.Element{}
+	c.order.Init()
+}
+
+// Len returns number of entities in the cache, including expired ones not evicted yet
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
@@ -0,0 +1,214 @@
+package accessor
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *AccessorTestSuite) TestLRUCache() {
+	req := require.New(s.T())
+
+	now := time.Now()
+	c := NewLRUCache(2, time.Minute)
+	c.now = func() time.Time { return now }
+
+	c.Set(CacheKey{"city", "1"}, "foo")
+	c.Set(CacheKey{"city", "2"}, "bar")
+
+	// least recently used entity is evicted
+	_, ok := c.Get(CacheKey{"city", "1"})
+	req.True(ok)
+	c.Set(CacheKey{"city", "3"}, "baz")
+	_, ok = c.Get(CacheKey{"city", "2"})
+	req.False(ok)
+	req.Equal(2, c.Len())
+
+	v, ok := c.Get(CacheKey{"city", "1"})
+	req.True(ok)
+	req.Equal("foo", v)
+
+	// entities expire after ttl
+	now = now.Add(time.Minute)
+	_, ok = c.Get(CacheKey{"city", "1"})
+	req.False(ok)
+	req.Equal(1, c.Len())
+
+	c.Delete(CacheKey{"city", "3"})
+	req.Equal(0, c.Len())
+
+	c.Set(CacheKey{"city", "4"}, "qux")
+	c.Clear()
+	_, ok = c.Get(CacheKey{"city", "4"})
+	req.False(ok)
+}
+
+func (s *AccessorTestSuite) TestReadCache() {
+	req := require.New(s.T())
+
+	s.setupCompositeTables()
+	defer s.teardownCompositeTables()
+
+	cache := NewLRUCache(100, 0)
+	RegisterCache(cache, BaseEntity{}, GrandChildEntity{})
+	defer UnregisterCache(BaseEntity{}, GrandChildEntity{})
+
+	a := New(s.Db)
+	ctx := context.Background()
+
+	e := GrandChildEntity{ChildEntity{BaseEntity: BaseEntity{Id: 1}}, ""}
+	req.NoError(a.Read(ctx, &e, "grand_child"))
+	req.Equal("apple", e.GrandChildAttr)
+
+	b := BaseEntity{Id: 1}
+	req.NoError(a.Read(ctx, &b, "base"))
+	req.Equal(2, cache.Len())
+
+	// cached entities are served without query
+	_ = s.Db.MustExec("UPDATE grand_child SET grand_child_attr = 'changed' WHERE id = 1")
+
+	r := GrandChildEntity{ChildEntity{BaseEntity: BaseEntity{Id: 1}}, ""}
+	req.NoError(a.Read(ctx, &r, "grand_child"))
+	req.Equal("apple", r.GrandChildAttr)
+
+	// update invalidates entities of all tables sharing the row
+	r.Name = "updated"
+	_, err := a.Update(ctx, &r, "grand_child")
+	req.NoError(err)
+	req.Equal(0, cache.Len())
+
+	req.NoError(a.Read(ctx, &b, "base"))
+	req.Equal("updated", b.Name)
+
+	// invalidation is dropped on rollback
+	err = ExecTx(ctx, s.Db, nil, func(ctx context.Context, ta *Accessor) error {
+		b.Name = "rollback"
+		if _, err := ta.Update(ctx, &b, "base"); err != nil {
+			return err
+		}
+
+		// entities written in transaction are read from database
+		c := BaseEntity{Id: 1}
+		if err := ta.Read(ctx, &c, "base"); err != nil {
+			return err
+		}
+		req.Equal("rollback", c.Name)
+
+		return errors.New("rollback")
+	})
+	req.Error(err)
+
+	_ = s.Db.MustExec("UPDATE base SET name = 'changed' WHERE id = 1")
+	c := BaseEntity{Id: 1}
+	req.NoError(a.Read(ctx, &c, "base"))
+	req.Equal("updated", c.Name)
+
+	// invalidation happens on commit
+	err = ExecTx(ctx, s.Db, nil, func(ctx context.Context, ta *Accessor) error {
+		c.Name = "committed"
+		_, err := ta.Update(ctx, &c, "base")
+		return err
+	})
+	req.NoError(err)
+
+	c = BaseEntity{Id: 1}
+	req.NoError(a.Read(ctx, &c, "base"))
+	req.Equal("committed", c.Name)
+
+	// set-based writes drop cached entities of the type
+	_, err = a.UpdateWhere(ctx, BaseEntity{}, "base", map[string]any{"name": "where"}, squirrel.Eq{"id": 3})
+	req.NoError(err)
+	req.Equal(0, cache.Len())
+}
+
+type CachedCity struct {
+	Id      int    `db:"id"`
+	Name    string `db:"name"`
+	ZipCode string `db:"zip_code"`
+}
+
+func (s *AccessorTestSuite) TestReadCacheOtherHierarchy() {
+	req := require.New(s.T())
+
+	s.setupCompositeTables()
+	defer s.teardownCompositeTables()
+
+	baseCache := NewLRUCache(10, 0)
+	cityCache := NewLRUCache(10, 0)
+	RegisterCache(baseCache, BaseEntity{})
+	RegisterCache(cityCache, CachedCity{})
+	defer UnregisterCache(BaseEntity{}, CachedCity{})
+
+	a := New(s.Db)
+	ctx := context.Background()
+
+	city := CachedCity{Name: "Cupertino", ZipCode: "95014"}
+	req.NoError(a.Create(ctx, &city, "city"))
+	defer func() {
+		_, err := a.Delete(ctx, &city, "city")
+		req.NoError(err)
+	}()
+
+	req.NoError(a.Read(ctx, &CachedCity{Id: city.Id}, "city"))
+	req.NoError(a.Read(ctx, &BaseEntity{Id: 1}, "base"))
+	req.Equal(1, cityCache.Len())
+	req.Equal(1, baseCache.Len())
+
+	// set-based writes leave caches of other hierarchies intact
+	_, err := a.UpdateWhere(ctx, BaseEntity{}, "base", map[string]any{"name": "where"}, squirrel.Eq{"id": 3})
+	req.NoError(err)
+	req.Equal(0, baseCache.Len())
+	req.Equal(1, cityCache.Len())
+}
+
+func (s *AccessorTestSuite) TestReadCacheByOtherIdFields() {
+	req := require.New(s.T())
+
+	cache := NewLRUCache(10, 0)
+	RegisterCache(cache, CachedCity{})
+	defer UnregisterCache(CachedCity{})
+
+	a := New(s.Db)
+	ctx := context.Background()
+
+	city := CachedCity{Name: "Cupertino", ZipCode: "95014"}
+	req.NoError(a.Create(ctx, &city, "city"))
+
+	// entities read by other ID fields are cached by primary key
+	c := CachedCity{ZipCode: "95014"}
+	req.NoError(a.Read(ctx, &c, "city", "ZipCode"))
+	req.Equal(city, c)
+	req.Equal(1, cache.Len())
+
+	city.Name = "updated"
+	_, err := a.Update(ctx, &city, "city")
+	req.NoError(err)
+	req.Equal(0, cache.Len())
+
+	c = CachedCity{ZipCode: "95014"}
+	req.NoError(a.Read(ctx, &c, "city", "ZipCode"))
+	req.Equal("updated", c.Name)
+
+	// reads by other ID fields bypass cache
+	_ = s.Db.MustExec("UPDATE city SET name = 'changed' WHERE id = ?", city.Id)
+
+	c = CachedCity{ZipCode: "95014"}
+	req.NoError(a.Read(ctx, &c, "city", "ZipCode"))
+	req.Equal("changed", c.Name)
+
+	c = CachedCity{Id: city.Id}
+	req.NoError(a.Read(ctx, &c, "city"))
+	req.Equal("changed", c.Name)
+	req.Equal(1, cache.Len())
+
+	// writes by other ID fields clear cached entities of the table
+	_, err = a.Delete(ctx, CachedCity{ZipCode: "95014"}, "city", "ZipCode")
+	req.NoError(err)
+	req.Equal(0, cache.Len())
+
+	req.ErrorIs(a.Read(ctx, &CachedCity{Id: city.Id}, "city"), sql.ErrNoRows)
+}
@@ -0,0 +1,65 @@
+package accessor
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ErrTxDbMismatch is returned by FromContext if the ambient transaction runs on a
+// database handle other than the one asked for
+var ErrTxDbMismatch = errors.New("ambient transaction runs on a different database handle")
+
+type accessorKey struct{}
+
+// withAccessor returns a context that carries the accessor, ExecTx uses it to
+// make the transaction accessor available to helpers such as Enqueue
+func withAccessor(ctx context.Context, a *Accessor) context.Context {
+	return context.WithValue(ctx, accessorKey{}, a)
+}
+
+func accessorFromContext(ctx context.Context) (*Accessor, bool) {
+	a, ok := ctx.Value(accessorKey{}).(*Accessor)
+	return a, ok
+}
+
+// FromContext returns the transaction accessor of ExecTx carried by ctx, so that code
+// called within ExecTx joins the ambient transaction without being passed the accessor.
+// Outside ExecTx, it returns an accessor of fallbackDB.
+//
+// ErrTxDbMismatch is returned if the ambient transaction has been started on a database
+// handle other than fallbackDB, fallbackDB may be nil to join the ambient transaction
+// regardless.
+//
+// Usage example:
+/*
+	func (r *OrderRepository) Save(ctx context.Context, order *Order) error {
+		a, err := accessor.FromContext(ctx, r.db)
+		if err != nil {
+			return err
+		}
+		return a.Create(ctx, order, "orders")
+	}
+
+	// both writes are made in the same transaction
+	err := accessor.ExecTx(ctx, db, nil, func(ctx context.Context, a *accessor.Accessor) error {
+		if err := orders.Save(ctx, order); err != nil {
+			return err
+		}
+		return accessor.Enqueue(ctx, "order.created", order)
+	})
+*/
+func FromContext(ctx context.Context, fallbackDB *sqlx.DB) (*Accessor, error) {
+	if a, ok := accessorFromContext(ctx); ok {
+		if fallbackDB != nil && a.txDb != fallbackDB {
+			return nil, ErrTxDbMismatch
+		}
+		return a, nil
+	}
+
+	if fallbackDB == nil {
+		return nil, errors.New("missing database outside transaction")
+	}
+	return New(fallbackDB), nil
+}
@@ -0,0 +1,58 @@
+package accessor
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *AccessorTestSuite) TestFromContext() {
+	req := require.New(s.T())
+
+	s.setupCompositeTables()
+	defer s.teardownCompositeTables()
+
+	ctx := context.Background()
+
+	// repository function that is not passed an accessor
+	save := func(ctx context.Context, e *BaseEntity) error {
+		a, err := FromContext(ctx, s.Db)
+		if err != nil {
+			return err
+		}
+		return a.Create(ctx, e, "base")
+	}
+
+	a, err := FromContext(ctx, s.Db)
+	req.NoError(err)
+	req.Equal(s.Db, a.Db)
+
+	_, err = FromContext(ctx, nil)
+	req.Error(err)
+
+	e := BaseEntity{Name: "rollback"}
+	err = ExecTx(ctx, s.Db, nil, func(ctx context.Context, ta *Accessor) error {
+		a, err := FromContext(ctx, s.Db)
+		req.NoError(err)
+		req.Same(ta, a)
+
+		a, err = FromContext(ctx, nil)
+		req.NoError(err)
+		req.Same(ta, a)
+
+		_, err = FromContext(ctx, sqlx.NewDb(s.Db.DB, "sqlite3"))
+		req.ErrorIs(err, ErrTxDbMismatch)
+
+		if err := save(ctx, &e); err != nil {
+			return err
+		}
+		return errors.New("rollback")
+	})
+	req.Error(err)
+
+	// write made through FromContext is rolled back with the transaction
+	req.ErrorIs(New(s.Db).Read(ctx, &BaseEntity{Id: e.Id}, "base"), sql.ErrNoRows)
+}
@@ -0,0 +1,112 @@
+package accessor
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// TableColumn is a column qualified with the table that owns it. gdbc entity enhancer
+// generates TableColumn objects for every mapped field of an entity, including fields
+// inherited from base entities, so that criteria can be written in a type-safe way.
+//
+// Usage example:
+/*
+	mgrList := []Manager{}
+	err = a.EntitySelect(
+		context.Background(),
+		&mgrList,
+		m.TableName(),
+		func(builder squirrel.SelectBuilder) accessor.Sqlizer {
+			return builder.
+				Where(ManagerCols.Company.Eq("bar.com")).
+				OrderBy(ManagerCols.FirstName.Asc())
+		},
+	)
+*/
+type TableColumn struct {
+	Table string
+	Name  string
+}
+
+func NewTableColumn(tbl string, col string) TableColumn {
+	return TableColumn{
+		Table: tbl,
+		Name:  col,
+	}
+}
+
+// String returns the table qualified column name, e.g. person.first_name
+func (c TableColumn) String() string {
+	if c.Table == "" {
+		return c.Name
+	}
+	return fmt.Sprintf("%s.%s", c.Table, c.Name)
+}
+
+func (c TableColumn) Eq(val any) squirrel.Sqlizer {
+	return squirrel.Eq{c.String(): val}
+}
+
+func (c TableColumn) NotEq(val any) squirrel.Sqlizer {
+	return squirrel.NotEq{c.String(): val}
+}
+
+func (c TableColumn) Lt(val any) squirrel.Sqlizer {
+	return squirrel.Lt{c.String(): val}
+}
+
+func (c TableColumn) LtOrEq(val any) squirrel.Sqlizer {
+	return squirrel.LtOrEq{c.String(): val}
+}
+
+func (c TableColumn) Gt(val any) squirrel.Sqlizer {
+	return squirrel.Gt{c.String(): val}
+}
+
+func (c TableColumn) GtOrEq(val any) squirrel.Sqlizer {
+	return squirrel.GtOrEq{c.String(): val}
+}
+
+// In generates "col IN (...)", an empty value list matches nothing
+func (c TableColumn) In(vals ...any) squirrel.Sqlizer {
+	return squirrel.Eq{c.String(): vals}
+}
+
+func (c TableColumn) NotIn(vals ...any) squirrel.Sqlizer {
+	return squirrel.NotEq{c.String(): vals}
+}
+
+func (c TableColumn) Like(pattern string) squirrel.Sqlizer {
+	return squirrel.Like{c.String(): pattern}
+}
+
+func (c TableColumn) NotLike(pattern string) squirrel.Sqlizer {
+	return squirrel.NotLike{c.String(): pattern}
+}
+
+// ILike is Postgres only
+func (c TableColumn) ILike(pattern string) squirrel.Sqlizer {
+	return squirrel.ILike{c.String(): pattern}
+}
+
+func (c TableColumn) IsNull() squirrel.Sqlizer {
+	return squirrel.Eq{c.String(): nil}
+}
+
+func (c TableColumn) IsNotNull() squirrel.Sqlizer {
+	return squirrel.NotEq{c.String(): nil}
+}
+
+func (c TableColumn) Between(low any, high any) squirrel.Sqlizer {
+	return squirrel.Expr(c.String()+" BETWEEN ? AND ?", low, high)
+}
+
+// Asc returns ORDER BY expression to be used with squirrel.SelectBuilder.OrderBy
+func (c TableColumn) Asc() string {
+	return c.String() + " ASC"
+}
+
+func (c TableColumn) Desc() string {
+	return c.String() + " DESC"
+}
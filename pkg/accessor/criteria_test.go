@@ -0,0 +1,48 @@
+package accessor
+
+import (
+	"testing"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TableColumn(t *testing.T) {
+	req := require.New(t)
+
+	c := NewTableColumn("person", "first_name")
+	req.Equal("person.first_name", c.String())
+	req.Equal("person.first_name ASC", c.Asc())
+	req.Equal("person.first_name DESC", c.Desc())
+
+	cases := []struct {
+		sqlizer squirrel.Sqlizer
+		sql     string
+		args    []any
+	}{
+		{c.Eq("foo"), "person.first_name = ?", []any{"foo"}},
+		{c.NotEq("foo"), "person.first_name <> ?", []any{"foo"}},
+		{c.Gt(1), "person.first_name > ?", []any{1}},
+		{c.LtOrEq(1), "person.first_name <= ?", []any{1}},
+		{c.In("foo", "bar"), "person.first_name IN (?,?)", []any{"foo", "bar"}},
+		{c.NotIn("foo"), "person.first_name NOT IN (?)", []any{"foo"}},
+		{c.Like("fo%"), "person.first_name LIKE ?", []any{"fo%"}},
+		{c.IsNull(), "person.first_name IS NULL", nil},
+		{c.IsNotNull(), "person.first_name IS NOT NULL", nil},
+		{c.Between(1, 2), "person.first_name BETWEEN ? AND ?", []any{1, 2}},
+	}
+
+	for _, tc := range cases {
+		sql, args, err := tc.sqlizer.ToSql()
+		req.NoError(err)
+		req.Equal(tc.sql, sql)
+		if tc.args == nil {
+			req.Empty(args)
+		} else {
+			req.Equal(tc.args, args)
+		}
+	}
+
+	c = NewTableColumn("", "first_name")
+	req.Equal("first_name", c.String())
+}
@@ -0,0 +1,236 @@
+package accessor
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// supportsDataModifyingCte tells if the database behind driver supports data-modifying
+// statements (INSERT/DELETE ... RETURNING) in WITH clauses
+func supportsDataModifyingCte(driverName string) bool {
+	switch driverName {
+	case "postgres", "pgx":
+		return true
+	}
+	return false
+}
+
+// cteName returns name of the CTE that writes table tbl, prefix tells the operation, e.g. ins
+func cteName(prefix string, tbl string) string {
+	return prefix + "_" + strings.ReplaceAll(tbl, ".", "_")
+}
+
+// createCompositeWithCte creates composite entity with a single statement, tables in the
+// inheritance chain are inserted by chained data-modifying CTEs and the joined row is
+// scanned back into the entity, which saves a round trip per table and the read-back.
+func (a *Accessor) createCompositeWithCte(
+	ctx context.Context,
+	s *EntityMappingSchema,
+	tracker UpdateTracker,
+	idFields ...string,
+) error {
+	q, args, err := a.compositeInsertCte(s, tracker, idFields...)
+	if err != nil {
+		return err
+	}
+
+	return a.Get(WithTable(ctx, s.TableName), s.Entity, a.Db.Rebind(q), args...)
+}
+
+// compositeInsertCte builds the statement that creates composite entity in s, e.g.
+/*
+	WITH ins_person AS (INSERT INTO person (first_name) VALUES (?) RETURNING *),
+	ins_employee AS (INSERT INTO employee (person_id,company) VALUES ((SELECT id FROM ins_person),?) RETURNING *)
+	SELECT ins_person.*, ins_employee.* FROM ins_person JOIN ins_employee ON ins_person.id=ins_employee.person_id
+*/
+// Key columns of tables other than the root table take values of ID columns returned by
+// the CTE of the root table.
+func (a *Accessor) compositeInsertCte(
+	s *EntityMappingSchema,
+	tracker UpdateTracker,
+	idFields ...string,
+) (string, []any, error) {
+	if len(idFields) == 0 {
+		idFields = []string{"Id"}
+	}
+
+	idColumns, _, err := a.getMapping(s.Entity, idFields...)
+	if err != nil {
+		return "", nil, err
+	}
+
+	schemas := s.Schemas()
+	root := cteName("ins", schemas[0].TableName)
+
+	var builder strings.Builder
+	var args []any
+
+	for i, mm := range schemas {
+		cols, vals, err := a.createMapping(mm.Entity, mm, nil, tracker, mm.TableName, idFields...)
+		if err != nil {
+			return "", nil, err
+		}
+
+		if i > 0 {
+			keyColumns := mm.keyColumns(idColumns)
+
+			keyVals := make([]any, len(keyColumns))
+			for j, col := range idColumns {
+				keyVals[j] = squirrel.Expr(fmt.Sprintf("(SELECT %s FROM %s)", col, root))
+			}
+
+			for j := 0; j < len(cols); j++ {
+				if stringInSlice(cols[j], keyColumns) {
+					cols = append(cols[:j], cols[j+1:]...)
+					vals = append(vals[:j], vals[j+1:]...)
+					j--
+				}
+			}
+
+			cols = append(append([]string{}, keyColumns...), cols...)
+			vals = append(keyVals, vals...)
+		}
+
+		q, insertArgs, err := insertReturning(squirrel.StatementBuilder, mm.TableName, cols, vals).ToSql()
+		if err != nil {
+			return "", nil, err
+		}
+
+		if i == 0 {
+			builder.WriteString("WITH ")
+		} else {
+			builder.WriteString(", ")
+		}
+		builder.WriteString(fmt.Sprintf("%s AS (%s)", cteName("ins", mm.TableName), q))
+		args = append(args, insertArgs...)
+	}
+
+	builder.WriteString(" SELECT ")
+	for i, mm := range schemas {
+		if i > 0 {
+			builder.WriteString(", ")
+		}
+		builder.WriteString(cteName("ins", mm.TableName) + ".*")
+	}
+
+	builder.WriteString(" FROM " + root)
+	for i := 1; i < len(schemas); i++ {
+		builder.WriteString(fmt.Sprintf(" JOIN %s ON %s", cteName("ins", schemas[i].TableName), getKeyJoinOnString(
+			cteName("ins", schemas[i-1].TableName), schemas[i-1].keyColumns(idColumns),
+			cteName("ins", schemas[i].TableName), schemas[i].keyColumns(idColumns),
+		)))
+	}
+
+	return builder.String(), args, nil
+}
+
+// deleteCompositeWithCte deletes composite entity with a single statement, tables in the
+// inheritance chain are deleted by chained data-modifying CTEs, which also count rows
+// deleted in each table.
+func (a *Accessor) deleteCompositeWithCte(
+	ctx context.Context,
+	s *EntityMappingSchema,
+	idFields ...string,
+) (sql.Result, error) {
+	q, args, err := a.compositeDeleteCte(s, idFields...)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := a.queryx(ctx, a.Db.Rebind(q), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	schemas := s.Schemas()
+	counts := make([]int64, len(schemas))
+	dest := make([]any, len(counts))
+	for i := range counts {
+		dest[i] = &counts[i]
+	}
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, sql.ErrNoRows
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	result := CompositeResult{
+		RootTable:         schemas[0].TableName,
+		TableRowsAffected: map[string]int64{},
+	}
+	for i, mm := range schemas {
+		result.TableRowsAffected[mm.TableName] = counts[i]
+	}
+
+	return result, nil
+}
+
+// compositeDeleteCte builds the statement that deletes composite entity in s from leaf
+// table to root table, and selects rows deleted in each table in the order of s.Schemas(), e.g.
+/*
+	WITH del_employee AS (DELETE FROM employee WHERE person_id = ? RETURNING 1),
+	del_person AS (DELETE FROM person WHERE id = ? RETURNING 1)
+	SELECT (SELECT count(*) FROM del_person), (SELECT count(*) FROM del_employee)
+*/
+// Foreign keys between the tables are checked at the end of the statement, unless they
+// are declared with ON DELETE RESTRICT.
+func (a *Accessor) compositeDeleteCte(s *EntityMappingSchema, idFields ...string) (string, []any, error) {
+	schemas := s.Schemas()
+
+	var builder strings.Builder
+	var args []any
+
+	for i := len(schemas) - 1; i >= 0; i-- {
+		m := schemas[i]
+
+		keyValueMap, keyColumns, err := a.tableKeys(m, idFields...)
+		if err != nil {
+			return "", nil, err
+		}
+
+		eq := squirrel.Eq{}
+		for _, col := range keyColumns {
+			v, ok := keyValueMap[col]
+			if !ok {
+				return "", nil, errors.New("missing ID columns")
+			}
+			if eq[col], err = keyLookupValue(m.EntityType, m.TableName, col, v); err != nil {
+				return "", nil, err
+			}
+		}
+
+		q, deleteArgs, err := squirrel.StatementBuilder.Delete(m.TableName).Where(eq).Suffix("RETURNING 1").ToSql()
+		if err != nil {
+			return "", nil, err
+		}
+
+		if i == len(schemas)-1 {
+			builder.WriteString("WITH ")
+		} else {
+			builder.WriteString(", ")
+		}
+		builder.WriteString(fmt.Sprintf("%s AS (%s)", cteName("del", m.TableName), q))
+		args = append(args, deleteArgs...)
+	}
+
+	builder.WriteString(" SELECT ")
+	for i, m := range schemas {
+		if i > 0 {
+			builder.WriteString(", ")
+		}
+		builder.WriteString(fmt.Sprintf("(SELECT count(*) FROM %s)", cteName("del", m.TableName)))
+	}
+
+	return builder.String(), args, nil
+}
@@ -0,0 +1,48 @@
+package accessor
+
+import (
+	"reflect"
+
+	"github.com/stretchr/testify/require"
+)
+
+func (s *AccessorTestSuite) TestCompositeInsertCte() {
+	req := require.New(s.T())
+
+	req.True(supportsDataModifyingCte("postgres"))
+	req.False(supportsDataModifyingCte("sqlite3"))
+
+	a := New(s.Db)
+
+	e := &GrandChildEntity{ChildEntity{BaseEntity{Name: "foo"}, "red"}, "apple"}
+	schema, err := EntitySchema(e, reflect.TypeOf(e), "grand_child")
+	req.NoError(err)
+
+	q, args, err := a.compositeInsertCte(schema, nil)
+	req.NoError(err)
+	req.Equal(
+		"WITH ins_base AS (INSERT INTO base (name) VALUES (?) RETURNING *), "+
+			"ins_child AS (INSERT INTO child (id,child_attr) VALUES ((SELECT id FROM ins_base),?) RETURNING *), "+
+			"ins_grand_child AS (INSERT INTO grand_child (id,grand_child_attr) VALUES ((SELECT id FROM ins_base),?) RETURNING *) "+
+			"SELECT ins_base.*, ins_child.*, ins_grand_child.* FROM ins_base "+
+			"JOIN ins_child ON ins_base.id=ins_child.id "+
+			"JOIN ins_grand_child ON ins_child.id=ins_grand_child.id",
+		q,
+	)
+	req.Equal([]any{"foo", "red", "apple"}, args)
+
+	// key columns of different names and explicitly given ID
+	l := &LegacyGrandChildEntity{
+		LegacyChildEntity: LegacyChildEntity{BaseEntity: BaseEntity{Id: 100}, ChildAttr: "red"},
+		GrandChildAttr:    "apple",
+	}
+	schema, err = EntitySchema(l, reflect.TypeOf(l), "legacy_grand_child")
+	req.NoError(err)
+
+	q, args, err = a.compositeInsertCte(schema, nil)
+	req.NoError(err)
+	req.Contains(q, "ins_legacy_child AS (INSERT INTO legacy_child (base_id,child_attr) VALUES ((SELECT id FROM ins_legacy_base),?) RETURNING *)")
+	req.Contains(q, "JOIN ins_legacy_grand_child ON ins_legacy_child.base_id=ins_legacy_grand_child.child_id")
+	req.Equal(100, args[0])
+	req.Len(args, 4)
+}
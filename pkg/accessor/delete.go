@@ -0,0 +1,94 @@
+package accessor
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+// DeleteStrategy tells how Delete removes composite entities from tables in their
+// inheritance chains
+type DeleteStrategy int
+
+const (
+	// DeleteEachTable deletes rows from leaf table to root table, one statement per table
+	DeleteEachTable DeleteStrategy = iota
+
+	// DeleteCascade deletes the row in the root table only, rows in the other tables
+	// are left to ON DELETE CASCADE of their foreign keys
+	DeleteCascade
+
+	// DeleteWithCte deletes rows from all tables with a single statement of chained
+	// data-modifying CTEs on Postgres, other databases fall back to DeleteEachTable
+	DeleteWithCte
+)
+
+// CompositeResult is the sql.Result of Delete of composite entities, RowsAffected
+// reports rows affected in the root table
+type CompositeResult struct {
+	RootTable string
+
+	// table -> rows affected, tables left to ON DELETE CASCADE are not included
+	TableRowsAffected map[string]int64
+}
+
+func (r CompositeResult) LastInsertId() (int64, error) {
+	return 0, nil
+}
+
+func (r CompositeResult) RowsAffected() (int64, error) {
+	return r.TableRowsAffected[r.RootTable], nil
+}
+
+var deleteStrategyRegistry sync.Map
+
+// RegisterDeleteStrategy sets the strategy Delete takes for composite entities of given
+// types, entities of types that are not registered are deleted with DeleteEachTable,
+// see also WithDeleteStrategy.
+//
+// Usage example:
+/*
+	accessor.RegisterDeleteStrategy(accessor.DeleteCascade, Manager{}, crosspkg.Executive{})
+
+	result, err := a.Delete(context.Background(), &m, "manager")
+	affected := result.(accessor.CompositeResult).TableRowsAffected
+*/
+func RegisterDeleteStrategy(strategy DeleteStrategy, entities ...any) {
+	for _, entity := range entities {
+		if typ := registeredEntityType(reflect.TypeOf(entity)); typ != nil {
+			deleteStrategyRegistry.Store(typ, strategy)
+		}
+	}
+}
+
+// UnregisterDeleteStrategy reverts RegisterDeleteStrategy
+func UnregisterDeleteStrategy(entities ...any) {
+	for _, entity := range entities {
+		if typ := registeredEntityType(reflect.TypeOf(entity)); typ != nil {
+			deleteStrategyRegistry.Delete(typ)
+		}
+	}
+}
+
+type deleteStrategyKey struct{}
+
+// WithDeleteStrategy returns a context that makes Delete take strategy for composite
+// entities, it takes precedence over strategies set by RegisterDeleteStrategy
+func WithDeleteStrategy(ctx context.Context, strategy DeleteStrategy) context.Context {
+	return context.WithValue(ctx, deleteStrategyKey{}, strategy)
+}
+
+// deleteStrategyOf returns the strategy to delete entity with
+func deleteStrategyOf(ctx context.Context, entity any) DeleteStrategy {
+	if strategy, ok := ctx.Value(deleteStrategyKey{}).(DeleteStrategy); ok {
+		return strategy
+	}
+
+	if typ := registeredEntityType(reflect.TypeOf(entity)); typ != nil {
+		if strategy, ok := deleteStrategyRegistry.Load(typ); ok {
+			return strategy.(DeleteStrategy)
+		}
+	}
+
+	return DeleteEachTable
+}
@@ -0,0 +1,116 @@
+package accessor
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/stretchr/testify/require"
+)
+
+func (s *AccessorTestSuite) TestDeleteStrategies() {
+	req := require.New(s.T())
+
+	_ = s.Db.MustExec(`
+PRAGMA foreign_keys = ON;
+
+CREATE TABLE IF NOT EXISTS base (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name text
+);
+
+CREATE TABLE IF NOT EXISTS child (
+    id integer primary key references base(id) ON DELETE CASCADE,
+    child_attr text
+);
+
+CREATE TABLE IF NOT EXISTS grand_child (
+    id integer primary key references child(id) ON DELETE CASCADE,
+    grand_child_attr text
+);
+    `)
+	defer func() {
+		UnregisterDeleteStrategy(GrandChildEntity{})
+		s.teardownCompositeTables()
+		_ = s.Db.MustExec(`PRAGMA foreign_keys = OFF;`)
+	}()
+
+	a := New(s.Db)
+	ctx := context.Background()
+
+	create := func(name string) *GrandChildEntity {
+		e := &GrandChildEntity{ChildEntity{BaseEntity{Name: name}, "red"}, "apple"}
+		req.NoError(a.Create(ctx, e, "grand_child"))
+		return e
+	}
+
+	count := func(tbl string) int {
+		var cnt int
+		req.NoError(s.Db.Get(&cnt, "SELECT count(*) FROM "+tbl))
+		return cnt
+	}
+
+	// rows are deleted from each table by default
+	e := create("foo")
+	result, err := a.Delete(ctx, e, "grand_child")
+	req.NoError(err)
+	req.Equal(CompositeResult{
+		RootTable:         "base",
+		TableRowsAffected: map[string]int64{"base": 1, "child": 1, "grand_child": 1},
+	}, result)
+
+	affected, err := result.RowsAffected()
+	req.NoError(err)
+	req.Equal(int64(1), affected)
+
+	// per-call strategy
+	e = create("bar")
+	result, err = a.Delete(WithDeleteStrategy(ctx, DeleteCascade), e, "grand_child")
+	req.NoError(err)
+	req.Equal(map[string]int64{"base": 1}, result.(CompositeResult).TableRowsAffected)
+	req.Equal(0, count("child"))
+	req.Equal(0, count("grand_child"))
+
+	// per-entity strategy, which per-call strategy takes precedence over
+	RegisterDeleteStrategy(DeleteCascade, GrandChildEntity{})
+
+	e = create("baz")
+	result, err = a.Delete(ctx, e, "grand_child")
+	req.NoError(err)
+	req.Equal(map[string]int64{"base": 1}, result.(CompositeResult).TableRowsAffected)
+
+	e = create("qux")
+	result, err = a.Delete(WithDeleteStrategy(ctx, DeleteEachTable), e, "grand_child")
+	req.NoError(err)
+	req.Len(result.(CompositeResult).TableRowsAffected, 3)
+
+	// CTE falls back to deletes of each table with SQLite
+	e = create("quux")
+	result, err = a.Delete(WithDeleteStrategy(ctx, DeleteWithCte), e, "grand_child")
+	req.NoError(err)
+	req.Len(result.(CompositeResult).TableRowsAffected, 3)
+	req.Equal(0, count("base"))
+}
+
+func (s *AccessorTestSuite) TestCompositeDeleteCte() {
+	req := require.New(s.T())
+
+	a := New(s.Db)
+
+	e := &LegacyGrandChildEntity{
+		LegacyChildEntity: LegacyChildEntity{BaseEntity: BaseEntity{Id: 100}},
+	}
+	schema, err := EntitySchema(e, reflect.TypeOf(e), "legacy_grand_child")
+	req.NoError(err)
+
+	q, args, err := a.compositeDeleteCte(schema, "Id")
+	req.NoError(err)
+	req.Equal(
+		"WITH del_legacy_grand_child AS (DELETE FROM legacy_grand_child WHERE child_id = ? RETURNING 1), "+
+			"del_legacy_child AS (DELETE FROM legacy_child WHERE base_id = ? RETURNING 1), "+
+			"del_legacy_base AS (DELETE FROM legacy_base WHERE id = ? RETURNING 1) "+
+			"SELECT (SELECT count(*) FROM del_legacy_base), (SELECT count(*) FROM del_legacy_child), "+
+			"(SELECT count(*) FROM del_legacy_grand_child)",
+		q,
+	)
+	req.Equal([]any{100, 100, 100}, args)
+}
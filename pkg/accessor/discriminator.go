@@ -0,0 +1,111 @@
+package accessor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// attributes of embedded type that maps single-table inheritance, subtypes share the table
+// of base type and their rows are distinguished by value of discriminator column, e.g.
+// `db:",discriminator=kind,value=manager"`
+const (
+	attrDiscriminator      = "discriminator"
+	attrDiscriminatorValue = "value"
+)
+
+// discriminatorColumn returns discriminator column qualified with table name
+func (m *EntityMappingSchema) discriminatorColumn() string {
+	return fmt.Sprintf("%s.%s", m.TableName, m.Discriminator)
+}
+
+// withDiscriminator restricts where predicate to rows of entity type in s, if the entity
+// type is mapped by single-table inheritance
+func withDiscriminator(s *EntityMappingSchema, where Sqlizer) Sqlizer {
+	if s.Discriminator == "" {
+		return where
+	}
+
+	eq := squirrel.Eq{s.discriminatorColumn(): s.DiscriminatorValue}
+	if where == nil {
+		return eq
+	}
+	return squirrel.And{where, eq}
+}
+
+// setDiscriminator makes sure that discriminator column is inserted with the value of
+// entity type in s, it overrides the value of field mapped to the column if there is one
+func setDiscriminator(s *EntityMappingSchema, cols []string, vals []any) ([]string, []any) {
+	if s.Discriminator == "" {
+		return cols, vals
+	}
+
+	for i, col := range cols {
+		if col == s.Discriminator {
+			vals[i] = s.DiscriminatorValue
+			return cols, vals
+		}
+	}
+
+	return append(cols, s.Discriminator), append(vals, s.DiscriminatorValue)
+}
+
+// isOfType tells if the row of entity in s is of entity type in s. Writes of composite
+// entity types mapped by single-table inheritance check it up front, their base tables
+// do not carry the discriminator column.
+func (a *Accessor) isOfType(ctx context.Context, s *EntityMappingSchema, idFields ...string) (bool, error) {
+	if s.Discriminator == "" {
+		return true, nil
+	}
+
+	idColumns, colValueMap, err := a.getMapping(s.Entity, idFields...)
+	if err != nil {
+		return false, err
+	}
+
+	root := s.Tables()[0]
+	eq := squirrel.Eq{}
+	for _, col := range idColumns {
+		v, ok := colValueMap[col]
+		if !ok {
+			return false, errors.New("missing ID columns")
+		}
+
+		if eq[fmt.Sprintf("%s.%s", root, col)], err = keyLookupValue(s.EntityType, s.TableName, col, v); err != nil {
+			return false, err
+		}
+	}
+
+	_, ids, err := a.selectKeys(ctx, s, eq, idFields...)
+	return len(ids) > 0, err
+}
+
+// keysOfType returns keys in ids, in the ID list convention of ReadMany, of rows of entity
+// type in s, keys of rows of other types sharing the table are left out
+func (a *Accessor) keysOfType(
+	ctx context.Context,
+	s *EntityMappingSchema,
+	idColumns []string,
+	ids []any,
+	idFields ...string,
+) ([]any, error) {
+	if s.Discriminator == "" {
+		return ids, nil
+	}
+
+	matched := []any{}
+	err := chunkKeys(ids, func(chunk []any) error {
+		where, err := keysPredicate(s.Tables()[0], idColumns, chunk)
+		if err != nil {
+			return err
+		}
+
+		_, keys, err := a.selectKeys(ctx, s, where, idFields...)
+		matched = append(matched, keys...)
+		return err
+	})
+
+	return matched, err
+}
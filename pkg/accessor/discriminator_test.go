@@ -0,0 +1,233 @@
+package accessor
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+)
+
+type StaffEntity struct {
+	BaseEntity `db:",discriminator=kind,value=staff"`
+	Title      string `db:"title"`
+}
+
+type InternEntity struct {
+	BaseEntity `db:",discriminator=kind,value=intern"`
+}
+
+type StaffChildEntity struct {
+	ChildEntity `db:",discriminator=kind,value=staff"`
+	Title       string `db:"title"`
+}
+
+type StaffEntityWithTableAttribute struct {
+	BaseEntity `db:",table=base,discriminator=kind,value=staff"`
+}
+
+type StaffEntityWithoutDiscriminatorValue struct {
+	BaseEntity `db:",discriminator=kind"`
+}
+
+func (s *AccessorTestSuite) TestDiscriminatorSchema() {
+	req := require.New(s.T())
+
+	schema, err := EntitySchema(StaffEntity{}, reflect.TypeOf(StaffEntity{}), "party")
+	req.NoError(err)
+	req.Equal("party", schema.TableName)
+	req.Equal(map[string]string{"Id": "id", "Name": "name", "Title": "title"}, schema.Columns)
+	req.Empty(schema.BaseMappings)
+	req.Equal("kind", schema.Discriminator)
+	req.Equal("staff", schema.DiscriminatorValue)
+
+	// subtype without columns of its own
+	schema, err = EntitySchema(InternEntity{}, reflect.TypeOf(InternEntity{}), "party")
+	req.NoError(err)
+	req.Equal("intern", schema.DiscriminatorValue)
+
+	schema, err = EntitySchema(StaffChildEntity{}, reflect.TypeOf(StaffChildEntity{}), "child")
+	req.NoError(err)
+	req.Equal([]string{"base", "child"}, schema.Tables())
+	req.Equal(map[string]string{"ChildAttr": "child_attr", "Title": "title"}, schema.Columns)
+
+	_, err = EntitySchema(StaffEntityWithTableAttribute{}, reflect.TypeOf(StaffEntityWithTableAttribute{}), "party")
+	req.Error(err)
+
+	_, err = EntitySchema(StaffEntityWithoutDiscriminatorValue{}, reflect.TypeOf(StaffEntityWithoutDiscriminatorValue{}), "party")
+	req.Error(err)
+}
+
+func (s *AccessorTestSuite) TestDiscriminator() {
+	req := require.New(s.T())
+
+	_ = s.Db.MustExec(`
+CREATE TABLE IF NOT EXISTS party (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    kind text NOT NULL,
+    name text,
+    title text
+);
+    `)
+	defer func() {
+		_ = s.Db.MustExec(`DROP TABLE IF EXISTS party;`)
+	}()
+
+	a := New(s.Db)
+	ctx := context.Background()
+
+	staff := StaffEntity{BaseEntity: BaseEntity{Name: "foo"}, Title: "engineer"}
+	req.NoError(a.Create(ctx, &staff, "party"))
+
+	intern := InternEntity{BaseEntity: BaseEntity{Name: "bar"}}
+	req.NoError(a.Create(ctx, &intern, "party"))
+
+	var kinds []string
+	req.NoError(a.Select(ctx, &kinds, "SELECT kind FROM party ORDER BY id"))
+	req.Equal([]string{"staff", "intern"}, kinds)
+
+	r := StaffEntity{}
+	r.Id = staff.Id
+	req.NoError(a.Read(ctx, &r, "party"))
+	req.Equal(staff, r)
+
+	r = StaffEntity{}
+	r.Id = intern.Id
+	req.ErrorIs(a.Read(ctx, &r, "party"), sql.ErrNoRows)
+
+	var list []StaffEntity
+	req.NoError(a.EntitySelect(ctx, &list, "party", func(builder squirrel.SelectBuilder) Sqlizer {
+		return builder
+	}))
+	req.Equal([]StaffEntity{staff}, list)
+
+	cnt, err := a.Count(ctx, InternEntity{}, "party", nil)
+	req.NoError(err)
+	req.Equal(int64(1), cnt)
+
+	example := StaffEntity{}
+	example.Name = "bar"
+	req.ErrorIs(a.FindByExample(ctx, &StaffEntity{}, &example, "party", nil), sql.ErrNoRows)
+
+	result, err := a.UpdateWhere(ctx, InternEntity{}, "party",
+		map[string]any{"name": "baz"},
+		nil,
+	)
+	req.NoError(err)
+	affected, err := result.RowsAffected()
+	req.NoError(err)
+	req.Equal(int64(1), affected)
+
+	result, err = a.DeleteWhere(ctx, StaffEntity{}, "party", squirrel.Eq{"name": "baz"})
+	req.NoError(err)
+	affected, err = result.RowsAffected()
+	req.NoError(err)
+	req.Equal(int64(0), affected)
+
+	// writes by key leave rows of other types alone
+	other := StaffEntity{BaseEntity: BaseEntity{Id: intern.Id, Name: "qux"}, Title: "engineer"}
+	result, err = a.Update(ctx, &other, "party")
+	req.NoError(err)
+	affected, err = result.RowsAffected()
+	req.NoError(err)
+	req.Equal(int64(0), affected)
+
+	result, err = a.Delete(ctx, StaffEntity{BaseEntity: BaseEntity{Id: intern.Id}}, "party")
+	req.NoError(err)
+	affected, err = result.RowsAffected()
+	req.NoError(err)
+	req.Equal(int64(0), affected)
+
+	result, err = a.DeleteMany(ctx, StaffEntity{}, "party", []any{intern.Id})
+	req.NoError(err)
+	affected, err = result.RowsAffected()
+	req.NoError(err)
+	req.Equal(int64(0), affected)
+
+	i := InternEntity{BaseEntity: BaseEntity{Id: intern.Id}}
+	req.NoError(a.Read(ctx, &i, "party"))
+	req.Equal("baz", i.Name)
+
+	result, err = a.Delete(ctx, InternEntity{BaseEntity: BaseEntity{Id: intern.Id}}, "party")
+	req.NoError(err)
+	affected, err = result.RowsAffected()
+	req.NoError(err)
+	req.Equal(int64(1), affected)
+}
+
+func (s *AccessorTestSuite) TestDiscriminatorComposite() {
+	req := require.New(s.T())
+
+	s.setupCompositeTables()
+	defer s.teardownCompositeTables()
+
+	_ = s.Db.MustExec(`
+ALTER TABLE child ADD COLUMN kind text;
+ALTER TABLE child ADD COLUMN title text;
+    `)
+
+	a := New(s.Db)
+	ctx := context.Background()
+
+	e := ChildEntity{BaseEntity: BaseEntity{Name: "foo"}, ChildAttr: "red"}
+	req.NoError(a.Create(ctx, &e, "child"))
+
+	staff := StaffChildEntity{
+		ChildEntity: ChildEntity{BaseEntity: BaseEntity{Name: "bar"}, ChildAttr: "blue"},
+		Title:       "engineer",
+	}
+	req.NoError(a.Create(ctx, &staff, "child"))
+
+	r := StaffChildEntity{}
+	r.Id = staff.Id
+	req.NoError(a.Read(ctx, &r, "child"))
+	req.Equal(staff, r)
+
+	r = StaffChildEntity{}
+	r.Id = e.Id
+	req.ErrorIs(a.Read(ctx, &r, "child"), sql.ErrNoRows)
+
+	var list []StaffChildEntity
+	req.NoError(a.ReadMany(ctx, &list, "child", []any{e.Id, staff.Id}))
+	req.Equal([]StaffChildEntity{staff}, list)
+
+	staff.Title = "manager"
+	_, err := a.Update(ctx, &staff, "child")
+	req.NoError(err)
+
+	var title string
+	req.NoError(a.Get(ctx, &title, "SELECT title FROM child WHERE kind = 'staff'"))
+	req.Equal("manager", title)
+
+	// writes by key leave rows of other types alone, in all tables
+	other := StaffChildEntity{
+		ChildEntity: ChildEntity{BaseEntity: BaseEntity{Id: e.Id, Name: "qux"}, ChildAttr: "green"},
+		Title:       "engineer",
+	}
+	result, err := a.Update(ctx, &other, "child")
+	req.NoError(err)
+	affected, err := result.RowsAffected()
+	req.NoError(err)
+	req.Equal(int64(0), affected)
+
+	result, err = a.Delete(ctx, StaffChildEntity{ChildEntity: ChildEntity{BaseEntity: BaseEntity{Id: e.Id}}}, "child")
+	req.NoError(err)
+	affected, err = result.RowsAffected()
+	req.NoError(err)
+	req.Equal(int64(0), affected)
+
+	result, err = a.DeleteMany(ctx, StaffChildEntity{}, "child", []any{e.Id, staff.Id})
+	req.NoError(err)
+	affected, err = result.RowsAffected()
+	req.NoError(err)
+	req.Equal(int64(1), affected)
+
+	c := ChildEntity{BaseEntity: BaseEntity{Id: e.Id}}
+	req.NoError(a.Read(ctx, &c, "child"))
+	req.Equal(e, c)
+
+	var cnt int
+	req.NoError(a.Get(ctx, &cnt, "SELECT COUNT(*) FROM base WHERE id = ?", staff.Id))
+	req.Equal(0, cnt)
+}
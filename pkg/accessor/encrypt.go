@@ -0,0 +1,520 @@
+package accessor
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/jmoiron/sqlx/reflectx"
+)
+
+const (
+	attrEncrypted          = "encrypted"
+	encryptedDeterministic = "deterministic"
+)
+
+// Encryptor encrypts values of columns tagged with encrypted attribute, e.g.
+// `db:"email,encrypted"`, or `db:"email,encrypted=deterministic"` for columns that
+// should stay usable in equality lookups. Ciphertext is stored as text.
+//
+// aad is additional authenticated data in the form of <table>.<column>, where table is
+// the table of the column in the inheritance chain of the entity. Encryptors should bind
+// ciphertext to it, so that ciphertext copied to another column or table can not be
+// decrypted there.
+type Encryptor interface {
+	Encrypt(plaintext []byte, aad []byte, deterministic bool) (string, error)
+	Decrypt(ciphertext string, aad []byte) ([]byte, error)
+}
+
+// FieldEncryptor is the Encryptor used by accessor, it should be set before entities
+// with encrypted columns are written or read
+//
+// Usage example:
+/*
+	accessor.FieldEncryptor = accessor.NewAESGCMEncryptor(&accessor.StaticKeyProvider{
+		CurrentKeyId: "2023-10",
+		Keys: map[string][]byte{
+			"2023-04": oldKey,
+			"2023-10": newKey,
+		},
+	})
+*/
+var FieldEncryptor Encryptor
+
+// LookupEncryptor is implemented by Encryptors that support key rotation, it encrypts
+// plaintext deterministically under every key in use, so that equality lookups also match
+// values encrypted with retired keys
+type LookupEncryptor interface {
+	EncryptForLookup(plaintext []byte, aad []byte) ([]string, error)
+}
+
+// KeyProvider supplies keys to AES-GCM encryptor. Values are always encrypted with
+// the current key, and key id is embedded in ciphertext so that values encrypted with
+// retired keys can still be decrypted.
+type KeyProvider interface {
+	CurrentKey() (keyId string, key []byte, err error)
+	Key(keyId string) ([]byte, error)
+}
+
+// StaticKeyProvider keeps keys in memory, keys should be 16, 24 or 32 bytes long
+type StaticKeyProvider struct {
+	CurrentKeyId string
+	Keys         map[string][]byte
+}
+
+func (p *StaticKeyProvider) CurrentKey() (string, []byte, error) {
+	key, err := p.Key(p.CurrentKeyId)
+	return p.CurrentKeyId, key, err
+}
+
+// KeyIds returns ids of all keys, AES-GCM encryptor looks up deterministically encrypted
+// values under each of them
+func (p *StaticKeyProvider) KeyIds() []string {
+	keyIds := make([]string, 0, len(p.Keys))
+	for keyId := range p.Keys {
+		keyIds = append(keyIds, keyId)
+	}
+	sort.Strings(keyIds)
+	return keyIds
+}
+
+func (p *StaticKeyProvider) Key(keyId string) ([]byte, error) {
+	key, ok := p.Keys[keyId]
+	if !ok {
+		return nil, fmt.Errorf("encryption key %s does not exist", keyId)
+	}
+	return key, nil
+}
+
+type aesGCMEncryptor struct {
+	keys KeyProvider
+}
+
+// NewAESGCMEncryptor returns an AES-GCM Encryptor, ciphertext is in the form of
+// <key id>:<base64 of nonce and sealed data>. Key id and aad are authenticated.
+//
+// In deterministic mode, nonce is derived from HMAC-SHA256 of aad and the plaintext, so
+// that the same plaintext is always encrypted into the same ciphertext with the same key
+// in the same column.
+// The encryptor implements LookupEncryptor if keys provides KeyIds() []string, as
+// StaticKeyProvider does, otherwise lookups only match values encrypted with the
+// current key.
+func NewAESGCMEncryptor(keys KeyProvider) Encryptor {
+	return &aesGCMEncryptor{
+		keys: keys,
+	}
+}
+
+func (e *aesGCMEncryptor) Encrypt(plaintext []byte, aad []byte, deterministic bool) (string, error) {
+	keyId, key, err := e.keys.CurrentKey()
+	if err != nil {
+		return "", err
+	}
+
+	return encryptWithKey(keyId, key, plaintext, aad, deterministic)
+}
+
+func (e *aesGCMEncryptor) EncryptForLookup(plaintext []byte, aad []byte) ([]string, error) {
+	lister, ok := e.keys.(interface{ KeyIds() []string })
+	if !ok {
+		ciphertext, err := e.Encrypt(plaintext, aad, true)
+		if err != nil {
+			return nil, err
+		}
+		return []string{ciphertext}, nil
+	}
+
+	var ciphertexts []string
+	for _, keyId := range lister.KeyIds() {
+		key, err := e.keys.Key(keyId)
+		if err != nil {
+			return nil, err
+		}
+
+		ciphertext, err := encryptWithKey(keyId, key, plaintext, aad, true)
+		if err != nil {
+			return nil, err
+		}
+		ciphertexts = append(ciphertexts, ciphertext)
+	}
+	return ciphertexts, nil
+}
+
+func encryptWithKey(keyId string, key []byte, plaintext []byte, aad []byte, deterministic bool) (string, error) {
+	if strings.Contains(keyId, ":") {
+		return "", fmt.Errorf("encryption key id %s should not contain ':'", keyId)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if deterministic {
+		// use a derived key for nonce generation rather than the encryption key itself
+		kdf := hmac.New(sha256.New, key)
+		kdf.Write([]byte("gdbc deterministic nonce"))
+
+		// aad is length-prefixed, nonces of different columns never collide
+		mac := hmac.New(sha256.New, kdf.Sum(nil))
+		var size [4]byte
+		binary.BigEndian.PutUint32(size[:], uint32(len(aad)))
+		mac.Write(size[:])
+		mac.Write(aad)
+		mac.Write(plaintext)
+		copy(nonce, mac.Sum(nil))
+	} else if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, plaintext, gcmAdditionalData(keyId, aad))
+	return keyId + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// gcmAdditionalData authenticates key id along with aad, key id never contains ':'
+func gcmAdditionalData(keyId string, aad []byte) []byte {
+	return append([]byte(keyId+":"), aad...)
+}
+
+func (e *aesGCMEncryptor) Decrypt(ciphertext string, aad []byte) ([]byte, error) {
+	tokens := strings.SplitN(ciphertext, ":", 2)
+	if len(tokens) != 2 {
+		return nil, fmt.Errorf("invalid ciphertext format")
+	}
+
+	key, err := e.keys.Key(tokens[0])
+	if err != nil {
+		return nil, err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(tokens[1])
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, fmt.Errorf("invalid ciphertext format")
+	}
+
+	nonce, sealed := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, sealed, gcmAdditionalData(tokens[0], aad))
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+var encryptedColumnsCache sync.Map
+
+// encryptedColumns returns column -> deterministic mapping of encrypted columns in entity type
+func encryptedColumns(typ reflect.Type) map[string]bool {
+	typ = reflectx.Deref(typ)
+	if cols, ok := encryptedColumnsCache.Load(typ); ok {
+		return cols.(map[string]bool)
+	}
+
+	cols := map[string]bool{}
+	for col, attrs := range entityColumnAttributes(typ) {
+		if mode, ok := attrs[attrEncrypted]; ok {
+			cols[col] = mode == encryptedDeterministic
+		}
+	}
+
+	encryptedColumnsCache.Store(typ, cols)
+	return cols
+}
+
+var baseColumnTablesCache sync.Map
+
+// baseColumnTables returns column -> table mapping of columns inherited from base entities
+// that are mapped to tables of their own, i.e. embedded with table attribute. Columns of
+// the entity table itself are not in the mapping.
+func baseColumnTables(typ reflect.Type) map[string]string {
+	typ = reflectx.Deref(typ)
+	if tables, ok := baseColumnTablesCache.Load(typ); ok {
+		return tables.(map[string]string)
+	}
+
+	tables := map[string]string{}
+	if typ.Kind() == reflect.Struct {
+		own := map[string]bool{}
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			col, attrs, err := fieldMappedColumnWithAttributes(field, "db")
+
+			if !field.Anonymous {
+				if err == nil && col != "" {
+					own[col] = true
+				}
+				continue
+			}
+
+			inherited := map[string]bool{}
+			for col := range entityColumnAttributes(field.Type) {
+				inherited[col] = true
+			}
+
+			// columns of base entities of the embedded type keep their own tables
+			baseTables := baseColumnTables(field.Type)
+			for col := range inherited {
+				if tbl, ok := baseTables[col]; ok {
+					tables[col] = tbl
+				} else if tbl := attrs["table"]; tbl != "" {
+					tables[col] = tbl
+				}
+			}
+		}
+
+		// fields declared by the type shadow fields of embedded types
+		for col := range own {
+			delete(tables, col)
+		}
+	}
+
+	baseColumnTablesCache.Store(typ, tables)
+	return tables
+}
+
+// encryptionAad returns additional authenticated data of encrypted column col of entity
+// type mapped to table tbl, nil is returned if table of the column is unknown
+func encryptionAad(typ reflect.Type, tbl string, col string) []byte {
+	if base, ok := baseColumnTables(typ)[col]; ok {
+		tbl = base
+	}
+
+	if tbl == "" {
+		return nil
+	}
+	return []byte(tbl + "." + col)
+}
+
+func encryptValue(typ reflect.Type, tbl string, col string, val any, deterministic bool) (any, error) {
+	var plaintext []byte
+	switch v := val.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		plaintext = []byte(v)
+	case []byte:
+		plaintext = v
+	default:
+		return nil, fmt.Errorf("encrypted column %s should be string or []byte", col)
+	}
+
+	if FieldEncryptor == nil {
+		return nil, fmt.Errorf("missing FieldEncryptor for encrypted column %s", col)
+	}
+
+	aad := encryptionAad(typ, tbl, col)
+	if aad == nil {
+		return nil, fmt.Errorf("missing table of encrypted column %s", col)
+	}
+
+	return FieldEncryptor.Encrypt(plaintext, aad, deterministic)
+}
+
+// lookupDriverValue works as driverValue for equality lookups of deterministically encrypted
+// column col, values are encrypted under every key in use if FieldEncryptor implements
+// LookupEncryptor, a slice of ciphertexts is returned then to be matched with IN
+func lookupDriverValue(entityType reflect.Type, tbl string, col string, v reflect.Value) (any, error) {
+	val, err := plainDriverValue(entityType, col, v)
+	if err != nil {
+		return nil, err
+	}
+
+	lookup, ok := FieldEncryptor.(LookupEncryptor)
+	if !ok || val == nil {
+		return encryptValue(entityType, tbl, col, val, true)
+	}
+
+	var plaintext []byte
+	switch t := val.(type) {
+	case string:
+		plaintext = []byte(t)
+	case []byte:
+		plaintext = t
+	default:
+		return nil, fmt.Errorf("encrypted column %s should be string or []byte", col)
+	}
+
+	aad := encryptionAad(entityType, tbl, col)
+	if aad == nil {
+		return nil, fmt.Errorf("missing table of encrypted column %s", col)
+	}
+
+	ciphertexts, err := lookup.EncryptForLookup(plaintext, aad)
+	if err != nil {
+		return nil, err
+	}
+
+	vals := make([]any, len(ciphertexts))
+	for i, ciphertext := range ciphertexts {
+		vals[i] = ciphertext
+	}
+	return vals, nil
+}
+
+// keyDriverValue works as driverValue for key column col, encrypted key columns must be
+// encrypted deterministically to be matched by key predicates
+func keyDriverValue(entityType reflect.Type, tbl string, col string, v reflect.Value) (any, error) {
+	if deterministic, ok := encryptedColumns(entityType)[col]; ok && !deterministic {
+		return nil, fmt.Errorf("key column %s is not encrypted deterministically", col)
+	}
+	return driverValue(entityType, tbl, col, v)
+}
+
+// keyLookupValue returns value of key column col to be matched by key predicates, values
+// of encrypted key columns are looked up as lookupDriverValue does, a slice of ciphertexts
+// may be returned to be matched with IN
+func keyLookupValue(entityType reflect.Type, tbl string, col string, v reflect.Value) (any, error) {
+	deterministic, ok := encryptedColumns(entityType)[col]
+	if !ok {
+		return getDriverValue(v), nil
+	}
+
+	if !deterministic {
+		return nil, fmt.Errorf("key column %s is not encrypted deterministically", col)
+	}
+	return lookupDriverValue(entityType, tbl, col, v)
+}
+
+// destEncryptedColumns returns encrypted columns of entity type of dest, which can be a
+// pointer to an entity or to a slice of entities
+func destEncryptedColumns(dest any) map[string]bool {
+	typ := destEntityType(dest)
+	if typ == nil {
+		return nil
+	}
+	return encryptedColumns(typ)
+}
+
+func destEntityType(dest any) reflect.Type {
+	typ := reflectx.Deref(reflect.TypeOf(dest))
+	if typ.Kind() == reflect.Slice {
+		typ = reflectx.Deref(typ.Elem())
+	}
+
+	if typ.Kind() != reflect.Struct {
+		return nil
+	}
+	return typ
+}
+
+type tableKey struct{}
+
+// WithTable returns a context that carries the table rows are read from by Get, Select,
+// NamedGet and NamedSelect. Values of encrypted columns are bound to their tables, the
+// table is needed to decrypt them unless entity type of dest implements TableNamer.
+//
+// Usage example:
+/*
+	var people []EncryptedPerson
+	err := a.Select(accessor.WithTable(ctx, "person"), &people, "SELECT * FROM person")
+*/
+func WithTable(ctx context.Context, tbl string) context.Context {
+	return context.WithValue(ctx, tableKey{}, tbl)
+}
+
+// destTable returns the table rows scanned into dest are read from, empty string is
+// returned if it is unknown
+func destTable(ctx context.Context, dest any) string {
+	if tbl, ok := ctx.Value(tableKey{}).(string); ok {
+		return tbl
+	}
+
+	if typ := destEntityType(dest); typ != nil {
+		if namer, ok := reflect.New(typ).Interface().(TableNamer); ok {
+			return namer.TableName()
+		}
+	}
+	return ""
+}
+
+// encryptedScanner decrypts value of an encrypted column as it is scanned into field
+type encryptedScanner struct {
+	col string
+	aad []byte
+	v   reflect.Value
+}
+
+func (s *encryptedScanner) Scan(src any) error {
+	var ciphertext []byte
+	switch t := src.(type) {
+	case nil:
+		s.v.Set(reflect.Zero(s.v.Type()))
+		return nil
+	case []byte:
+		ciphertext = t
+	case string:
+		ciphertext = []byte(t)
+	default:
+		return fmt.Errorf("unsupported type %T of encrypted column %s", src, s.col)
+	}
+
+	plaintext, err := decryptBytes(s.col, s.aad, ciphertext)
+	if err != nil {
+		return err
+	}
+
+	// fields of Scanner types, e.g. Null[string] or sql.NullString, scan the plaintext
+	if scanner, ok := s.v.Addr().Interface().(sql.Scanner); ok {
+		return scanner.Scan(plaintext)
+	}
+
+	v := s.v
+	if v.Kind() == reflect.Ptr {
+		v.Set(reflect.New(v.Type().Elem()))
+		v = v.Elem()
+	}
+
+	if scanner, ok := v.Addr().Interface().(sql.Scanner); ok {
+		return scanner.Scan(plaintext)
+	}
+
+	switch {
+	case v.Kind() == reflect.String:
+		v.SetString(string(plaintext))
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+		v.SetBytes(plaintext)
+	default:
+		return fmt.Errorf("encrypted column %s should be string, []byte or a Scanner of them", s.col)
+	}
+	return nil
+}
+
+func decryptBytes(col string, aad []byte, ciphertext []byte) ([]byte, error) {
+	if FieldEncryptor == nil {
+		return nil, fmt.Errorf("missing FieldEncryptor for encrypted column %s", col)
+	}
+
+	if aad == nil {
+		return nil, fmt.Errorf("missing table of encrypted column %s, pass it with WithTable", col)
+	}
+
+	plaintext, err := FieldEncryptor.Decrypt(string(ciphertext), aad)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt column %s: %w", col, err)
+	}
+	return plaintext, nil
+}
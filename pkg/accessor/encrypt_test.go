@@ -0,0 +1,385 @@
+package accessor
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+)
+
+type EncryptedCity struct {
+	Id      int    `db:"id"`
+	Name    string `db:"name"`
+	ZipCode string `db:"zip_code,encrypted"`
+}
+
+type EncryptedNullCity struct {
+	Id      int            `db:"id"`
+	Name    sql.NullString `db:"name,encrypted"`
+	ZipCode Null[string]   `db:"zip_code,encrypted=deterministic"`
+}
+
+type EncryptedPerson struct {
+	FirstName string `db:"first_name"`
+	LastName  string `db:"last_name"`
+	Email     string `db:"email,encrypted=deterministic"`
+}
+
+type EncryptedBaseEntity struct {
+	Id   int    `db:"id"`
+	Name string `db:"name,encrypted"`
+}
+
+type EncryptedChildEntity struct {
+	EncryptedBaseEntity `db:",table=base"`
+	ChildAttr           string `db:"child_attr"`
+}
+
+func Test_AESGCMEncryptor(t *testing.T) {
+	req := require.New(t)
+
+	keys := &StaticKeyProvider{
+		CurrentKeyId: "k1",
+		Keys: map[string][]byte{
+			"k1": []byte("0123456789abcdef0123456789abcdef"),
+			"k2": []byte("fedcba9876543210fedcba9876543210"),
+		},
+	}
+	e := NewAESGCMEncryptor(keys)
+
+	aad := []byte("person.email")
+
+	c1, err := e.Encrypt([]byte("foo@test"), aad, false)
+	req.NoError(err)
+	req.True(strings.HasPrefix(c1, "k1:"))
+
+	c2, err := e.Encrypt([]byte("foo@test"), aad, false)
+	req.NoError(err)
+	req.NotEqual(c1, c2)
+
+	d1, err := e.Encrypt([]byte("foo@test"), aad, true)
+	req.NoError(err)
+	d2, err := e.Encrypt([]byte("foo@test"), aad, true)
+	req.NoError(err)
+	req.Equal(d1, d2)
+
+	for _, c := range []string{c1, c2, d1} {
+		p, err := e.Decrypt(c, aad)
+		req.NoError(err)
+		req.Equal("foo@test", string(p))
+	}
+
+	// ciphertext is bound to its column
+	d3, err := e.Encrypt([]byte("foo@test"), []byte("person.first_name"), true)
+	req.NoError(err)
+	req.NotEqual(d1, d3)
+
+	for _, c := range []string{c1, d1} {
+		_, err = e.Decrypt(c, []byte("person.first_name"))
+		req.Error(err)
+	}
+
+	// values encrypted with retired keys can still be decrypted
+	keys.CurrentKeyId = "k2"
+	c3, err := e.Encrypt([]byte("foo@test"), aad, true)
+	req.NoError(err)
+	req.True(strings.HasPrefix(c3, "k2:"))
+
+	p, err := e.Decrypt(c1, aad)
+	req.NoError(err)
+	req.Equal("foo@test", string(p))
+
+	// key id is authenticated
+	_, err = e.Decrypt("k2"+strings.TrimPrefix(c1, "k1"), aad)
+	req.Error(err)
+
+	_, err = e.Decrypt("k3:"+strings.TrimPrefix(c1, "k1:"), aad)
+	req.Error(err)
+
+	_, err = e.Decrypt("plaintext", aad)
+	req.Error(err)
+}
+
+func Test_encryptionAad(t *testing.T) {
+	req := require.New(t)
+
+	req.Equal("city.zip_code", string(encryptionAad(reflect.TypeOf(EncryptedCity{}), "city", "zip_code")))
+	req.Nil(encryptionAad(reflect.TypeOf(EncryptedCity{}), "", "zip_code"))
+
+	// inherited columns are bound to the base table
+	req.Equal("base.name", string(encryptionAad(reflect.TypeOf(&EncryptedChildEntity{}), "child", "name")))
+	req.Equal("base.name", string(encryptionAad(reflect.TypeOf(&EncryptedChildEntity{}), "", "name")))
+	req.Equal("child.child_attr", string(encryptionAad(reflect.TypeOf(&EncryptedChildEntity{}), "child", "child_attr")))
+}
+
+func (s *AccessorTestSuite) TestEncryptedColumns() {
+	req := require.New(s.T())
+
+	keys := &StaticKeyProvider{
+		CurrentKeyId: "k1",
+		Keys: map[string][]byte{
+			"k1": []byte("0123456789abcdef0123456789abcdef"),
+			"k2": []byte("fedcba9876543210fedcba9876543210"),
+		},
+	}
+	FieldEncryptor = NewAESGCMEncryptor(keys)
+	defer func() {
+		FieldEncryptor = nil
+		s.setupTestDatabase()
+	}()
+
+	_ = s.Db.MustExec(`DELETE FROM person`)
+
+	a := New(s.Db)
+	ctx := context.Background()
+
+	city := EncryptedCity{Name: "San Jose", ZipCode: "95120"}
+	req.NoError(a.Create(ctx, &city, "city"))
+	req.Equal("95120", city.ZipCode)
+
+	city2 := EncryptedCity{Name: "Santa Clara", ZipCode: "95120"}
+	req.NoError(a.Create(ctx, &city2, "city"))
+
+	// stored values are encrypted, randomized encryption hides equal values
+	var zips []string
+	req.NoError(s.Db.Select(&zips, "SELECT zip_code FROM city ORDER BY id"))
+	req.Equal(2, len(zips))
+	req.True(strings.HasPrefix(zips[0], "k1:"))
+	req.NotEqual(zips[0], zips[1])
+
+	c := EncryptedCity{Id: city.Id}
+	req.NoError(a.Read(ctx, &c, "city"))
+	req.Equal("95120", c.ZipCode)
+
+	// key rotation
+	keys.CurrentKeyId = "k2"
+	city.ZipCode = "95121"
+	_, err := a.Update(ctx, &city, "city")
+	req.NoError(err)
+
+	var list []*EncryptedCity
+	req.NoError(a.EntitySelect(ctx, &list, "city", func(builder squirrel.SelectBuilder) Sqlizer {
+		return builder.OrderBy("id")
+	}))
+	req.Equal(2, len(list))
+	req.Equal("95121", list[0].ZipCode)
+	req.Equal("95120", list[1].ZipCode)
+
+	req.NoError(s.Db.Select(&zips, "SELECT zip_code FROM city ORDER BY id"))
+	req.True(strings.HasPrefix(zips[0], "k2:"))
+	req.True(strings.HasPrefix(zips[1], "k1:"))
+
+	// randomized encrypted columns can not be matched by example
+	err = a.FindByExample(ctx, &list, &EncryptedCity{ZipCode: "95120"}, "city", nil)
+	req.Error(err)
+
+	// deterministic encryption allows equality lookups
+	for _, p := range []EncryptedPerson{
+		{FirstName: "foo", LastName: "test", Email: "foo@test"},
+		{FirstName: "bar", LastName: "test", Email: "bar@test"},
+	} {
+		p := p
+		req.NoError(a.Create(ctx, &p, "person", "FirstName", "LastName"))
+	}
+
+	var p EncryptedPerson
+	err = a.FindByExample(ctx, &p, &EncryptedPerson{Email: "bar@test"}, "person", &ExampleOptions{Like: true})
+	req.NoError(err)
+	req.Equal("bar", p.FirstName)
+	req.Equal("bar@test", p.Email)
+
+	// lookups match values encrypted with retired keys
+	keys.CurrentKeyId = "k1"
+	p = EncryptedPerson{}
+	err = a.FindByExample(ctx, &p, &EncryptedPerson{Email: "bar@test"}, "person", nil)
+	req.NoError(err)
+	req.Equal("bar", p.FirstName)
+
+	var people []EncryptedPerson
+	_, err = a.UpdateWhere(ctx, EncryptedPerson{}, "person",
+		map[string]any{"email": "baz@test"},
+		squirrel.Eq{"first_name": "foo"},
+	)
+	req.NoError(err)
+
+	// raw queries need the table to decrypt values
+	err = a.Select(ctx, &people, "SELECT * FROM person ORDER BY first_name")
+	req.Error(err)
+
+	req.NoError(a.Select(WithTable(ctx, "person"), &people, "SELECT * FROM person ORDER BY first_name"))
+	req.Equal(2, len(people))
+	req.Equal("bar@test", people[0].Email)
+	req.Equal("baz@test", people[1].Email)
+
+	// ciphertext copied to another column can not be decrypted
+	_ = s.Db.MustExec(`UPDATE person SET last_name = email`)
+	type swapped struct {
+		FirstName string `db:"first_name"`
+		LastName  string `db:"last_name,encrypted"`
+	}
+	var rows []swapped
+	err = a.Select(WithTable(ctx, "person"), &rows, "SELECT first_name, last_name FROM person")
+	req.Error(err)
+}
+
+func (s *AccessorTestSuite) TestEncryptedColumnsScanned() {
+	req := require.New(s.T())
+
+	FieldEncryptor = NewAESGCMEncryptor(&StaticKeyProvider{
+		CurrentKeyId: "k1",
+		Keys: map[string][]byte{
+			"k1": []byte("0123456789abcdef0123456789abcdef"),
+		},
+	})
+	defer func() {
+		FieldEncryptor = nil
+		s.setupTestDatabase()
+	}()
+
+	s.setupCompositeTables()
+	defer s.teardownCompositeTables()
+
+	_ = s.Db.MustExec(`DELETE FROM person`)
+
+	a := New(s.Db)
+	ctx := context.Background()
+
+	req.NoError(a.Create(ctx, &EncryptedPerson{FirstName: "foo", LastName: "test", Email: "foo@test"}, "person", "FirstName", "LastName"))
+
+	// encrypted columns that are not selected are left alone
+	var people []EncryptedPerson
+	req.NoError(a.Select(ctx, &people, "SELECT first_name, last_name FROM person"))
+	req.Equal(1, len(people))
+	req.Equal("foo", people[0].FirstName)
+	req.Equal("", people[0].Email)
+
+	var p EncryptedPerson
+	req.NoError(a.Get(WithTable(ctx, "person"), &p, "SELECT email FROM person"))
+	req.Equal("foo@test", p.Email)
+
+	// rows of tables other than the base table do not carry the encrypted column
+	e := EncryptedChildEntity{EncryptedBaseEntity{Name: "secret"}, "red"}
+	req.NoError(a.Create(ctx, &e, "child"))
+	req.Equal("secret", e.Name)
+
+	var name string
+	req.NoError(s.Db.Get(&name, "SELECT name FROM base WHERE id = ?", e.Id))
+	req.True(strings.HasPrefix(name, "k1:"))
+
+	e.Name = "classified"
+	e.ChildAttr = "blue"
+	req.NoError(a.UpdateReturning(ctx, &e, "child"))
+	req.Equal("classified", e.Name)
+	req.Equal("blue", e.ChildAttr)
+
+	r := EncryptedChildEntity{EncryptedBaseEntity: EncryptedBaseEntity{Id: e.Id}}
+	req.NoError(a.Read(ctx, &r, "child"))
+	req.Equal(e, r)
+}
+
+func (s *AccessorTestSuite) TestEncryptedScannerColumns() {
+	req := require.New(s.T())
+
+	FieldEncryptor = NewAESGCMEncryptor(&StaticKeyProvider{
+		CurrentKeyId: "k1",
+		Keys: map[string][]byte{
+			"k1": []byte("0123456789abcdef0123456789abcdef"),
+		},
+	})
+	defer func() {
+		FieldEncryptor = nil
+		s.setupTestDatabase()
+	}()
+
+	a := New(s.Db)
+	ctx := context.Background()
+
+	city := EncryptedNullCity{
+		Name:    sql.NullString{String: "San Jose", Valid: true},
+		ZipCode: NewNull("95120"),
+	}
+	req.NoError(a.Create(ctx, &city, "city"))
+	req.Equal("San Jose", city.Name.String)
+	req.Equal(NewNull("95120"), city.ZipCode)
+
+	var name string
+	req.NoError(s.Db.Get(&name, "SELECT name FROM city WHERE id = ?", city.Id))
+	req.True(strings.HasPrefix(name, "k1:"))
+
+	c := EncryptedNullCity{Id: city.Id}
+	req.NoError(a.Read(ctx, &c, "city"))
+	req.Equal(city, c)
+
+	var found EncryptedNullCity
+	req.NoError(a.FindByExample(ctx, &found, &EncryptedNullCity{ZipCode: NewNull("95120")}, "city", nil))
+	req.Equal(city, found)
+
+	// NULL values are kept NULL
+	empty := EncryptedNullCity{}
+	req.NoError(a.Create(ctx, &empty, "city"))
+
+	c = EncryptedNullCity{Id: empty.Id}
+	req.NoError(a.Read(ctx, &c, "city"))
+	req.False(c.Name.Valid)
+	req.False(c.ZipCode.Valid)
+}
+
+func (s *AccessorTestSuite) TestEncryptedKeyColumns() {
+	req := require.New(s.T())
+
+	keys := &StaticKeyProvider{
+		CurrentKeyId: "k1",
+		Keys: map[string][]byte{
+			"k1": []byte("0123456789abcdef0123456789abcdef"),
+			"k2": []byte("fedcba9876543210fedcba9876543210"),
+		},
+	}
+	FieldEncryptor = NewAESGCMEncryptor(keys)
+	defer func() {
+		FieldEncryptor = nil
+		s.setupTestDatabase()
+	}()
+
+	_ = s.Db.MustExec(`DELETE FROM person`)
+
+	a := New(s.Db)
+	ctx := context.Background()
+
+	p := EncryptedPerson{FirstName: "foo", LastName: "test", Email: "foo@test"}
+	req.NoError(a.Create(ctx, &p, "person", "Email"))
+
+	var email string
+	req.NoError(s.Db.Get(&email, "SELECT email FROM person"))
+	req.True(strings.HasPrefix(email, "k1:"))
+
+	// key values are encrypted in key predicates, under every key in use
+	keys.CurrentKeyId = "k2"
+
+	r := EncryptedPerson{Email: "foo@test"}
+	req.NoError(a.Read(ctx, &r, "person", "Email"))
+	req.Equal(p, r)
+
+	p.FirstName = "bar"
+	result, err := a.Update(ctx, &p, "person", "Email")
+	req.NoError(err)
+	n, _ := result.RowsAffected()
+	req.Equal(int64(1), n)
+
+	r = EncryptedPerson{Email: "foo@test"}
+	req.NoError(a.Read(ctx, &r, "person", "Email"))
+	req.Equal("bar", r.FirstName)
+
+	result, err = a.Delete(ctx, &EncryptedPerson{Email: "foo@test"}, "person", "Email")
+	req.NoError(err)
+	n, _ = result.RowsAffected()
+	req.Equal(int64(1), n)
+
+	// randomized encrypted columns can not be keys
+	c := EncryptedCity{Name: "San Jose", ZipCode: "95120"}
+	req.Error(a.Create(ctx, &c, "city", "ZipCode"))
+	req.Error(a.Read(ctx, &EncryptedCity{ZipCode: "95120"}, "city", "ZipCode"))
+}
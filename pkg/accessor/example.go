@@ -0,0 +1,209 @@
+package accessor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx/reflectx"
+)
+
+// ExampleOptions controls how FindByExample turns an example entity into a query
+type ExampleOptions struct {
+	// match string fields with LIKE instead of equality, caller supplies wildcards
+	// in the example value, e.g. "fo%"
+	Like bool
+
+	// ORDER BY expressions, e.g. "person.first_name DESC"
+	OrderBy []string
+
+	Limit  uint64
+	Offset uint64
+
+	// ID fields used to join tables of composite entity, default to "Id"
+	IdFields []string
+}
+
+// FindByExample queries entities that match the example entity.
+//
+// Predicates are derived from non-zero fields of the example. If the example
+// is an UpdateTracker with tracked changes, tracker-changed columns are used instead,
+// which allows to match against zero values. Columns are qualified with the table
+// that owns them in the inheritance chain, so composite entities are supported.
+//
+// dest can either be a pointer to an entity or a pointer to a slice of entities.
+//
+// Usage example:
+/*
+	example := Person{}
+	example.LastName = "test"
+
+	var people []Person
+	err := a.FindByExample(context.Background(), &people, &example, "person", &ExampleOptions{
+		OrderBy: []string{"person.first_name"},
+		Limit:   10,
+	})
+*/
+func (a *Accessor) FindByExample(
+	ctx context.Context,
+	dest any,
+	example any,
+	tbl string,
+	opts *ExampleOptions,
+) error {
+	if opts == nil {
+		opts = &ExampleOptions{}
+	}
+
+	destType := reflect.TypeOf(dest)
+	if destType == nil || destType.Kind() != reflect.Pointer {
+		return errors.New("expecting dest type to be pointer type of the entity or entity slice")
+	}
+
+	where, err := a.examplePredicates(example, tbl, opts.Like)
+	if err != nil {
+		return err
+	}
+
+	sqlizer := func(builder squirrel.SelectBuilder) Sqlizer {
+		if len(where) > 0 {
+			builder = builder.Where(where)
+		}
+		if len(opts.OrderBy) > 0 {
+			builder = builder.OrderBy(opts.OrderBy...)
+		}
+		if opts.Limit > 0 {
+			builder = builder.Limit(opts.Limit)
+		}
+		if opts.Offset > 0 {
+			builder = builder.Offset(opts.Offset)
+		}
+		return builder
+	}
+
+	if reflectx.Deref(destType).Kind() == reflect.Slice {
+		return a.EntitySelect(ctx, dest, tbl, sqlizer, opts.IdFields...)
+	}
+
+	return a.EntityGet(ctx, dest, tbl, sqlizer, opts.IdFields...)
+}
+
+func (a *Accessor) examplePredicates(example any, tbl string, like bool) (squirrel.And, error) {
+	if example == nil {
+		return nil, errors.New("missing example entity")
+	}
+
+	s, err := EntitySchema(example, reflect.TypeOf(example), tbl)
+	if err != nil {
+		return nil, err
+	}
+
+	colValueMap := removeNestedCols(a.fieldMap(reflect.ValueOf(example)))
+
+	tracker, _ := example.(UpdateTracker)
+	if tracker != nil && !hasTrackedChanges(tracker, s) {
+		tracker = nil
+	}
+
+	encrypted := encryptedColumns(s.EntityType)
+
+	where := squirrel.And{}
+	for _, m := range s.Schemas() {
+		var colsChanged []string
+		if tracker != nil {
+			colsChanged = tracker.ColumnsChanged(m.TableName)
+		}
+
+		for _, col := range sortedColumns(m) {
+			v, ok := colValueMap[col]
+			if !ok {
+				continue
+			}
+
+			if tracker != nil {
+				if !stringInSlice(col, colsChanged) {
+					continue
+				}
+			} else if v.IsZero() {
+				continue
+			}
+
+			qualified := fmt.Sprintf("%s.%s", m.TableName, col)
+
+			// only deterministically encrypted columns can be matched
+			if deterministic, ok := encrypted[col]; ok && !deterministic {
+				return nil, fmt.Errorf("column %s is not encrypted deterministically, it can not be matched by example", col)
+			}
+
+			var val any
+			if _, ok := encrypted[col]; ok {
+				val, err = lookupDriverValue(s.EntityType, s.TableName, col, v)
+			} else {
+				val, err = driverValue(s.EntityType, s.TableName, col, v)
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			if _, ok := encrypted[col]; !ok && like && isStringValue(v) && val != nil {
+				where = append(where, squirrel.Like{qualified: val})
+			} else {
+				where = append(where, squirrel.Eq{qualified: val})
+			}
+		}
+	}
+
+	return where, nil
+}
+
+func hasTrackedChanges(tracker UpdateTracker, s *EntityMappingSchema) bool {
+	for _, tbl := range s.Tables() {
+		if len(tracker.ColumnsChanged(tbl)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedColumns returns columns of the schema in field declaration order to
+// keep generated SQL stable
+func sortedColumns(m *EntityMappingSchema) []string {
+	typ := entityType(m.EntityType)
+	if typ == nil {
+		return []string{}
+	}
+
+	return fieldOrderedColumns(typ, m.Columns)
+}
+
+// fieldOrderedColumns returns mapped columns in field declaration order, including
+// columns of embedded types that share the table (single-table inheritance)
+func fieldOrderedColumns(typ reflect.Type, columns map[string]string) []string {
+	cols := []string{}
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+
+		if field.Anonymous {
+			if _, attrs, err := fieldMappedColumnWithAttributes(field, "db"); err == nil {
+				if _, ok := attrs[attrDiscriminator]; ok {
+					if ft := entityType(reflectx.Deref(field.Type)); ft != nil {
+						cols = append(cols, fieldOrderedColumns(ft, columns)...)
+					}
+				}
+			}
+			continue
+		}
+
+		if col, ok := columns[field.Name]; ok {
+			cols = append(cols, col)
+		}
+	}
+	return cols
+}
+
+func isStringValue(v reflect.Value) bool {
+	return reflectx.Deref(v.Type()).Kind() == reflect.String
+}
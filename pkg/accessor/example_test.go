@@ -0,0 +1,124 @@
+package accessor
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/require"
+)
+
+func (s *AccessorTestSuite) setupCompositeTables() {
+	_ = s.Db.MustExec(`
+CREATE TABLE IF NOT EXISTS base (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name text
+);
+
+CREATE TABLE IF NOT EXISTS child (
+    id integer primary key,
+    child_attr text
+);
+
+CREATE TABLE IF NOT EXISTS grand_child (
+    id integer primary key,
+    grand_child_attr text
+);
+    `)
+
+	a := New(s.Db)
+	for _, e := range []GrandChildEntity{
+		{ChildEntity{BaseEntity{Name: "foo"}, "red"}, "apple"},
+		{ChildEntity{BaseEntity{Name: "bar"}, "red"}, "cherry"},
+		{ChildEntity{BaseEntity{Name: "baz"}, "green"}, "pear"},
+	} {
+		e := e
+		if err := a.Create(context.Background(), &e, "grand_child"); err != nil {
+			s.T().Fatal(err)
+		}
+	}
+}
+
+func (s *AccessorTestSuite) teardownCompositeTables() {
+	_ = s.Db.MustExec(`
+DROP TABLE IF EXISTS grand_child;
+DROP TABLE IF EXISTS child;
+DROP TABLE IF EXISTS base;
+    `)
+}
+
+func (s *AccessorTestSuite) TestFindByExample() {
+	req := require.New(s.T())
+
+	a := New(s.Db)
+
+	example := Person{}
+	example.LastName = "test"
+
+	var p []Person
+	err := a.FindByExample(context.Background(), &p, &example, "person", &ExampleOptions{
+		OrderBy: []string{"person.first_name"},
+	})
+	req.NoError(err)
+	req.Equal(2, len(p))
+	req.Equal("bar", p[0].FirstName)
+	req.Equal("foo", p[1].FirstName)
+
+	example.FirstName = "foo"
+	pp := Person{}
+	err = a.FindByExample(context.Background(), &pp, &example, "person", nil)
+	req.NoError(err)
+	req.Equal("foo@test", pp.Email)
+
+	// LIKE matching, ordering and limit
+	example = Person{Email: "%@test"}
+	p = nil
+	err = a.FindByExample(context.Background(), &p, &example, "person", &ExampleOptions{
+		Like:    true,
+		OrderBy: []string{"person.first_name DESC"},
+		Limit:   1,
+	})
+	req.NoError(err)
+	req.Equal(1, len(p))
+	req.Equal("foo", p[0].FirstName)
+
+	// zero value can only be matched via tracked changes
+	tracked := &PersonWithUpdateTracker{}
+	tracked.SetEmail("")
+	p = nil
+	err = a.FindByExample(context.Background(), &p, tracked, "person", nil)
+	req.NoError(err)
+	req.Equal(0, len(p))
+
+	pp = Person{}
+	err = a.FindByExample(context.Background(), &pp, &Person{FirstName: "none"}, "person", nil)
+	req.Error(err)
+}
+
+func (s *AccessorTestSuite) TestFindByExampleComposite() {
+	req := require.New(s.T())
+
+	s.setupCompositeTables()
+	defer s.teardownCompositeTables()
+
+	a := New(s.Db)
+
+	example := GrandChildEntity{}
+	example.ChildAttr = "red"
+
+	var list []GrandChildEntity
+	err := a.FindByExample(context.Background(), &list, &example, "grand_child", &ExampleOptions{
+		OrderBy: []string{"base.name"},
+	})
+	req.NoError(err)
+	req.Equal(2, len(list))
+	req.Equal("bar", list[0].Name)
+	req.Equal("cherry", list[0].GrandChildAttr)
+	req.Equal("foo", list[1].Name)
+	req.Equal("apple", list[1].GrandChildAttr)
+
+	example.Name = "foo"
+	e := GrandChildEntity{}
+	err = a.FindByExample(context.Background(), &e, &example, "grand_child", nil)
+	req.NoError(err)
+	req.Equal("apple", e.GrandChildAttr)
+	req.True(e.Id != 0)
+}
@@ -0,0 +1,80 @@
+package accessor
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// attribute of embedded type that maps key columns of the table of embedding type onto
+// key columns of the table of embedded type, e.g. `db:",table=person,join=person_id:id"`
+// joins employee.person_id to person.id. Multiple column pairs are separated by semicolons,
+// e.g. join=org_id:org_id;person_no:no
+const attrJoin = "join"
+
+// parseJoinColumns returns column of embedded type table -> column of embedding type table
+func parseJoinColumns(join string) (map[string]string, error) {
+	cols := map[string]string{}
+
+	for _, pair := range strings.Split(join, ";") {
+		tokens := strings.Split(strings.TrimSpace(pair), ":")
+		if len(tokens) != 2 || tokens[0] == "" || tokens[1] == "" {
+			return nil, fmt.Errorf("invalid join column mapping %q", pair)
+		}
+		cols[tokens[1]] = tokens[0]
+	}
+
+	return cols, nil
+}
+
+// keyColumns returns key columns of table TableName in the inheritance chain, given ID
+// columns of the root table. A table is joined to its base table on columns of the same
+// names, unless join attribute maps them differently.
+func (m *EntityMappingSchema) keyColumns(idColumns []string) []string {
+	if len(m.BaseMappings) == 0 {
+		return idColumns
+	}
+
+	baseKeys := m.BaseMappings[0].keyColumns(idColumns)
+	if len(m.JoinColumns) == 0 {
+		return baseKeys
+	}
+
+	keys := make([]string, len(baseKeys))
+	for i, col := range baseKeys {
+		if c, ok := m.JoinColumns[col]; ok {
+			keys[i] = c
+		} else {
+			keys[i] = col
+		}
+	}
+	return keys
+}
+
+// tableKeyValues maps values of ID columns of the root table onto key columns of a table
+// in the inheritance chain
+func tableKeyValues(
+	colValueMap map[string]reflect.Value,
+	idColumns []string,
+	keyColumns []string,
+) map[string]reflect.Value {
+	keyValueMap := map[string]reflect.Value{}
+	for i, col := range idColumns {
+		if v, ok := colValueMap[col]; ok {
+			keyValueMap[keyColumns[i]] = v
+		}
+	}
+	return keyValueMap
+}
+
+func getKeyJoinOnString(table1 string, keys1 []string, table2 string, keys2 []string) string {
+	var builder strings.Builder
+
+	for i := range keys1 {
+		builder.WriteString(fmt.Sprintf("%s.%s=%s.%s", table1, keys1[i], table2, keys2[i]))
+		if i < len(keys1)-1 {
+			builder.WriteString(" AND ")
+		}
+	}
+	return builder.String()
+}
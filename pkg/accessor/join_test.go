@@ -0,0 +1,125 @@
+package accessor
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+)
+
+type LegacyChildEntity struct {
+	BaseEntity `db:",table=legacy_base,join=base_id:id"`
+	ChildAttr  string `db:"child_attr"`
+}
+
+type LegacyGrandChildEntity struct {
+	LegacyChildEntity `db:",table=legacy_child,join=child_id:base_id"`
+	GrandChildAttr    string `db:"grand_child_attr"`
+}
+
+type LegacyChildEntityWithInvalidJoin struct {
+	BaseEntity `db:",table=legacy_base,join=base_id"`
+	ChildAttr  string `db:"child_attr"`
+}
+
+func (s *AccessorTestSuite) TestJoinColumnsSchema() {
+	req := require.New(s.T())
+
+	e := LegacyGrandChildEntity{}
+	schema, err := EntitySchema(e, reflect.TypeOf(e), "legacy_grand_child")
+	req.NoError(err)
+
+	req.Equal([]string{"child_id"}, schema.keyColumns([]string{"id"}))
+	req.Equal(
+		"legacy_child ON legacy_base.id=legacy_child.base_id JOIN legacy_grand_child ON legacy_child.base_id=legacy_grand_child.child_id",
+		schema.GetTableJoinString("id"),
+	)
+
+	_, err = EntitySchema(LegacyChildEntityWithInvalidJoin{}, reflect.TypeOf(LegacyChildEntityWithInvalidJoin{}), "legacy_child")
+	req.Error(err)
+}
+
+func (s *AccessorTestSuite) TestJoinColumns() {
+	req := require.New(s.T())
+
+	_ = s.Db.MustExec(`
+CREATE TABLE IF NOT EXISTS legacy_base (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name text
+);
+
+CREATE TABLE IF NOT EXISTS legacy_child (
+    base_id integer primary key,
+    child_attr text
+);
+
+CREATE TABLE IF NOT EXISTS legacy_grand_child (
+    child_id integer primary key,
+    grand_child_attr text
+);
+    `)
+	defer func() {
+		_ = s.Db.MustExec(`
+DROP TABLE IF EXISTS legacy_grand_child;
+DROP TABLE IF EXISTS legacy_child;
+DROP TABLE IF EXISTS legacy_base;
+    `)
+	}()
+
+	a := New(s.Db)
+	ctx := context.Background()
+
+	e := LegacyGrandChildEntity{
+		LegacyChildEntity: LegacyChildEntity{BaseEntity: BaseEntity{Name: "foo"}, ChildAttr: "red"},
+		GrandChildAttr:    "apple",
+	}
+	req.NoError(a.Create(ctx, &e, "legacy_grand_child"))
+	req.True(e.Id > 0)
+
+	var childIds []int
+	req.NoError(a.Select(ctx, &childIds, "SELECT child_id FROM legacy_grand_child"))
+	req.Equal([]int{e.Id}, childIds)
+
+	r := LegacyGrandChildEntity{}
+	r.Id = e.Id
+	req.NoError(a.Read(ctx, &r, "legacy_grand_child"))
+	req.Equal(e, r)
+
+	e.ChildAttr = "green"
+	e.GrandChildAttr = "pear"
+	_, err := a.Update(ctx, &e, "legacy_grand_child")
+	req.NoError(err)
+
+	var list []LegacyGrandChildEntity
+	req.NoError(a.EntitySelect(ctx, &list, "legacy_grand_child", func(builder squirrel.SelectBuilder) Sqlizer {
+		return builder.Where(squirrel.Eq{"legacy_child.child_attr": "green"})
+	}))
+	req.Equal([]LegacyGrandChildEntity{e}, list)
+
+	_, err = a.UpdateWhere(ctx, LegacyGrandChildEntity{}, "legacy_grand_child",
+		map[string]any{"child_attr": "blue"},
+		squirrel.Eq{"legacy_base.name": "foo"},
+	)
+	req.NoError(err)
+
+	r = LegacyGrandChildEntity{}
+	r.Id = e.Id
+	req.NoError(a.Read(ctx, &r, "legacy_grand_child"))
+	req.Equal("blue", r.ChildAttr)
+	req.Equal("pear", r.GrandChildAttr)
+
+	_, err = a.Delete(ctx, &r, "legacy_grand_child")
+	req.NoError(err)
+
+	for _, tbl := range []string{"legacy_base", "legacy_child", "legacy_grand_child"} {
+		var cnt int
+		req.NoError(a.Get(ctx, &cnt, "SELECT count(*) FROM "+tbl))
+		req.Equal(0, cnt, tbl)
+	}
+
+	r = LegacyGrandChildEntity{}
+	r.Id = e.Id
+	req.ErrorIs(a.Read(ctx, &r, "legacy_grand_child"), sql.ErrNoRows)
+}
@@ -0,0 +1,307 @@
+package accessor
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/reflectx"
+)
+
+const attrJson = "json"
+
+// JsonPatch is a partial update of a JSON column, Value is marshaled into JSON and
+// set at Path, e.g. []string{"address", "city"}
+type JsonPatch struct {
+	Path  []string
+	Value any
+}
+
+// JsonPatchTracker is implemented by generated update trackers of entities with json
+// columns. When patches are registered on a changed json column, Update sets them with
+// jsonb_set (Postgres) or json_set (SQLite) instead of replacing the whole document.
+type JsonPatchTracker interface {
+	JsonPatches(tbl string, col string) []JsonPatch
+}
+
+var jsonColumnsCache sync.Map
+
+// jsonColumns returns columns tagged with json attribute in entity type
+func jsonColumns(typ reflect.Type) map[string]bool {
+	typ = reflectx.Deref(typ)
+	if cols, ok := jsonColumnsCache.Load(typ); ok {
+		return cols.(map[string]bool)
+	}
+
+	cols := map[string]bool{}
+	for col, attrs := range entityColumnAttributes(typ) {
+		if _, ok := attrs[attrJson]; ok {
+			cols[col] = true
+		}
+	}
+
+	jsonColumnsCache.Store(typ, cols)
+	return cols
+}
+
+// destJsonColumns returns json columns of entity type of dest, which can be a pointer
+// to an entity or to a slice of entities
+func destJsonColumns(dest any) map[string]bool {
+	typ := reflectx.Deref(reflect.TypeOf(dest))
+	if typ.Kind() == reflect.Slice {
+		typ = reflectx.Deref(typ.Elem())
+	}
+
+	if typ.Kind() != reflect.Struct {
+		return nil
+	}
+	return jsonColumns(typ)
+}
+
+// jsonValue marshals field value into JSON text, nil pointers, maps and slices are stored as NULL
+func jsonValue(v reflect.Value) (any, error) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+	}
+
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+type jsonScanner struct {
+	col       string
+	v         reflect.Value
+	encrypted bool
+	aad       []byte
+}
+
+func (s *jsonScanner) Scan(src any) error {
+	var data []byte
+	switch t := src.(type) {
+	case nil:
+		s.v.Set(reflect.Zero(s.v.Type()))
+		return nil
+	case []byte:
+		data = t
+	case string:
+		data = []byte(t)
+	default:
+		return fmt.Errorf("unsupported type %T of json column %s", src, s.col)
+	}
+
+	if s.encrypted {
+		var err error
+		if data, err = decryptBytes(s.col, s.aad, data); err != nil {
+			return err
+		}
+	}
+
+	target := reflect.New(s.v.Type())
+	if err := json.Unmarshal(data, target.Interface()); err != nil {
+		return fmt.Errorf("unmarshal json column %s: %w", s.col, err)
+	}
+
+	s.v.Set(target.Elem())
+	return nil
+}
+
+// structScan works as sqlx StructScan, values of json columns are unmarshaled into fields
+// and values of encrypted columns of table tbl are decrypted, only columns present in the
+// row are touched
+func (a *Accessor) structScan(rows *sqlx.Rows, dest any, tbl string) error {
+	v := reflect.ValueOf(dest)
+
+	jsonCols := destJsonColumns(dest)
+	encrypted := destEncryptedColumns(dest)
+	if len(jsonCols) == 0 && len(encrypted) == 0 {
+		return rows.StructScan(dest)
+	}
+
+	if v.Kind() != reflect.Ptr {
+		return errors.New("must pass a pointer, not a value, to StructScan destination")
+	}
+	if v.IsNil() {
+		return errors.New("nil pointer passed to StructScan destination")
+	}
+	direct := v.Elem()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	return rows.Scan(scanTargets(direct, tbl, columns, a.mapper().TraversalsByName(direct.Type(), columns))...)
+}
+
+// scanTargets returns scan destinations of columns in fields of struct value direct that
+// traversals lead to, values of json columns are unmarshaled and values of encrypted columns
+// of table tbl are decrypted as they are scanned
+func scanTargets(direct reflect.Value, tbl string, columns []string, traversals [][]int) []any {
+	jsonCols := jsonColumns(direct.Type())
+	encrypted := encryptedColumns(direct.Type())
+
+	values := make([]any, len(columns))
+	for i, traversal := range traversals {
+		if len(traversal) == 0 {
+			// silently drop unmatched columns as unsafe sqlx does
+			values[i] = new(any)
+			continue
+		}
+
+		f := reflectx.FieldByIndexes(direct, traversal)
+		_, isEncrypted := encrypted[columns[i]]
+
+		var aad []byte
+		if isEncrypted {
+			aad = encryptionAad(direct.Type(), tbl, columns[i])
+		}
+
+		if jsonCols[columns[i]] {
+			values[i] = &jsonScanner{col: columns[i], v: f, encrypted: isEncrypted, aad: aad}
+		} else if isEncrypted {
+			values[i] = &encryptedScanner{col: columns[i], aad: aad, v: f}
+		} else {
+			values[i] = f.Addr().Interface()
+		}
+	}
+
+	return values
+}
+
+// scanOne scans the first row of table tbl into dest
+func (a *Accessor) scanOne(rows *sqlx.Rows, dest any, tbl string) error {
+	defer rows.Close()
+
+	for rows.Next() {
+		return a.structScan(rows, dest, tbl)
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	return sql.ErrNoRows
+}
+
+// scanAll scans all rows of table tbl into dest, which must be a pointer to a slice
+func (a *Accessor) scanAll(rows *sqlx.Rows, dest any, tbl string) error {
+	defer rows.Close()
+
+	value := reflect.ValueOf(dest)
+	if value.Kind() != reflect.Ptr {
+		return errors.New("must pass a pointer, not a value, to StructScan destination")
+	}
+	if value.IsNil() {
+		return errors.New("nil pointer passed to StructScan destination")
+	}
+	direct := reflect.Indirect(value)
+
+	slice, err := baseType(value.Type(), reflect.Slice)
+	if err != nil {
+		return err
+	}
+	direct.SetLen(0)
+	isPtr := slice.Elem().Kind() == reflect.Ptr
+	base := reflectx.Deref(slice.Elem())
+
+	for rows.Next() {
+		elem := reflect.New(base)
+		err := a.structScan(rows, elem.Interface(), tbl)
+		if err != nil {
+			return err
+		}
+
+		if isPtr {
+			direct.Set(reflect.Append(direct, elem))
+		} else {
+			direct.Set(reflect.Append(direct, reflect.Indirect(elem)))
+		}
+	}
+
+	return rows.Err()
+}
+
+// jsonPatchExpr generates the SET expression that applies patches to a json column
+func jsonPatchExpr(driverName string, col string, patches []JsonPatch) (Sqlizer, error) {
+	var expr string
+	var args []any
+
+	switch driverName {
+	case "postgres", "pgx":
+		// patch json and text columns as jsonb, the result is cast back on assignment
+		expr = fmt.Sprintf("COALESCE(%s::jsonb, '{}'::jsonb)", col)
+	case "sqlite3":
+		expr = fmt.Sprintf("COALESCE(%s, '{}')", col)
+	default:
+		return nil, fmt.Errorf("partial update of json column %s is not supported with driver %s", col, driverName)
+	}
+
+	for _, patch := range patches {
+		if len(patch.Path) == 0 {
+			return nil, fmt.Errorf("missing path in patch of json column %s", col)
+		}
+
+		data, err := json.Marshal(patch.Value)
+		if err != nil {
+			return nil, fmt.Errorf("marshal patch of json column %s: %w", col, err)
+		}
+
+		if driverName == "sqlite3" {
+			expr = fmt.Sprintf("json_set(%s, ?, json(?))", expr)
+			args = append(args, sqliteJsonPath(patch.Path), string(data))
+		} else {
+			expr = fmt.Sprintf("jsonb_set(%s, ?, ?::jsonb, true)", expr)
+			args = append(args, postgresTextArray(patch.Path), string(data))
+		}
+	}
+
+	return squirrel.Expr(expr, args...), nil
+}
+
+func postgresTextArray(path []string) string {
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+	elems := make([]string, len(path))
+	for i, p := range path {
+		elems[i] = `"` + escaper.Replace(p) + `"`
+	}
+	return "{" + strings.Join(elems, ",") + "}"
+}
+
+func sqliteJsonPath(path []string) string {
+	var builder strings.Builder
+
+	builder.WriteString("$")
+	for _, p := range path {
+		if isArrayIndex(p) {
+			builder.WriteString("[" + p + "]")
+		} else {
+			builder.WriteString(`."` + strings.ReplaceAll(p, `"`, `\"`) + `"`)
+		}
+	}
+	return builder.String()
+}
+
+func isArrayIndex(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
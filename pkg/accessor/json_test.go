@@ -0,0 +1,106 @@
+package accessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+)
+
+type JsonChildEntity struct {
+	BaseEntity `db:",table=base"`
+	Tags       []string       `db:"tags,json"`
+	Meta       map[string]any `db:"meta,json"`
+}
+
+func Test_jsonPatchExpr(t *testing.T) {
+	req := require.New(t)
+
+	patches := []JsonPatch{
+		{Path: []string{"address", "city"}, Value: "San Jose"},
+		{Path: []string{"tags", "0"}, Value: map[string]int{"n": 1}},
+	}
+
+	expr, err := jsonPatchExpr("postgres", "profile", patches)
+	req.NoError(err)
+	sql, args, err := expr.ToSql()
+	req.NoError(err)
+	req.Equal("jsonb_set(jsonb_set(COALESCE(profile::jsonb, '{}'::jsonb), ?, ?::jsonb, true), ?, ?::jsonb, true)", sql)
+	req.Equal([]any{`{"address","city"}`, `"San Jose"`, `{"tags","0"}`, `{"n":1}`}, args)
+
+	expr, err = jsonPatchExpr("sqlite3", "profile", patches)
+	req.NoError(err)
+	sql, args, err = expr.ToSql()
+	req.NoError(err)
+	req.Equal("json_set(json_set(COALESCE(profile, '{}'), ?, json(?)), ?, json(?))", sql)
+	req.Equal([]any{`$."address"."city"`, `"San Jose"`, `$."tags"[0]`, `{"n":1}`}, args)
+
+	_, err = jsonPatchExpr("mysql", "profile", patches)
+	req.Error(err)
+
+	_, err = jsonPatchExpr("postgres", "profile", []JsonPatch{{Value: 1}})
+	req.Error(err)
+}
+
+func (s *AccessorTestSuite) TestJsonColumnsComposite() {
+	req := require.New(s.T())
+
+	_ = s.Db.MustExec(`
+CREATE TABLE IF NOT EXISTS base (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name text
+);
+
+CREATE TABLE IF NOT EXISTS json_child (
+    id integer primary key,
+    tags text,
+    meta text
+);
+    `)
+	defer func() {
+		_ = s.Db.MustExec(`
+DROP TABLE IF EXISTS json_child;
+DROP TABLE IF EXISTS base;
+    `)
+	}()
+
+	a := New(s.Db)
+	ctx := context.Background()
+
+	e := JsonChildEntity{
+		BaseEntity: BaseEntity{Name: "foo"},
+		Tags:       []string{"a", "b"},
+		Meta:       map[string]any{"size": float64(3)},
+	}
+	req.NoError(a.Create(ctx, &e, "json_child"))
+	req.True(e.Id > 0)
+
+	r := JsonChildEntity{}
+	r.Id = e.Id
+	req.NoError(a.Read(ctx, &r, "json_child"))
+	req.Equal(e, r)
+
+	e.Tags = nil
+	e.Meta["color"] = "red"
+	_, err := a.Update(ctx, &e, "json_child")
+	req.NoError(err)
+
+	var list []JsonChildEntity
+	req.NoError(a.Select(ctx, &list, "SELECT * FROM base JOIN json_child ON base.id = json_child.id"))
+	req.Equal(1, len(list))
+	req.Equal("foo", list[0].Name)
+	req.Nil(list[0].Tags)
+	req.Equal(map[string]any{"size": float64(3), "color": "red"}, list[0].Meta)
+
+	_, err = a.UpdateWhere(ctx, JsonChildEntity{}, "json_child",
+		map[string]any{"tags": []string{"c"}},
+		squirrel.Eq{"base.name": "foo"},
+	)
+	req.NoError(err)
+
+	r = JsonChildEntity{}
+	r.Id = e.Id
+	req.NoError(a.Read(ctx, &r, "json_child"))
+	req.Equal([]string{"c"}, r.Tags)
+}
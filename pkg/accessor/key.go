@@ -0,0 +1,38 @@
+package accessor
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// entityKey returns values of ID fields of entity
+func entityKey(a *Accessor, entity any, idFields []string) ([]any, error) {
+	idColumns, colValueMap, err := a.getMapping(entity, idFields...)
+	if err != nil {
+		return nil, err
+	}
+
+	key := make([]any, len(idColumns))
+	for i, col := range idColumns {
+		v, ok := colValueMap[col]
+		if !ok {
+			return nil, errors.New("missing ID columns")
+		}
+		key[i] = getDriverValue(v)
+	}
+
+	return key, nil
+}
+
+// keyString joins values of a key into a string, which keys caches and identity maps
+func keyString(key []any) string {
+	parts := make([]string, len(key))
+	for i, v := range key {
+		if b, ok := v.([]byte); ok {
+			v = string(b)
+		}
+		parts[i] = fmt.Sprint(v)
+	}
+	return strings.Join(parts, "\x00")
+}
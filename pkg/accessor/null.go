@@ -0,0 +1,319 @@
+package accessor
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// ZeroPointerAsNull turns on legacy NULL handling, in which non-nil pointers to zero
+// values (e.g. Age *int pointing to 0) are written as NULL. By default, only nil
+// pointers are written as NULL and pointers to zero values are written as zero values.
+var ZeroPointerAsNull = false
+
+// Null represents a value of T that may be NULL. T can be any primitive type, time.Time,
+// []byte or a named type of them (e.g. type Mood string), values are converted the same
+// way with all drivers. Null is marshaled into JSON as either its value or null.
+//
+// Usage example:
+/*
+	type Person struct {
+		Id          int                      `db:"id"`
+		Age         accessor.Null[int]       `db:"age"`
+		CurrentMood accessor.Null[Mood]      `db:"current_mood"`
+		AddedAt     accessor.Null[time.Time] `db:"added_at"`
+	}
+
+	p.CurrentMood = accessor.NewNull(Mood("happy"))
+*/
+type Null[T any] struct {
+	V     T
+	Valid bool
+}
+
+// NewNull returns a valid Null holding v
+func NewNull[T any](v T) Null[T] {
+	return Null[T]{V: v, Valid: true}
+}
+
+// Scan implements the sql.Scanner interface.
+func (n *Null[T]) Scan(value any) error {
+	var zero T
+
+	if value == nil {
+		n.V, n.Valid = zero, false
+		return nil
+	}
+
+	if scanner, ok := any(&n.V).(sql.Scanner); ok {
+		if err := scanner.Scan(value); err != nil {
+			return err
+		}
+	} else if err := convertValue(reflect.ValueOf(&n.V).Elem(), value); err != nil {
+		n.V = zero
+		return err
+	}
+
+	n.Valid = true
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (n Null[T]) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+
+	if valuer, ok := any(n.V).(driver.Valuer); ok {
+		return valuer.Value()
+	}
+	return primitiveValue(reflect.ValueOf(n.V))
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (n Null[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.V)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (n *Null[T]) UnmarshalJSON(data []byte) error {
+	var zero T
+
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		n.V, n.Valid = zero, false
+		return nil
+	}
+
+	if err := json.Unmarshal(data, &n.V); err != nil {
+		n.V, n.Valid = zero, false
+		return err
+	}
+
+	n.Valid = true
+	return nil
+}
+
+// time layouts that drivers may return time values in, when columns are declared as text
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02T15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+// primitiveValue converts v into one of driver.Value types
+func primitiveValue(v reflect.Value) (driver.Value, error) {
+	if v.Type().ConvertibleTo(timeType) && v.Kind() == reflect.Struct {
+		return v.Convert(timeType).Interface(), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u := v.Uint()
+		if u > math.MaxInt64 {
+			return nil, fmt.Errorf("uint64 value %d is too large", u)
+		}
+		return int64(u), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Bytes(), nil
+		}
+	}
+
+	return nil, fmt.Errorf("unsupported type %s", v.Type())
+}
+
+// convertValue converts driver value src into dest, which must be settable
+func convertValue(dest reflect.Value, src any) error {
+	unsupported := fmt.Errorf("converting %T to %s is unsupported", src, dest.Type())
+
+	if dest.Kind() == reflect.Struct {
+		if !dest.Type().ConvertibleTo(timeType) {
+			return unsupported
+		}
+
+		var t time.Time
+		switch s := src.(type) {
+		case time.Time:
+			t = s
+		case string:
+			var err error
+			if t, err = parseTime(s); err != nil {
+				return err
+			}
+		case []byte:
+			var err error
+			if t, err = parseTime(string(s)); err != nil {
+				return err
+			}
+		default:
+			return unsupported
+		}
+
+		dest.Set(reflect.ValueOf(t).Convert(dest.Type()))
+		return nil
+	}
+
+	var text string
+	switch s := src.(type) {
+	case string:
+		text = s
+	case []byte:
+		text = string(s)
+	}
+
+	switch dest.Kind() {
+	case reflect.String:
+		switch s := src.(type) {
+		case string, []byte:
+			dest.SetString(text)
+		case int64:
+			dest.SetString(strconv.FormatInt(s, 10))
+		case float64:
+			dest.SetString(strconv.FormatFloat(s, 'g', -1, 64))
+		case bool:
+			dest.SetString(strconv.FormatBool(s))
+		case time.Time:
+			dest.SetString(s.Format(time.RFC3339Nano))
+		default:
+			return unsupported
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		switch s := src.(type) {
+		case int64:
+			i = s
+		case float64:
+			if s != math.Trunc(s) {
+				return fmt.Errorf("converting %v to %s loses precision", s, dest.Type())
+			}
+			i = int64(s)
+		case bool:
+			if s {
+				i = 1
+			}
+		case string, []byte:
+			var err error
+			if i, err = strconv.ParseInt(text, 10, dest.Type().Bits()); err != nil {
+				return fmt.Errorf("converting %q to %s: %w", text, dest.Type(), err)
+			}
+		default:
+			return unsupported
+		}
+		if dest.OverflowInt(i) {
+			return fmt.Errorf("value %d overflows %s", i, dest.Type())
+		}
+		dest.SetInt(i)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var u uint64
+		switch s := src.(type) {
+		case int64:
+			if s < 0 {
+				return fmt.Errorf("converting %d to %s is out of range", s, dest.Type())
+			}
+			u = uint64(s)
+		case float64:
+			if s < 0 || s != math.Trunc(s) {
+				return fmt.Errorf("converting %v to %s loses precision", s, dest.Type())
+			}
+			u = uint64(s)
+		case string, []byte:
+			var err error
+			if u, err = strconv.ParseUint(text, 10, dest.Type().Bits()); err != nil {
+				return fmt.Errorf("converting %q to %s: %w", text, dest.Type(), err)
+			}
+		default:
+			return unsupported
+		}
+		if dest.OverflowUint(u) {
+			return fmt.Errorf("value %d overflows %s", u, dest.Type())
+		}
+		dest.SetUint(u)
+
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		switch s := src.(type) {
+		case float64:
+			f = s
+		case int64:
+			f = float64(s)
+		case string, []byte:
+			var err error
+			if f, err = strconv.ParseFloat(text, dest.Type().Bits()); err != nil {
+				return fmt.Errorf("converting %q to %s: %w", text, dest.Type(), err)
+			}
+		default:
+			return unsupported
+		}
+		dest.SetFloat(f)
+
+	case reflect.Bool:
+		var b bool
+		switch s := src.(type) {
+		case bool:
+			b = s
+		case int64:
+			b = s != 0
+		case string, []byte:
+			var err error
+			if b, err = strconv.ParseBool(text); err != nil {
+				return fmt.Errorf("converting %q to %s: %w", text, dest.Type(), err)
+			}
+		default:
+			return unsupported
+		}
+		dest.SetBool(b)
+
+	case reflect.Slice:
+		if dest.Type().Elem().Kind() != reflect.Uint8 {
+			return unsupported
+		}
+
+		switch src.(type) {
+		case string, []byte:
+			// drivers may reuse the buffer of []byte values after Scan returns
+			dest.SetBytes([]byte(text))
+		default:
+			return unsupported
+		}
+
+	default:
+		return unsupported
+	}
+
+	return nil
+}
+
+func parseTime(s string) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("converting %q to time.Time is unsupported", s)
+}
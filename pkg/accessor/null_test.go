@@ -0,0 +1,148 @@
+package accessor
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testMood string
+
+func Test_NullScan(t *testing.T) {
+	req := require.New(t)
+
+	var i Null[int]
+	req.NoError(i.Scan(int64(42)))
+	req.Equal(NewNull(42), i)
+
+	// text protocol values
+	req.NoError(i.Scan([]byte("43")))
+	req.Equal(43, i.V)
+
+	req.NoError(i.Scan(nil))
+	req.False(i.Valid)
+	req.Equal(0, i.V)
+
+	var i8 Null[int8]
+	req.Error(i8.Scan(int64(300)))
+	req.False(i8.Valid)
+
+	var u Null[uint16]
+	req.NoError(u.Scan("7"))
+	req.Equal(uint16(7), u.V)
+	req.Error(u.Scan(int64(-1)))
+
+	var f Null[float32]
+	req.NoError(f.Scan(int64(2)))
+	req.Equal(float32(2), f.V)
+
+	var b Null[bool]
+	req.NoError(b.Scan(int64(1)))
+	req.True(b.V)
+	req.NoError(b.Scan("false"))
+	req.True(b.Valid)
+	req.False(b.V)
+
+	var m Null[testMood]
+	req.NoError(m.Scan([]byte("happy")))
+	req.Equal(NewNull(testMood("happy")), m)
+
+	var data Null[[]byte]
+	buf := []byte("data")
+	req.NoError(data.Scan(buf))
+	buf[0] = 'D'
+	req.Equal([]byte("data"), data.V)
+
+	now := time.Date(2023, 10, 1, 8, 30, 0, 0, time.UTC)
+	var ts Null[time.Time]
+	req.NoError(ts.Scan(now))
+	req.Equal(now, ts.V)
+	req.NoError(ts.Scan("2023-10-01 08:30:00+00:00"))
+	req.True(now.Equal(ts.V))
+
+	var s Null[string]
+	req.NoError(s.Scan(int64(5)))
+	req.Equal("5", s.V)
+	req.Error(s.Scan(struct{}{}))
+}
+
+func Test_NullValue(t *testing.T) {
+	req := require.New(t)
+
+	v, err := Null[int]{}.Value()
+	req.NoError(err)
+	req.Nil(v)
+
+	for _, c := range []struct {
+		valuer   driver.Valuer
+		expected any
+	}{
+		{NewNull(42), int64(42)},
+		{NewNull(uint8(7)), int64(7)},
+		{NewNull(float32(1.5)), float64(1.5)},
+		{NewNull(true), true},
+		{NewNull(testMood("sad")), "sad"},
+		{NewNull([]byte("data")), []byte("data")},
+	} {
+		v, err := c.valuer.Value()
+		req.NoError(err)
+		req.Equal(c.expected, v)
+	}
+
+	_, err = NewNull(uint64(1 << 63)).Value()
+	req.Error(err)
+
+	_, err = NewNull(struct{}{}).Value()
+	req.Error(err)
+}
+
+func Test_NullJSON(t *testing.T) {
+	req := require.New(t)
+
+	type doc struct {
+		Age  Null[int]      `json:"age"`
+		Mood Null[testMood] `json:"mood"`
+	}
+
+	data, err := json.Marshal(doc{Mood: NewNull(testMood("calm"))})
+	req.NoError(err)
+	req.JSONEq(`{"age":null,"mood":"calm"}`, string(data))
+
+	var d doc
+	req.NoError(json.Unmarshal([]byte(`{"age":3,"mood":null}`), &d))
+	req.Equal(NewNull(3), d.Age)
+	req.False(d.Mood.Valid)
+}
+
+func (s *AccessorTestSuite) TestZeroPointer() {
+	req := require.New(s.T())
+
+	defer func() {
+		ZeroPointerAsNull = false
+		s.setupTestDatabase()
+	}()
+
+	a := New(s.Db)
+	ctx := context.Background()
+
+	zero := 0
+	p := PersonWithAge{FirstName: "zero", Age: &zero}
+	req.NoError(a.Create(ctx, &p, "person", "FirstName"))
+
+	p = PersonWithAge{FirstName: "nil"}
+	req.NoError(a.Create(ctx, &p, "person", "FirstName"))
+	req.Nil(p.Age)
+
+	ZeroPointerAsNull = true
+	p = PersonWithAge{FirstName: "legacy", Age: &zero}
+	req.NoError(a.Create(ctx, &p, "person", "FirstName"))
+
+	var ages []Null[int]
+	req.NoError(a.Select(ctx, &ages, "SELECT age FROM person WHERE first_name IN (?, ?, ?) ORDER BY first_name DESC",
+		"zero", "nil", "legacy"))
+	req.Equal([]Null[int]{NewNull(0), {}, {}}, ages)
+}
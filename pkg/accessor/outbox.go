@@ -0,0 +1,96 @@
+package accessor
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+// OutboxTable is the table Enqueue writes messages into, it is expected to have
+// the following columns
+/*
+	CREATE TABLE outbox (
+		id           serial PRIMARY KEY,
+		topic        text NOT NULL,
+		payload      bytea,
+		status       text NOT NULL,
+		attempts     integer NOT NULL DEFAULT 0,
+		last_error   text,
+		created_at   timestamp NOT NULL,
+		available_at timestamp NOT NULL,
+		sent_at      timestamp
+	);
+*/
+var OutboxTable = "outbox"
+
+const (
+	OutboxPending = "pending"
+	OutboxSent    = "sent"
+	OutboxDead    = "dead"
+)
+
+// OutboxMessage maps a row of OutboxTable
+type OutboxMessage struct {
+	Id          int64          `db:"id"`
+	Topic       string         `db:"topic"`
+	Payload     []byte         `db:"payload"`
+	Status      string         `db:"status"`
+	Attempts    int            `db:"attempts"`
+	LastError   sql.NullString `db:"last_error"`
+	CreatedAt   time.Time      `db:"created_at"`
+	AvailableAt time.Time      `db:"available_at"`
+	SentAt      sql.NullTime   `db:"sent_at"`
+}
+
+// Enqueue writes a message into OutboxTable, in the transaction started by ExecTx that
+// ctx belongs to, so that the message is persisted if and only if the transaction commits.
+// Messages are then delivered by a relay worker, see package outbox.
+//
+// payload of []byte or string is stored as is, other values are stored in JSON.
+//
+// Usage example:
+/*
+	err := accessor.ExecTx(ctx, db, nil, func(ctx context.Context, a *accessor.Accessor) error {
+		if err := a.Create(ctx, order, "orders"); err != nil {
+			return err
+		}
+
+		return accessor.Enqueue(ctx, "order.created", order)
+	})
+*/
+func Enqueue(ctx context.Context, topic string, payload any) error {
+	a, ok := accessorFromContext(ctx)
+	if ok {
+		_, ok = a.Db.(*sqlx.Tx)
+	}
+	if !ok {
+		return errors.New("outbox message should be enqueued within ExecTx")
+	}
+
+	var data []byte
+	switch v := payload.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		var err error
+		if data, err = json.Marshal(payload); err != nil {
+			return err
+		}
+	}
+
+	t := now()
+	_, err := a.SqlizerExec(ctx, func(builder squirrel.StatementBuilderType) Sqlizer {
+		return builder.Insert(OutboxTable).
+			Columns("topic", "payload", "status", "attempts", "created_at", "available_at").
+			Values(topic, data, OutboxPending, 0, t, t)
+	})
+
+	return err
+}
@@ -0,0 +1,358 @@
+package accessor
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx/reflectx"
+)
+
+// TableNamer is implemented by entity types enhanced by gdbc
+type TableNamer interface {
+	TableName() string
+}
+
+// hierarchy keeps mapping schemas of entity types registered with RegisterHierarchy,
+// schema of the root type comes first
+type hierarchy struct {
+	schemas []*EntityMappingSchema
+}
+
+var hierarchyRegistry sync.Map
+
+// RegisterHierarchy registers entity types of a type hierarchy for ReadPolymorphic and
+// SelectPolymorphic. Subtypes can be mapped by either table inheritance or single-table
+// inheritance (or a mix of both), all of them should have the table of root type at the
+// root of their inheritance chains.
+//
+// Usage example:
+/*
+	err := accessor.RegisterHierarchy(&Person{}, &Employee{}, &Manager{}, &crosspkg.Executive{})
+*/
+func RegisterHierarchy(root TableNamer, subtypes ...TableNamer) error {
+	h := &hierarchy{}
+
+	for _, entity := range append([]TableNamer{root}, subtypes...) {
+		typ := reflectx.Deref(reflect.TypeOf(entity))
+		if typ.Kind() != reflect.Struct {
+			return fmt.Errorf("type %s should be struct", typ.Name())
+		}
+
+		s, err := EntitySchema(reflect.New(typ).Interface(), typ, entity.TableName())
+		if err != nil {
+			return err
+		}
+
+		if len(h.schemas) > 0 && s.Tables()[0] != h.schemas[0].TableName {
+			return fmt.Errorf("type %s is not mapped in hierarchy of table %s", typ.Name(), h.schemas[0].TableName)
+		}
+
+		h.schemas = append(h.schemas, s)
+	}
+
+	hierarchyRegistry.Store(reflectx.Deref(reflect.TypeOf(root)), h)
+	return nil
+}
+
+// UnregisterHierarchy reverts RegisterHierarchy
+func UnregisterHierarchy(root TableNamer) {
+	hierarchyRegistry.Delete(reflectx.Deref(reflect.TypeOf(root)))
+}
+
+func lookupHierarchy(root any) (*hierarchy, error) {
+	if root == nil {
+		return nil, errors.New("missing root entity")
+	}
+
+	typ := reflectx.Deref(reflect.TypeOf(root))
+	if h, ok := hierarchyRegistry.Load(typ); ok {
+		return h.(*hierarchy), nil
+	}
+
+	return nil, fmt.Errorf("type hierarchy of %s is not registered", typ.Name())
+}
+
+// ReadPolymorphic reads entity of root type hierarchy by its primary key, returned entity
+// is a pointer to the most specific registered type the row belongs to, e.g. *Manager for
+// a manager read through Person hierarchy. It returns sql.ErrNoRows if entity does not exist.
+//
+// Usage example:
+/*
+	entity, err := a.ReadPolymorphic(context.Background(), 1000, &Person{})
+	if m, ok := entity.(*Manager); ok {
+		...
+	}
+*/
+func (a *Accessor) ReadPolymorphic(ctx context.Context, id any, root any, idFields ...string) (any, error) {
+	h, err := lookupHierarchy(root)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(idFields) == 0 {
+		idFields = []string{"Id"}
+	}
+
+	idColumns, err := entityIdColumns(h.schemas[0].Entity, idFields...)
+	if err != nil {
+		return nil, err
+	}
+
+	where, err := keysPredicate(h.schemas[0].TableName, idColumns, []any{id})
+	if err != nil {
+		return nil, err
+	}
+
+	entities, err := a.SelectPolymorphic(ctx, root, func(builder squirrel.SelectBuilder) Sqlizer {
+		return builder.Where(where)
+	}, idFields...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entities) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return entities[0], nil
+}
+
+// SelectPolymorphic selects entities of root type hierarchy, returned entities are pointers
+// to the most specific registered types rows belong to.
+//
+// Tables of all registered subtypes are LEFT JOINed to the root table, the type of each row
+// is resolved from the joined row and the row is scanned into an entity of that type, all
+// in a single query. sqlizer can refine this query with WHERE, ORDER BY and LIMIT clauses on
+// columns qualified with their tables.
+//
+// Usage example:
+/*
+	entities, err := a.SelectPolymorphic(
+		context.Background(),
+		&Person{},
+		func(builder squirrel.SelectBuilder) accessor.Sqlizer {
+			return builder.Where(PersonCols.LastName.Eq("gdbc")).OrderBy(PersonCols.Id.Asc())
+		},
+	)
+*/
+func (a *Accessor) SelectPolymorphic(
+	ctx context.Context,
+	root any,
+	sqlizer func(builder squirrel.SelectBuilder) Sqlizer,
+	idFields ...string,
+) ([]any, error) {
+	h, err := lookupHierarchy(root)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(idFields) == 0 {
+		idFields = []string{"Id"}
+	}
+
+	idColumns, err := entityIdColumns(h.schemas[0].Entity, idFields...)
+	if err != nil {
+		return nil, err
+	}
+
+	sel := h.selection(idColumns)
+	builder := squirrel.StatementBuilder.Select(sel.columns...).From(h.schemas[0].TableName)
+	for _, tbl := range sel.tables {
+		builder = builder.LeftJoin(fmt.Sprintf("%s ON %s", tbl, sel.joins[tbl]))
+	}
+
+	var q string
+	var args []any
+	if sqlizer != nil {
+		q, args, err = sqlizer(builder).ToSql()
+	} else {
+		q, args, err = builder.ToSql()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// traversals of entity columns in the selection, for each type in the hierarchy
+	typeTraversals := make([][][]int, len(h.schemas))
+	for i, s := range h.schemas {
+		typeTraversals[i] = a.mapper().TraversalsByName(s.EntityType, sel.typeColumns(s))
+	}
+
+	rows, err := a.queryx(ctx, a.Db.Rebind(q), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []any{}
+	for rows.Next() {
+		vals, err := rows.SliceScan()
+		if err != nil {
+			return nil, err
+		}
+
+		present := map[string]bool{h.schemas[0].TableName: true}
+		for i, tbl := range sel.tables {
+			present[tbl] = vals[i] != nil
+		}
+
+		values := map[string]any{}
+		for i, col := range sel.discriminators {
+			v := vals[sel.discriminatorOffset+i]
+			if b, ok := v.([]byte); ok {
+				v = string(b)
+			}
+			values[col] = v
+		}
+
+		// the row is scanned again into the resolved type
+		i := h.resolve(present, values)
+		entity := reflect.New(h.schemas[i].EntityType)
+		if err := rows.Scan(scanTargets(entity.Elem(), h.schemas[i].TableName, sel.entityColumns, typeTraversals[i])...); err != nil {
+			return nil, err
+		}
+		result = append(result, entity.Interface())
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// polymorphicSelection lays out columns selected by SelectPolymorphic: ID column of each
+// joined table and discriminator columns that resolve the type of a row, followed by
+// columns of all tables in the hierarchy
+type polymorphicSelection struct {
+	columns []string
+
+	tables         []string
+	joins          map[string]string
+	discriminators []string
+
+	discriminatorOffset int
+
+	// names and tables of entity columns, in the whole selection (empty for columns
+	// used to resolve types)
+	entityColumns []string
+	columnTables  []string
+}
+
+func (h *hierarchy) selection(idColumns []string) *polymorphicSelection {
+	rootTable := h.schemas[0].TableName
+	tables, tableKeys, discriminators := h.joinedColumns(idColumns)
+
+	sel := &polymorphicSelection{
+		tables:         tables,
+		joins:          map[string]string{},
+		discriminators: discriminators,
+	}
+
+	for _, tbl := range tables {
+		sel.joins[tbl] = getKeyJoinOnString(rootTable, idColumns, tbl, tableKeys[tbl])
+		sel.columns = append(sel.columns, fmt.Sprintf("%s.%s", tbl, tableKeys[tbl][0]))
+	}
+	sel.discriminatorOffset = len(sel.columns)
+	sel.columns = append(sel.columns, discriminators...)
+
+	sel.entityColumns = make([]string, len(sel.columns))
+	sel.columnTables = make([]string, len(sel.columns))
+
+	// columns of each table, in the order tables are joined
+	tableColumns := map[string][]string{}
+	for _, s := range h.schemas {
+		for _, m := range s.Schemas() {
+			for _, col := range sortedColumns(m) {
+				if !stringInSlice(col, tableColumns[m.TableName]) {
+					tableColumns[m.TableName] = append(tableColumns[m.TableName], col)
+				}
+			}
+		}
+	}
+
+	for _, tbl := range append([]string{rootTable}, tables...) {
+		for _, col := range tableColumns[tbl] {
+			sel.columns = append(sel.columns, fmt.Sprintf("%s.%s", tbl, col))
+			sel.entityColumns = append(sel.entityColumns, col)
+			sel.columnTables = append(sel.columnTables, tbl)
+		}
+	}
+
+	return sel
+}
+
+// typeColumns returns names of entity columns in the selection that are mapped by entity
+// type in s, empty for other columns
+func (sel *polymorphicSelection) typeColumns(s *EntityMappingSchema) []string {
+	tables := s.Tables()
+
+	cols := make([]string, len(sel.entityColumns))
+	for i, col := range sel.entityColumns {
+		if col != "" && stringInSlice(sel.columnTables[i], tables) {
+			cols[i] = col
+		}
+	}
+	return cols
+}
+
+// joinedColumns returns tables other than root table in the hierarchy with their key
+// columns, and qualified discriminator columns
+func (h *hierarchy) joinedColumns(idColumns []string) (
+	tables []string,
+	tableKeys map[string][]string,
+	discriminators []string,
+) {
+	tableKeys = map[string][]string{}
+
+	for _, s := range h.schemas {
+		for _, m := range s.Schemas() {
+			if m.TableName != h.schemas[0].TableName && !stringInSlice(m.TableName, tables) {
+				tables = append(tables, m.TableName)
+				tableKeys[m.TableName] = m.keyColumns(idColumns)
+			}
+
+			if m.Discriminator != "" && !stringInSlice(m.discriminatorColumn(), discriminators) {
+				discriminators = append(discriminators, m.discriminatorColumn())
+			}
+		}
+	}
+
+	return
+}
+
+// resolve returns index of the most specific type that a row belongs to, present tells
+// which tables the row has been found in, values carries discriminator column values.
+// A type matches the row if the row is found in all tables in its inheritance chain
+// with matching discriminator values, the type with the longest chain wins.
+func (h *hierarchy) resolve(present map[string]bool, values map[string]any) int {
+	resolved, depth := 0, 0
+
+	for i, s := range h.schemas {
+		d := 0
+		for _, m := range s.Schemas() {
+			if !present[m.TableName] {
+				d = -1
+				break
+			}
+
+			d++
+			if m.Discriminator != "" {
+				if fmt.Sprint(values[m.discriminatorColumn()]) != m.DiscriminatorValue {
+					d = -1
+					break
+				}
+				d++
+			}
+		}
+
+		if d > depth {
+			resolved, depth = i, d
+		}
+	}
+
+	return resolved
+}
@@ -0,0 +1,98 @@
+package accessor
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+)
+
+func (e *BaseEntity) TableName() string {
+	return "base"
+}
+
+func (e *ChildEntity) TableName() string {
+	return "child"
+}
+
+func (e *GrandChildEntity) TableName() string {
+	return "grand_child"
+}
+
+func (s *AccessorTestSuite) TestPolymorphic() {
+	req := require.New(s.T())
+
+	s.setupCompositeTables()
+	defer s.teardownCompositeTables()
+
+	a := New(s.Db)
+	ctx := context.Background()
+
+	_, err := a.ReadPolymorphic(ctx, 1, &BaseEntity{})
+	req.Error(err)
+
+	req.Error(RegisterHierarchy(&ChildEntity{}, &BaseEntity{}))
+
+	req.NoError(RegisterHierarchy(&BaseEntity{}, &ChildEntity{}, &GrandChildEntity{}))
+	defer UnregisterHierarchy(&BaseEntity{})
+
+	child := ChildEntity{BaseEntity: BaseEntity{Name: "qux"}, ChildAttr: "blue"}
+	req.NoError(a.Create(ctx, &child, "child"))
+
+	base := BaseEntity{Name: "quux"}
+	req.NoError(a.Create(ctx, &base, "base"))
+
+	var grandChild GrandChildEntity
+	req.NoError(a.EntityGet(ctx, &grandChild, "grand_child", func(builder squirrel.SelectBuilder) Sqlizer {
+		return builder.Where(squirrel.Eq{"base.name": "foo"})
+	}))
+
+	entity, err := a.ReadPolymorphic(ctx, grandChild.Id, BaseEntity{})
+	req.NoError(err)
+	req.Equal(&grandChild, entity)
+
+	entity, err = a.ReadPolymorphic(ctx, child.Id, &BaseEntity{})
+	req.NoError(err)
+	req.Equal(&child, entity)
+
+	entity, err = a.ReadPolymorphic(ctx, base.Id, &BaseEntity{})
+	req.NoError(err)
+	req.Equal(&base, entity)
+
+	_, err = a.ReadPolymorphic(ctx, -1, &BaseEntity{})
+	req.ErrorIs(err, sql.ErrNoRows)
+
+	entities, err := a.SelectPolymorphic(ctx, &BaseEntity{}, func(builder squirrel.SelectBuilder) Sqlizer {
+		return builder.Where(squirrel.NotEq{"base.name": "bar"}).OrderBy("base.id DESC")
+	})
+	req.NoError(err)
+	req.Equal(4, len(entities))
+	req.Equal(&base, entities[0])
+	req.Equal(&child, entities[1])
+	req.IsType(&GrandChildEntity{}, entities[2])
+	req.Equal(&grandChild, entities[3])
+}
+
+func (s *AccessorTestSuite) TestPolymorphicSelection() {
+	req := require.New(s.T())
+
+	req.NoError(RegisterHierarchy(&BaseEntity{}, &ChildEntity{}, &GrandChildEntity{}))
+	defer UnregisterHierarchy(&BaseEntity{})
+
+	h, err := lookupHierarchy(&BaseEntity{})
+	req.NoError(err)
+
+	// subtype columns are selected along with columns that resolve the type of a row
+	sel := h.selection([]string{"id"})
+	req.Equal([]string{
+		"child.id", "grand_child.id",
+		"base.id", "base.name",
+		"child.child_attr",
+		"grand_child.grand_child_attr",
+	}, sel.columns)
+
+	req.Equal([]string{"", "", "id", "name", "", ""}, sel.typeColumns(h.schemas[0]))
+	req.Equal([]string{"", "", "id", "name", "child_attr", ""}, sel.typeColumns(h.schemas[1]))
+	req.Equal([]string{"", "", "id", "name", "child_attr", "grand_child_attr"}, sel.typeColumns(h.schemas[2]))
+}
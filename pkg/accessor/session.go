@@ -0,0 +1,348 @@
+package accessor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Session is a unit of work on top of Accessor. Entities read through a session are kept
+// in an identity map keyed by table and primary key, so that repeated reads return the same
+// pointer, and changes made to them are detected with snapshots (see Track). Entities
+// registered as new, dirty or deleted are written by Flush in a single transaction.
+//
+// Session is not thread-safe, it is meant to span a single request or job.
+//
+// Usage example:
+/*
+	s := accessor.NewSession(db)
+
+	entity, err := s.Read(ctx, &Manager{Employee: Employee{Person: Person{Id: 1000}}}, "manager")
+	m := entity.(*Manager)
+	m.Title = toPtr("CTO")
+
+	err = s.RegisterNew(&Employee{...}, "employee")
+	err = s.RegisterDeleted(&Person{Id: 2000}, "person")
+
+	// inserts, updates m and deletes in one transaction
+	err = s.Flush(ctx)
+*/
+type Session struct {
+	Db *sqlx.DB
+
+	accessor *Accessor
+
+	identities map[identityKey]*sessionEntry
+	managed    []*sessionEntry
+
+	news    []*sessionEntry
+	dirties []*sessionEntry
+	deletes []*sessionEntry
+}
+
+type identityKey struct {
+	tbl string
+	key string
+}
+
+type sessionEntry struct {
+	entity   any
+	tbl      string
+	idFields []string
+
+	// number of tables in the inheritance chain of entity type
+	depth int
+}
+
+// NewSession returns a session that reads entities with db and flushes changes in
+// transactions started on db
+func NewSession(db *sqlx.DB) *Session {
+	return &Session{
+		Db:         db,
+		accessor:   New(db),
+		identities: map[identityKey]*sessionEntry{},
+	}
+}
+
+func (s *Session) entry(entity any, tbl string, idFields []string) (*sessionEntry, error) {
+	if entity == nil || reflect.TypeOf(entity).Kind() != reflect.Ptr {
+		return nil, errors.New("must pass a pointer, not a value, to session")
+	}
+
+	if len(idFields) == 0 {
+		idFields = []string{"Id"}
+	}
+
+	schema, err := EntitySchema(entity, reflect.TypeOf(entity), tbl)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sessionEntry{
+		entity:   entity,
+		tbl:      tbl,
+		idFields: idFields,
+		depth:    len(schema.Tables()),
+	}, nil
+}
+
+// identity returns key of entity in the identity map
+func (s *Session) identity(e *sessionEntry) (identityKey, error) {
+	key, err := entityKey(s.accessor, e.entity, e.idFields)
+	if err != nil {
+		return identityKey{}, err
+	}
+
+	return identityKey{tbl: e.tbl, key: keyString(key)}, nil
+}
+
+func (s *Session) manage(e *sessionEntry) error {
+	key, err := s.identity(e)
+	if err != nil {
+		return err
+	}
+
+	s.identities[key] = e
+	s.managed = append(s.managed, e)
+	return s.accessor.Track(e.entity)
+}
+
+// Read reads entity by its primary key, entity returned is the one kept in the identity
+// map if the same table and primary key have been read before (no query is made then),
+// otherwise it is entity itself, which is managed by the session from now on.
+func (s *Session) Read(ctx context.Context, entity any, tbl string, idFields ...string) (any, error) {
+	e, err := s.entry(entity, tbl, idFields)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.identity(e)
+	if err != nil {
+		return nil, err
+	}
+
+	if managed, ok := s.identities[key]; ok {
+		return managed.entity, nil
+	}
+
+	if err := s.accessor.Read(ctx, entity, tbl, e.idFields...); err != nil {
+		return nil, err
+	}
+
+	if err := s.manage(e); err != nil {
+		return nil, err
+	}
+	return entity, nil
+}
+
+// RegisterNew registers entity to be created by Flush
+func (s *Session) RegisterNew(entity any, tbl string, idFields ...string) error {
+	e, err := s.entry(entity, tbl, idFields)
+	if err != nil {
+		return err
+	}
+
+	s.news = append(s.news, e)
+	return nil
+}
+
+// RegisterDirty registers entity to be updated by Flush, entities read through the session
+// do not need to be registered, their changes are detected with snapshots
+func (s *Session) RegisterDirty(entity any, tbl string, idFields ...string) error {
+	if s.isManaged(entity) || indexOf(s.news, entity) >= 0 || indexOf(s.dirties, entity) >= 0 {
+		return nil
+	}
+
+	e, err := s.entry(entity, tbl, idFields)
+	if err != nil {
+		return err
+	}
+
+	s.dirties = append(s.dirties, e)
+	return nil
+}
+
+// RegisterDeleted registers entity to be deleted by Flush, entities registered as new are
+// simply dropped
+func (s *Session) RegisterDeleted(entity any, tbl string, idFields ...string) error {
+	if i := indexOf(s.news, entity); i >= 0 {
+		s.news = append(s.news[:i], s.news[i+1:]...)
+		return nil
+	}
+
+	if indexOf(s.deletes, entity) >= 0 {
+		return nil
+	}
+
+	e, err := s.entry(entity, tbl, idFields)
+	if err != nil {
+		return err
+	}
+
+	s.deletes = append(s.deletes, e)
+	return nil
+}
+
+func (s *Session) isManaged(entity any) bool {
+	return indexOf(s.managed, entity) >= 0
+}
+
+func indexOf(entries []*sessionEntry, entity any) int {
+	for i, e := range entries {
+		if e.entity == entity {
+			return i
+		}
+	}
+	return -1
+}
+
+// Flush writes registered changes in a single transaction: new entities are created with
+// base types before derived types, dirty and changed managed entities are updated, then
+// deleted entities are deleted with derived types before base types. Deletes of entities of
+// the same type are batched with DeleteMany, unless they take a delete strategy other
+// than DeleteEachTable.
+//
+// Once the transaction commits, created entities are managed by the session and deleted
+// entities are dropped from it. If Flush fails, the transaction is rolled back, however,
+// entities may have been changed by writes (e.g. backfilled IDs), the session should be
+// discarded then.
+func (s *Session) Flush(ctx context.Context) error {
+	// snapshots are retaken by updates, keep them apart until commit
+	snapshots := s.accessor.snapshots.clone()
+
+	err := ExecTx(ctx, s.Db, nil, func(ctx context.Context, a *Accessor) error {
+		a.snapshots = snapshots
+		return s.flush(ctx, a)
+	})
+	if err != nil {
+		return err
+	}
+	s.accessor.snapshots = snapshots
+
+	for _, e := range s.deletes {
+		if key, err := s.identity(e); err == nil {
+			if managed, ok := s.identities[key]; ok {
+				delete(s.identities, key)
+				s.accessor.Untrack(managed.entity)
+				if i := indexOf(s.managed, managed.entity); i >= 0 {
+					s.managed = append(s.managed[:i], s.managed[i+1:]...)
+				}
+			}
+		}
+	}
+
+	for _, e := range s.news {
+		if err := s.manage(e); err != nil {
+			return err
+		}
+	}
+
+	s.news, s.dirties, s.deletes = nil, nil, nil
+	return nil
+}
+
+func (s *Session) flush(ctx context.Context, a *Accessor) error {
+	news := append([]*sessionEntry{}, s.news...)
+	sort.SliceStable(news, func(i, j int) bool {
+		return news[i].depth < news[j].depth
+	})
+
+	for _, e := range news {
+		if err := a.Create(ctx, e.entity, e.tbl, e.idFields...); err != nil {
+			return err
+		}
+	}
+
+	// entities to be deleted are not updated
+	deleted := map[identityKey]bool{}
+	for _, e := range s.deletes {
+		key, err := s.identity(e)
+		if err != nil {
+			return err
+		}
+		deleted[key] = true
+	}
+
+	updates := []*sessionEntry{}
+	for _, e := range append(append([]*sessionEntry{}, s.dirties...), s.managed...) {
+		key, err := s.identity(e)
+		if err != nil {
+			return err
+		}
+
+		if !deleted[key] {
+			updates = append(updates, e)
+		}
+	}
+	sort.SliceStable(updates, func(i, j int) bool {
+		return updates[i].depth < updates[j].depth
+	})
+
+	for _, e := range updates {
+		if _, err := a.Update(ctx, e.entity, e.tbl, e.idFields...); err != nil {
+			return err
+		}
+	}
+
+	return s.flushDeletes(ctx, a)
+}
+
+func (s *Session) flushDeletes(ctx context.Context, a *Accessor) error {
+	deletes := append([]*sessionEntry{}, s.deletes...)
+	sort.SliceStable(deletes, func(i, j int) bool {
+		return deletes[i].depth > deletes[j].depth
+	})
+
+	// entities of the same type, table and ID fields are deleted in batches, at the
+	// position of the first one
+	var steps []string
+	stepEntries := map[string][]*sessionEntry{}
+
+	for i, e := range deletes {
+		step := fmt.Sprintf("%s:%s:%s", reflect.TypeOf(e.entity), e.tbl, strings.Join(e.idFields, ","))
+		if e.depth > 1 && deleteStrategyOf(ctx, e.entity) != DeleteEachTable {
+			step = fmt.Sprintf("#%d", i)
+		}
+
+		if _, ok := stepEntries[step]; !ok {
+			steps = append(steps, step)
+		}
+		stepEntries[step] = append(stepEntries[step], e)
+	}
+
+	for _, step := range steps {
+		entries := stepEntries[step]
+
+		if strings.HasPrefix(step, "#") {
+			if _, err := a.Delete(ctx, entries[0].entity, entries[0].tbl, entries[0].idFields...); err != nil {
+				return err
+			}
+			continue
+		}
+
+		ids := make([]any, len(entries))
+		for i, e := range entries {
+			key, err := entityKey(a, e.entity, e.idFields)
+			if err != nil {
+				return err
+			}
+
+			if len(key) == 1 {
+				ids[i] = key[0]
+			} else {
+				ids[i] = key
+			}
+		}
+
+		if _, err := a.DeleteMany(ctx, entries[0].entity, entries[0].tbl, ids, entries[0].idFields...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package accessor
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/stretchr/testify/require"
+)
+
+func (s *AccessorTestSuite) TestSession() {
+	req := require.New(s.T())
+
+	s.setupCompositeTables()
+	defer s.teardownCompositeTables()
+
+	a := New(s.Db)
+	ctx := context.Background()
+	sess := NewSession(s.Db)
+
+	count := func(tbl string) int {
+		var cnt int
+		req.NoError(s.Db.Get(&cnt, "SELECT count(*) FROM "+tbl))
+		return cnt
+	}
+
+	read := func(id int) any {
+		e, err := sess.Read(ctx, &GrandChildEntity{ChildEntity{BaseEntity: BaseEntity{Id: id}}, ""}, "grand_child")
+		req.NoError(err)
+		return e
+	}
+
+	// repeated reads return the same pointer
+	e := read(1).(*GrandChildEntity)
+	req.Equal("apple", e.GrandChildAttr)
+	req.Same(e, read(1))
+
+	_, err := sess.Read(ctx, GrandChildEntity{}, "grand_child")
+	req.Error(err)
+
+	e.GrandChildAttr = "plum"
+
+	created := &GrandChildEntity{ChildEntity{BaseEntity{Name: "qux"}, "blue"}, "fig"}
+	req.NoError(sess.RegisterNew(created, "grand_child"))
+
+	base := &BaseEntity{Name: "plain"}
+	req.NoError(sess.RegisterNew(base, "base"))
+
+	dropped := &BaseEntity{Name: "dropped"}
+	req.NoError(sess.RegisterNew(dropped, "base"))
+	req.NoError(sess.RegisterDeleted(dropped, "base"))
+
+	for _, id := range []int{2, 3} {
+		req.NoError(sess.RegisterDeleted(&GrandChildEntity{ChildEntity{BaseEntity: BaseEntity{Id: id}}, ""}, "grand_child"))
+	}
+
+	// nothing is written before flush
+	req.Equal(3, count("base"))
+
+	req.NoError(sess.Flush(ctx))
+
+	// base types are created before derived types
+	req.Less(base.Id, created.Id)
+	req.Equal(3, count("base"))
+	req.Equal(2, count("child"))
+	req.Equal(2, count("grand_child"))
+
+	r := GrandChildEntity{ChildEntity{BaseEntity: BaseEntity{Id: 1}}, ""}
+	req.NoError(a.Read(ctx, &r, "grand_child"))
+	req.Equal("plum", r.GrandChildAttr)
+
+	// created entities are managed
+	req.Same(created, read(created.Id))
+
+	// deleted entities are dropped
+	_, err = sess.Read(ctx, &GrandChildEntity{ChildEntity{BaseEntity: BaseEntity{Id: 2}}, ""}, "grand_child")
+	req.ErrorIs(err, sql.ErrNoRows)
+
+	// entities that are not read through the session are updated in full
+	d := &BaseEntity{Id: base.Id, Name: "dirty"}
+	req.NoError(sess.RegisterDirty(d, "base"))
+	req.NoError(sess.Flush(ctx))
+
+	var name string
+	req.NoError(s.Db.Get(&name, "SELECT name FROM base WHERE id = ?", base.Id))
+	req.Equal("dirty", name)
+}
@@ -0,0 +1,176 @@
+package accessor
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+)
+
+// snapshotStore keeps snapshots taken by Track, it is shared by pointer with accessors
+// of transactions started on behalf of the accessor (e.g. by audited writes)
+type snapshotStore struct {
+	mu sync.Mutex
+
+	// entity pointer -> snapshot
+	snapshots map[any]any
+}
+
+func newSnapshotStore() *snapshotStore {
+	return &snapshotStore{snapshots: map[any]any{}}
+}
+
+func (ss *snapshotStore) load(entity any) (any, bool) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	snapshot, ok := ss.snapshots[entity]
+	return snapshot, ok
+}
+
+func (ss *snapshotStore) store(entity any, snapshot any) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	ss.snapshots[entity] = snapshot
+}
+
+// restore replaces the snapshot of entity only if entity is still tracked
+func (ss *snapshotStore) restore(entity any, snapshot any) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if _, ok := ss.snapshots[entity]; ok {
+		ss.snapshots[entity] = snapshot
+	}
+}
+
+func (ss *snapshotStore) delete(entity any) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	delete(ss.snapshots, entity)
+}
+
+func (ss *snapshotStore) clone() *snapshotStore {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	c := newSnapshotStore()
+	for entity, snapshot := range ss.snapshots {
+		c.snapshots[entity] = snapshot
+	}
+	return c
+}
+
+// snapshotTracker is the UpdateTracker of entities tracked by Track, it reports columns
+// whose values differ from the snapshot
+type snapshotTracker struct {
+	tbl     string
+	changed map[string]map[string]bool
+}
+
+func (t *snapshotTracker) ColumnsChanged(tbl ...string) []string {
+	cols := []string{}
+
+	if tbl == nil {
+		tbl = []string{t.tbl}
+	}
+
+	for col := range t.changed[tbl[0]] {
+		cols = append(cols, col)
+	}
+
+	return cols
+}
+
+func (t *snapshotTracker) MarkChanged(tbl string, col string) {
+	if t.changed[tbl] == nil {
+		t.changed[tbl] = map[string]bool{}
+	}
+	t.changed[tbl][col] = true
+}
+
+// Track takes a snapshot of mapped column values of entity, e.g. after Read. Update and
+// UpdateReturning of entity then write only columns whose values differ from the snapshot,
+// in all tables of composite entities, and take a new snapshot once they succeed. This
+// gives partial updates of any entity type with plain field assignments.
+//
+// Entity must be a pointer. Snapshots are kept by the accessor, and so are tracked
+// entities, until Untrack or Delete of the entity; long-lived accessors should Untrack
+// entities they are done with, or leave tracking to a Session. Accessors created by New
+// can track entities from concurrent goroutines. Entities that implement UpdateTracker
+// (e.g. generated XWithUpdateTracker types) keep tracking changes on their own.
+//
+// Usage example:
+/*
+   p := &Person{Id: 1000}
+   err := accessor.Read(context.Background(), p, "person")
+   err = accessor.Track(p)
+
+   p.Email = "foo@test.change"
+
+   // only email column is updated
+   result, err := accessor.Update(context.Background(), p, "person")
+*/
+func (a *Accessor) Track(entity any) error {
+	if entity == nil || reflect.TypeOf(entity).Kind() != reflect.Ptr {
+		return errors.New("must pass a pointer, not a value, to Track")
+	}
+
+	if a.snapshots == nil {
+		a.snapshots = newSnapshotStore()
+	}
+	a.snapshots.store(entity, copyEntity(entity))
+	return nil
+}
+
+// Untrack drops the snapshot of entity taken by Track
+func (a *Accessor) Untrack(entity any) {
+	if _, ok := a.snapshot(entity); ok {
+		a.snapshots.delete(entity)
+	}
+}
+
+func (a *Accessor) snapshot(entity any) (any, bool) {
+	if a.snapshots == nil || entity == nil || reflect.TypeOf(entity).Kind() != reflect.Ptr {
+		return nil, false
+	}
+
+	return a.snapshots.load(entity)
+}
+
+// retrack takes a new snapshot of entity if it is tracked
+func (a *Accessor) retrack(entity any) {
+	if _, ok := a.snapshot(entity); ok {
+		a.snapshots.restore(entity, copyEntity(entity))
+	}
+}
+
+// updateTracker returns tracker of changes made to entity, which is either the entity
+// itself or the difference from its snapshot, nil if changes to entity are not tracked
+func (a *Accessor) updateTracker(entity any, s *EntityMappingSchema) UpdateTracker {
+	if tracker, ok := entity.(UpdateTracker); ok {
+		return tracker
+	}
+
+	snapshot, ok := a.snapshot(entity)
+	if !ok {
+		return nil
+	}
+
+	colValueMap := a.fieldMap(reflect.ValueOf(entity))
+	snapshotColValueMap := a.fieldMap(reflect.ValueOf(snapshot))
+
+	tracker := &snapshotTracker{tbl: s.TableName, changed: map[string]map[string]bool{}}
+	for _, m := range s.Schemas() {
+		for _, col := range m.Columns {
+			v, ok := colValueMap[col]
+			prev, found := snapshotColValueMap[col]
+			if ok && found && !reflect.DeepEqual(v.Interface(), prev.Interface()) {
+				tracker.MarkChanged(m.TableName, col)
+			}
+		}
+	}
+
+	return tracker
+}
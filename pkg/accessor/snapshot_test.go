@@ -0,0 +1,128 @@
+package accessor
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *AccessorTestSuite) TestTrack() {
+	req := require.New(s.T())
+
+	s.setupCompositeTables()
+	defer s.teardownCompositeTables()
+
+	a := New(s.Db)
+	ctx := context.Background()
+
+	req.Error(a.Track(GrandChildEntity{}))
+
+	var e GrandChildEntity
+	req.NoError(a.EntityGet(ctx, &e, "grand_child", func(builder squirrel.SelectBuilder) Sqlizer {
+		return builder.Where(squirrel.Eq{"base.name": "foo"})
+	}))
+	req.NoError(a.Track(&e))
+
+	// nothing is written without changes
+	result, err := a.Update(ctx, &e, "grand_child")
+	req.NoError(err)
+	affected, err := result.RowsAffected()
+	req.NoError(err)
+	req.Equal(int64(0), affected)
+
+	// columns changed behind the back of the entity are not overwritten
+	_ = s.Db.MustExec("UPDATE base SET name = 'changed' WHERE id = ?", e.Id)
+	_ = s.Db.MustExec("UPDATE child SET child_attr = 'changed' WHERE id = ?", e.Id)
+
+	e.ChildAttr = "blue"
+	e.GrandChildAttr = "plum"
+	result, err = a.Update(ctx, &e, "grand_child")
+	req.NoError(err)
+	affected, err = result.RowsAffected()
+	req.NoError(err)
+	req.Equal(int64(1), affected)
+
+	r := GrandChildEntity{ChildEntity{BaseEntity: BaseEntity{Id: e.Id}}, ""}
+	req.NoError(a.Read(ctx, &r, "grand_child"))
+	req.Equal("changed", r.Name)
+	req.Equal("blue", r.ChildAttr)
+	req.Equal("plum", r.GrandChildAttr)
+
+	// snapshot is taken again after update
+	result, err = a.Update(ctx, &e, "grand_child")
+	req.NoError(err)
+	affected, err = result.RowsAffected()
+	req.NoError(err)
+	req.Equal(int64(0), affected)
+
+	// untracked entities are updated in full
+	a.Untrack(&e)
+	_, err = a.Update(ctx, &e, "grand_child")
+	req.NoError(err)
+
+	req.NoError(a.Read(ctx, &r, "grand_child"))
+	req.Equal(e.Name, r.Name)
+}
+
+func (s *AccessorTestSuite) TestTrackConcurrently() {
+	req := require.New(s.T())
+
+	s.setupCompositeTables()
+	defer s.teardownCompositeTables()
+
+	a := New(s.Db)
+	ctx := context.Background()
+
+	schema, err := EntitySchema(&GrandChildEntity{}, reflect.TypeOf(&GrandChildEntity{}), "grand_child")
+	req.NoError(err)
+
+	// snapshots of a shared accessor are taken and dropped from concurrent goroutines
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			e := &GrandChildEntity{ChildEntity{BaseEntity: BaseEntity{Id: i}}, ""}
+			for j := 0; j < 100; j++ {
+				_ = a.Track(e)
+				e.GrandChildAttr = fmt.Sprint(j)
+				_ = a.updateTracker(e, schema)
+				a.retrack(e)
+				a.Untrack(e)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// snapshots of deleted entities are dropped
+	e := GrandChildEntity{ChildEntity{BaseEntity: BaseEntity{Id: 1}}, ""}
+	req.NoError(a.Read(ctx, &e, "grand_child"))
+	req.NoError(a.Track(&e))
+	_, err = a.Delete(ctx, &e, "grand_child")
+	req.NoError(err)
+
+	_, ok := a.snapshot(&e)
+	req.False(ok)
+}
+
+func (s *AccessorTestSuite) TestTrackNestedJsonChanges() {
+	req := require.New(s.T())
+
+	a := New(s.Db)
+
+	schema, err := EntitySchema(&JsonChildEntity{}, reflect.TypeOf(&JsonChildEntity{}), "json_child")
+	req.NoError(err)
+
+	e := &JsonChildEntity{Meta: map[string]any{"dims": map[string]any{"w": float64(1)}}}
+	req.NoError(a.Track(e))
+	req.Empty(a.updateTracker(e, schema).ColumnsChanged("json_child"))
+
+	// edits of nested JSON values do not reach the snapshot
+	e.Meta["dims"].(map[string]any)["w"] = float64(2)
+	req.Equal([]string{"meta"}, a.updateTracker(e, schema).ColumnsChanged("json_child"))
+}
@@ -0,0 +1,174 @@
+package accessor
+
+import (
+	"database/sql"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/jmoiron/sqlx/reflectx"
+)
+
+const (
+	attrAutoCreateTime = "autoCreateTime"
+	attrAutoUpdateTime = "autoUpdateTime"
+)
+
+// Clock supplies timestamps for columns tagged with autoCreateTime or autoUpdateTime,
+// replace it to inject time in tests. Timestamps are always normalized to UTC.
+var Clock = time.Now
+
+// ChangeMarker is implemented by generated update trackers, it allows accessor to
+// register changes made on behalf of the caller, e.g. refreshed autoUpdateTime columns
+type ChangeMarker interface {
+	MarkChanged(tbl string, col string)
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func now() time.Time {
+	return Clock().UTC()
+}
+
+// entityColumnAttributes returns column -> attributes mapping of all mapped fields
+// in entity type, including fields of embedded types
+func entityColumnAttributes(typ reflect.Type) map[string]map[string]string {
+	colAttrs := map[string]map[string]string{}
+
+	typ = reflectx.Deref(typ)
+	if typ.Kind() != reflect.Struct {
+		return colAttrs
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+
+		if field.Anonymous {
+			ft := reflectx.Deref(field.Type)
+			if ft.Kind() == reflect.Struct {
+				for col, attrs := range entityColumnAttributes(ft) {
+					colAttrs[col] = attrs
+				}
+			}
+			continue
+		}
+
+		col, attrs, err := fieldMappedColumnWithAttributes(field, "db")
+		if err == nil && col != "" {
+			colAttrs[col] = attrs
+		}
+	}
+
+	return colAttrs
+}
+
+// autoUpdateTimeColumns returns columns of entity type tagged with autoUpdateTime, sorted
+func autoUpdateTimeColumns(typ reflect.Type) []string {
+	var cols []string
+	for col, attrs := range entityColumnAttributes(typ) {
+		if _, ok := attrs[attrAutoUpdateTime]; ok {
+			cols = append(cols, col)
+		}
+	}
+	sort.Strings(cols)
+
+	return cols
+}
+
+// touchCreateTimestamps fills zero-valued autoCreateTime and autoUpdateTime columns
+func (a *Accessor) touchCreateTimestamps(entity any) []string {
+	return a.touchTimestamps(entity, func(attrs map[string]string, v reflect.Value) bool {
+		if v.Kind() == reflect.Ptr && !v.IsNil() {
+			v = v.Elem()
+		}
+		if !v.IsZero() {
+			return false
+		}
+
+		_, created := attrs[attrAutoCreateTime]
+		_, updated := attrs[attrAutoUpdateTime]
+		return created || updated
+	})
+}
+
+// touchUpdateTimestamps refreshes autoUpdateTime columns
+func (a *Accessor) touchUpdateTimestamps(entity any) []string {
+	return a.touchTimestamps(entity, func(attrs map[string]string, _ reflect.Value) bool {
+		_, updated := attrs[attrAutoUpdateTime]
+		return updated
+	})
+}
+
+func (a *Accessor) touchTimestamps(
+	entity any,
+	predicate func(attrs map[string]string, v reflect.Value) bool,
+) []string {
+	if reflect.TypeOf(entity).Kind() != reflect.Ptr {
+		// entity values are not addressable
+		return nil
+	}
+
+	var touched []string
+
+	colAttrs := entityColumnAttributes(reflect.TypeOf(entity))
+	var colValueMap map[string]reflect.Value
+
+	t := now()
+	for col, attrs := range colAttrs {
+		if _, ok := attrs[attrAutoCreateTime]; !ok {
+			if _, ok := attrs[attrAutoUpdateTime]; !ok {
+				continue
+			}
+		}
+
+		if colValueMap == nil {
+			colValueMap = a.fieldMap(reflect.ValueOf(entity))
+		}
+
+		v, ok := colValueMap[col]
+		if !ok || !v.CanSet() || !predicate(attrs, v) {
+			continue
+		}
+
+		if setTimeValue(v, t) {
+			touched = append(touched, col)
+		}
+	}
+
+	return touched
+}
+
+// markChanged registers touched columns to the tracker under tables that own them
+func markChanged(entity any, s *EntityMappingSchema, cols []string) {
+	marker, ok := entity.(ChangeMarker)
+	if !ok || len(cols) == 0 {
+		return
+	}
+
+	for _, m := range s.Schemas() {
+		for _, col := range m.Columns {
+			if stringInSlice(col, cols) {
+				marker.MarkChanged(m.TableName, col)
+			}
+		}
+	}
+}
+
+func setTimeValue(v reflect.Value, t time.Time) bool {
+	switch {
+	case v.Type() == timeType:
+		v.Set(reflect.ValueOf(t))
+		return true
+
+	case v.Kind() == reflect.Ptr && v.Type().Elem() == timeType:
+		v.Set(reflect.ValueOf(&t))
+		return true
+
+	case v.CanAddr():
+		if scanner, ok := v.Addr().Interface().(sql.Scanner); ok {
+			return scanner.Scan(t) == nil
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,173 @@
+package accessor
+
+import (
+	"context"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+)
+
+type TimedBase struct {
+	Id        int        `db:"id"`
+	Name      string     `db:"name"`
+	CreatedAt time.Time  `db:"created_at,autoCreateTime"`
+	UpdatedAt *time.Time `db:"updated_at,autoUpdateTime"`
+}
+
+type TimedChild struct {
+	TimedBase `db:",table=timed_base"`
+	Attr      string `db:"attr"`
+}
+
+type TimedChildWithUpdateTracker struct {
+	TimedChild
+	trackMap map[string]map[string]bool
+}
+
+func (e *TimedChildWithUpdateTracker) MarkChanged(tbl string, col string) {
+	if e.trackMap == nil {
+		e.trackMap = make(map[string]map[string]bool)
+	}
+	if _, ok := e.trackMap[tbl]; !ok {
+		e.trackMap[tbl] = make(map[string]bool)
+	}
+	e.trackMap[tbl][col] = true
+}
+
+func (e *TimedChildWithUpdateTracker) ColumnsChanged(tbl ...string) []string {
+	cols := []string{}
+	if tbl == nil {
+		tbl = []string{"timed_child"}
+	}
+	for col := range e.trackMap[tbl[0]] {
+		cols = append(cols, col)
+	}
+	return cols
+}
+
+func (s *AccessorTestSuite) TestAutoTimestamps() {
+	req := require.New(s.T())
+
+	_ = s.Db.MustExec(`
+CREATE TABLE IF NOT EXISTS timed_base (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name text,
+    created_at timestamp,
+    updated_at timestamp
+);
+
+CREATE TABLE IF NOT EXISTS timed_child (
+    id integer primary key,
+    attr text
+);
+    `)
+	defer s.Db.MustExec(`
+DROP TABLE IF EXISTS timed_child;
+DROP TABLE IF EXISTS timed_base;
+    `)
+
+	saved := Clock
+	defer func() {
+		Clock = saved
+	}()
+
+	loc := time.FixedZone("UTC+8", 8*3600)
+	t1 := time.Date(2023, 1, 1, 8, 0, 0, 0, loc)
+	Clock = func() time.Time { return t1 }
+
+	a := New(s.Db)
+	ctx := context.Background()
+
+	// flat entity
+	b := TimedBase{Name: "flat"}
+	err := a.Create(ctx, &b, "timed_base")
+	req.NoError(err)
+	req.True(b.Id != 0)
+	req.True(b.CreatedAt.Equal(t1))
+	req.Equal(time.UTC, b.CreatedAt.Location())
+	req.True(b.UpdatedAt.Equal(t1))
+
+	// explicit creation time is kept
+	t0 := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	b2 := TimedBase{Name: "explicit", CreatedAt: t0}
+	err = a.Create(ctx, &b2, "timed_base")
+	req.NoError(err)
+	req.True(b2.CreatedAt.Equal(t0))
+
+	t2 := t1.Add(time.Hour)
+	Clock = func() time.Time { return t2 }
+
+	b.Name = "flat changed"
+	_, err = a.Update(ctx, &b, "timed_base")
+	req.NoError(err)
+	req.True(b.UpdatedAt.Equal(t2))
+
+	b = TimedBase{Id: b.Id}
+	err = a.Read(ctx, &b, "timed_base")
+	req.NoError(err)
+	req.Equal("flat changed", b.Name)
+	req.True(b.CreatedAt.Equal(t1))
+	req.True(b.UpdatedAt.Equal(t2))
+
+	// composite entity
+	Clock = func() time.Time { return t1 }
+	c := TimedChild{}
+	c.Name = "composite"
+	c.Attr = "foo"
+	err = a.Create(ctx, &c, "timed_child")
+	req.NoError(err)
+	req.True(c.CreatedAt.Equal(t1))
+	req.True(c.UpdatedAt.Equal(t1))
+
+	// tracker without changes is a no-op
+	Clock = func() time.Time { return t2 }
+	ct := &TimedChildWithUpdateTracker{}
+	ct.Id = c.Id
+	_, err = a.Update(ctx, ct, "timed_child")
+	req.NoError(err)
+	req.Nil(ct.UpdatedAt)
+
+	// auto-update column is refreshed and tracked in its owning table
+	ct.Attr = "bar"
+	ct.MarkChanged("timed_child", "attr")
+	_, err = a.Update(ctx, ct, "timed_child")
+	req.NoError(err)
+	req.Equal([]string{"updated_at"}, ct.ColumnsChanged("timed_base"))
+
+	c = TimedChild{}
+	c.Id = ct.Id
+	err = a.Read(ctx, &c, "timed_child")
+	req.NoError(err)
+	req.Equal("bar", c.Attr)
+	req.Equal("composite", c.Name)
+	req.True(c.CreatedAt.Equal(t1))
+	req.True(c.UpdatedAt.Equal(t2))
+
+	// creation time is not written by updates of entities that are not read first
+	_, err = a.Update(ctx, &TimedBase{Id: b.Id, Name: "flat blind"}, "timed_base")
+	req.NoError(err)
+	b = TimedBase{Id: b.Id}
+	req.NoError(a.Read(ctx, &b, "timed_base"))
+	req.Equal("flat blind", b.Name)
+	req.True(b.CreatedAt.Equal(t1))
+
+	_, err = a.UpdateWhere(ctx, TimedBase{}, "timed_base", map[string]any{"created_at": t2}, squirrel.Eq{"id": b.Id})
+	req.Error(err)
+
+	// set-based updates refresh auto-update columns unless they are set
+	t3 := t2.Add(time.Hour)
+	Clock = func() time.Time { return t3 }
+
+	_, err = a.UpdateWhere(ctx, TimedChild{}, "timed_child", map[string]any{"attr": "baz"}, squirrel.Eq{"timed_base.id": c.Id})
+	req.NoError(err)
+	req.NoError(a.Read(ctx, &c, "timed_child"))
+	req.Equal("baz", c.Attr)
+	req.True(c.CreatedAt.Equal(t1))
+	req.True(c.UpdatedAt.Equal(t3))
+
+	_, err = a.UpdateWhere(ctx, TimedBase{}, "timed_base", map[string]any{"updated_at": t1}, squirrel.Eq{"id": b.Id})
+	req.NoError(err)
+	req.NoError(a.Read(ctx, &b, "timed_base"))
+	req.True(b.UpdatedAt.Equal(t1))
+}
@@ -0,0 +1,154 @@
+package accessor
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	// ErrNoTransaction is returned by TxRunner of TxMandatory outside transaction
+	ErrNoTransaction = errors.New("no ambient transaction for mandatory propagation")
+
+	// ErrTransactionExists is returned by TxRunner of TxNever inside transaction
+	ErrTransactionExists = errors.New("ambient transaction exists for never propagation")
+
+	// ErrRollbackOnly is returned by ExecTx when a nested TxRunner joined the transaction
+	// and failed, while the error has not been returned to ExecTx
+	ErrRollbackOnly = errors.New("transaction rolled back as it has been marked rollback-only")
+)
+
+// Propagation decides how TxRunner runs in relation to the ambient transaction of ExecTx
+// carried by context
+type Propagation int
+
+const (
+	// TxRequired joins the ambient transaction, or starts a new one if there is none
+	TxRequired Propagation = iota
+
+	// TxRequiresNew always starts a new transaction on its own connection, the ambient
+	// transaction is left as it is
+	TxRequiresNew
+
+	// TxMandatory joins the ambient transaction, ErrNoTransaction is returned if there is none
+	TxMandatory
+
+	// TxNever runs without transaction, ErrTransactionExists is returned inside one
+	TxNever
+
+	// TxSupports joins the ambient transaction, or runs without transaction if there is none
+	TxSupports
+)
+
+// TxRunner runs functions in transactions according to its propagation mode, it carries the
+// transaction accessor in context as ExecTx does, so that nested runners and FromContext
+// join it. TxRunner is immutable, presets return modified copies.
+//
+// Options of the runner apply only to transactions it starts, runners that join the ambient
+// transaction take it as is. If a runner that joined the ambient transaction fails, the
+// transaction is marked rollback-only: ExecTx rolls it back even if the error is not
+// returned to it and reports ErrRollbackOnly.
+//
+// Usage example:
+/*
+	runner := accessor.NewTxRunner(db)
+
+	err := runner.Run(ctx, func(ctx context.Context, a *accessor.Accessor) error {
+		if err := a.Create(ctx, order, "orders"); err != nil {
+			return err
+		}
+
+		// audit log is kept even if the order fails
+		_ = runner.WithPropagation(accessor.TxRequiresNew).Run(ctx, writeAuditLog)
+
+		// joins the transaction of the order
+		return runner.WithPropagation(accessor.TxMandatory).Run(ctx, reserveStock)
+	})
+
+	err = runner.ReadOnly().WithIsolation(sql.LevelRepeatableRead).Run(ctx, report)
+*/
+type TxRunner struct {
+	Db *sqlx.DB
+
+	propagation Propagation
+	txOps       sql.TxOptions
+}
+
+// NewTxRunner returns a runner of TxRequired on db, with default transaction options
+func NewTxRunner(db *sqlx.DB) TxRunner {
+	return TxRunner{Db: db}
+}
+
+// WithPropagation returns a copy of the runner with the given propagation mode
+func (r TxRunner) WithPropagation(propagation Propagation) TxRunner {
+	r.propagation = propagation
+	return r
+}
+
+// ReadOnly returns a copy of the runner that starts read-only transactions
+func (r TxRunner) ReadOnly() TxRunner {
+	r.txOps.ReadOnly = true
+	return r
+}
+
+// WithIsolation returns a copy of the runner that starts transactions of the given
+// isolation level
+func (r TxRunner) WithIsolation(level sql.IsolationLevel) TxRunner {
+	r.txOps.Isolation = level
+	return r
+}
+
+// Run runs fn according to the propagation mode of the runner, fn is passed the accessor
+// to run with and the context carrying it
+func (r TxRunner) Run(ctx context.Context, fn func(ctx context.Context, a *Accessor) error) error {
+	if r.propagation == TxRequiresNew {
+		return r.execTx(ctx, fn)
+	}
+
+	ambient, ok := accessorFromContext(ctx)
+	if ok && ambient.txDb != r.Db {
+		return ErrTxDbMismatch
+	}
+
+	switch r.propagation {
+	case TxMandatory:
+		if !ok {
+			return ErrNoTransaction
+		}
+		return join(ctx, ambient, fn)
+
+	case TxNever:
+		if ok {
+			return ErrTransactionExists
+		}
+		return fn(ctx, New(r.Db))
+
+	case TxSupports:
+		if !ok {
+			return fn(ctx, New(r.Db))
+		}
+		return join(ctx, ambient, fn)
+
+	default:
+		if !ok {
+			return r.execTx(ctx, fn)
+		}
+		return join(ctx, ambient, fn)
+	}
+}
+
+func (r TxRunner) execTx(ctx context.Context, fn func(ctx context.Context, a *Accessor) error) error {
+	txOps := r.txOps
+	return ExecTx(ctx, r.Db, &txOps, fn)
+}
+
+// join runs fn in the ambient transaction, the transaction is marked rollback-only if fn fails
+func join(ctx context.Context, ambient *Accessor, fn func(ctx context.Context, a *Accessor) error) error {
+	err := fn(ctx, ambient)
+	if err != nil {
+		ambient.rollbackOnly = true
+	}
+	return err
+}
@@ -0,0 +1,117 @@
+package accessor
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *AccessorTestSuite) TestTxRunner() {
+	req := require.New(s.T())
+
+	s.setupCompositeTables()
+	defer s.teardownCompositeTables()
+
+	ctx := context.Background()
+	runner := NewTxRunner(s.Db)
+
+	noop := func(ctx context.Context, a *Accessor) error { return nil }
+
+	req.ErrorIs(runner.WithPropagation(TxMandatory).Run(ctx, noop), ErrNoTransaction)
+
+	// without transaction
+	for _, propagation := range []Propagation{TxNever, TxSupports} {
+		err := runner.WithPropagation(propagation).Run(ctx, func(ctx context.Context, a *Accessor) error {
+			req.Equal(s.Db, a.Db)
+			return nil
+		})
+		req.NoError(err)
+	}
+
+	err := runner.Run(ctx, func(ctx context.Context, a *Accessor) error {
+		_, ok := a.Db.(*sqlx.Tx)
+		req.True(ok)
+
+		for _, propagation := range []Propagation{TxRequired, TxMandatory, TxSupports} {
+			err := runner.WithPropagation(propagation).Run(ctx, func(ctx context.Context, ja *Accessor) error {
+				req.Same(a, ja)
+				return nil
+			})
+			req.NoError(err)
+		}
+
+		req.ErrorIs(runner.WithPropagation(TxNever).Run(ctx, noop), ErrTransactionExists)
+		req.ErrorIs(NewTxRunner(sqlx.NewDb(s.Db.DB, "sqlite3")).Run(ctx, noop), ErrTxDbMismatch)
+		return nil
+	})
+	req.NoError(err)
+
+	// failure of joined runner rolls back the transaction even if it is swallowed
+	e := BaseEntity{Name: "rollback"}
+	err = runner.Run(ctx, func(ctx context.Context, a *Accessor) error {
+		_ = runner.Run(ctx, func(ctx context.Context, a *Accessor) error {
+			if err := a.Create(ctx, &e, "base"); err != nil {
+				return err
+			}
+			return errors.New("failure")
+		})
+		return nil
+	})
+	req.ErrorIs(err, ErrRollbackOnly)
+	req.ErrorIs(New(s.Db).Read(ctx, &BaseEntity{Id: e.Id}, "base"), sql.ErrNoRows)
+
+	err = runner.ReadOnly().WithIsolation(sql.LevelSerializable).Run(ctx, func(ctx context.Context, a *Accessor) error {
+		return a.Read(ctx, &BaseEntity{Id: 1}, "base")
+	})
+	req.NoError(err)
+}
+
+func (s *AccessorTestSuite) TestTxRunnerRequiresNew() {
+	req := require.New(s.T())
+
+	// in-memory databases are per connection, transaction of its own needs a database
+	// shared by connections
+	db, err := sqlx.Open("sqlite3", filepath.Join(s.T().TempDir(), "tx.db"))
+	req.NoError(err)
+	defer db.Close()
+
+	_ = db.MustExec(`CREATE TABLE city (id INTEGER PRIMARY KEY AUTOINCREMENT, name text)`)
+
+	ctx := context.Background()
+	runner := NewTxRunner(db)
+	errRollback := errors.New("rollback")
+
+	err = runner.Run(ctx, func(ctx context.Context, a *Accessor) error {
+		err := runner.WithPropagation(TxRequiresNew).Run(ctx, func(ctx context.Context, na *Accessor) error {
+			req.NotSame(a, na)
+			req.NotEqual(a.Db, na.Db)
+
+			fa, err := FromContext(ctx, db)
+			req.NoError(err)
+			req.Same(na, fa)
+
+			_, err = na.Exec(ctx, "INSERT INTO city (name) VALUES (?)", "inner")
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		// the outer transaction starts writing once the inner one has committed, sqlite
+		// allows a single writer
+		if _, err := a.Exec(ctx, "INSERT INTO city (name) VALUES (?)", "outer"); err != nil {
+			return err
+		}
+		return errRollback
+	})
+	req.ErrorIs(err, errRollback)
+
+	// the inner write survives rollback of the outer transaction
+	var names []string
+	req.NoError(db.Select(&names, "SELECT name FROM city"))
+	req.Equal([]string{"inner"}, names)
+}
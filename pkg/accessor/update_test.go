@@ -0,0 +1,112 @@
+package accessor
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/stretchr/testify/require"
+)
+
+type GadgetEntity struct {
+	Id   int    `db:"id"`
+	Name string `db:"name"`
+	Code string `db:"code,readonly"`
+}
+
+type SmartGadgetEntity struct {
+	GadgetEntity `db:",table=gadget"`
+	Os           string `db:"os"`
+	Label        string `db:"label,readonly"`
+}
+
+func (s *AccessorTestSuite) TestUpdateReturning() {
+	req := require.New(s.T())
+
+	_ = s.Db.MustExec(`
+CREATE TABLE IF NOT EXISTS gadget (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name text,
+    code text GENERATED ALWAYS AS (upper(name)) VIRTUAL
+);
+
+CREATE TABLE IF NOT EXISTS smart_gadget (
+    id integer primary key,
+    os text,
+    label text GENERATED ALWAYS AS (upper(os)) VIRTUAL
+);
+    `)
+	defer func() {
+		_ = s.Db.MustExec(`
+DROP TABLE IF EXISTS smart_gadget;
+DROP TABLE IF EXISTS gadget;
+    `)
+	}()
+
+	a := New(s.Db)
+	ctx := context.Background()
+
+	g := GadgetEntity{Name: "foo"}
+	req.NoError(a.Create(ctx, &g, "gadget"))
+	req.Equal("FOO", g.Code)
+
+	// values computed by database are stale after Update
+	g.Name = "bar"
+	_, err := a.Update(ctx, &g, "gadget")
+	req.NoError(err)
+	req.Equal("FOO", g.Code)
+
+	g.Name = "baz"
+	req.NoError(a.UpdateReturning(ctx, &g, "gadget"))
+	req.Equal("BAZ", g.Code)
+
+	req.Error(a.UpdateReturning(ctx, g, "gadget"))
+	req.ErrorIs(a.UpdateReturning(ctx, &GadgetEntity{Id: 1000, Name: "none"}, "gadget"), sql.ErrNoRows)
+
+	// rows of all tables are merged back into composite entity
+	sg := SmartGadgetEntity{GadgetEntity: GadgetEntity{Name: "phone"}, Os: "android"}
+	req.NoError(a.Create(ctx, &sg, "smart_gadget"))
+	req.Equal("PHONE", sg.Code)
+	req.Equal("ANDROID", sg.Label)
+
+	sg.Name = "tablet"
+	sg.Os = "ios"
+	req.NoError(a.UpdateReturning(ctx, &sg, "smart_gadget"))
+	req.Equal("TABLET", sg.Code)
+	req.Equal("IOS", sg.Label)
+	req.Equal("tablet", sg.Name)
+
+	r := SmartGadgetEntity{GadgetEntity: GadgetEntity{Id: sg.Id}}
+	req.NoError(a.Read(ctx, &r, "smart_gadget"))
+	req.Equal(sg, r)
+}
+
+func (s *AccessorTestSuite) TestUpdateReturningMultipleRows() {
+	req := require.New(s.T())
+
+	a := New(s.Db)
+	ctx := context.Background()
+
+	// both persons have last name test
+	p := Person{FirstName: "baz", LastName: "test", Email: "baz@test"}
+	err := a.UpdateReturning(ctx, &p, "person", "LastName")
+	req.Error(err)
+	req.NotErrorIs(err, sql.ErrNoRows)
+
+	// the update is rolled back
+	var persons []Person
+	req.NoError(a.Select(ctx, &persons, "select * from person where last_name = ? order by first_name", "test"))
+	req.Len(persons, 2)
+	req.Equal("bar", persons[0].FirstName)
+	req.Equal("foo", persons[1].FirstName)
+
+	// keys matching a single row update it
+	p = Person{FirstName: "foo", LastName: "test", Email: "foo@example"}
+	req.NoError(a.UpdateReturning(ctx, &p, "person", "FirstName", "LastName"))
+	req.Equal("foo@example", p.Email)
+
+	result, err := a.updateEntity(ctx, &Person{FirstName: "foo", LastName: "test", Email: "foo@test"}, "person", true, "FirstName", "LastName")
+	req.NoError(err)
+	rowsAffected, err := result.RowsAffected()
+	req.NoError(err)
+	req.EqualValues(1, rowsAffected)
+}
@@ -0,0 +1,452 @@
+package accessor
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/reflectx"
+)
+
+// UpdateWhere updates all entity rows that satisfy the where predicate, without loading
+// them first. setMap maps column names (optionally qualified with table name, e.g. the
+// result of TableColumn.String()) to new values.
+//
+// entity is only used to determine entity type (can be a zero value). For composite entity,
+// keys of matched entities are resolved first through the same table JOIN as EntitySelect,
+// then each table that owns columns in setMap is updated by these keys, all in a transaction
+// (the one accessor is bound to, or a new one). Returned result reports number of matched
+// entities in this case.
+//
+// Columns tagged with autoUpdateTime are refreshed unless setMap sets them. Audited
+// entities are updated by keys as composite entities are, see RegisterAudit.
+//
+// Usage example:
+/*
+	result, err := a.UpdateWhere(
+		context.Background(),
+		Manager{},
+		"manager",
+		map[string]any{
+			ManagerCols.Title.String():   "VP",
+			ManagerCols.Company.String(): "foo.com",
+		},
+		ManagerCols.LastName.Eq("gdbc"),
+	)
+*/
+func (a *Accessor) UpdateWhere(
+	ctx context.Context,
+	entity any,
+	tbl string,
+	setMap map[string]any,
+	where Sqlizer,
+	idFields ...string,
+) (sql.Result, error) {
+	if entity == nil {
+		return nil, errors.New("missing entity")
+	}
+
+	s, err := EntitySchema(entity, reflect.TypeOf(entity), tbl)
+	if err != nil {
+		return nil, err
+	}
+
+	// matched entities are unknown up front, cached entities of the type are dropped
+	defer a.invalidateCachedTable(s)
+
+	tableSets, err := partitionSetMap(s, setMap)
+	if err != nil {
+		return nil, err
+	}
+
+	cols := auditedColumns(s.Entity)
+	if len(s.BaseMappings) == 0 && len(cols) == 0 {
+		return a.SqlizerExec(ctx, func(builder squirrel.StatementBuilderType) Sqlizer {
+			return builder.Update(tbl).SetMap(tableSets[tbl]).Where(withDiscriminator(s, where))
+		})
+	}
+
+	var ids []any
+	err = a.withinTx(ctx, func(ta *Accessor) error {
+		idColumns, matched, err := ta.selectKeys(ctx, s, where, idFields...)
+		if err != nil {
+			return err
+		}
+
+		ids = matched
+		return ta.auditedByKeys(ctx, AuditUpdate, cols, s, tbl, ids, func() error {
+			return ta.updateByKeys(ctx, s, tableSets, idColumns, ids)
+		}, idFields...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return bulkSqlResult{rowsAffected: int64(len(ids))}, nil
+}
+
+// UpdateWhereReturning works as UpdateWhere, updated entities are scanned into dest, which
+// must be a pointer to a slice of entities.
+//
+// For regular entity, "RETURNING *" is appended to the UPDATE statement, it requires the
+// dialect to support it (Postgres, SQLite 3.35+). For composite or audited entity, updated
+// entities are read back by their keys in the transaction of the update.
+func (a *Accessor) UpdateWhereReturning(
+	ctx context.Context,
+	dest any,
+	tbl string,
+	setMap map[string]any,
+	where Sqlizer,
+	idFields ...string,
+) error {
+	s, err := sliceEntitySchema(dest, tbl)
+	if err != nil {
+		return err
+	}
+
+	// matched entities are unknown up front, cached entities of the type are dropped
+	defer a.invalidateCachedTable(s)
+
+	tableSets, err := partitionSetMap(s, setMap)
+	if err != nil {
+		return err
+	}
+
+	cols := auditedColumns(s.Entity)
+	if len(s.BaseMappings) == 0 && len(cols) == 0 {
+		return a.SqlizerSelect(WithTable(ctx, tbl), dest, func(builder squirrel.StatementBuilderType) Sqlizer {
+			return builder.Update(tbl).SetMap(tableSets[tbl]).Where(withDiscriminator(s, where)).Suffix("RETURNING *")
+		})
+	}
+
+	return a.withinTx(ctx, func(ta *Accessor) error {
+		idColumns, ids, err := ta.selectKeys(ctx, s, where, idFields...)
+		if err != nil {
+			return err
+		}
+
+		err = ta.auditedByKeys(ctx, AuditUpdate, cols, s, tbl, ids, func() error {
+			return ta.updateByKeys(ctx, s, tableSets, idColumns, ids)
+		}, idFields...)
+		if err != nil {
+			return err
+		}
+
+		return ta.ReadMany(ctx, dest, tbl, ids, idFields...)
+	})
+}
+
+// DeleteWhere deletes all entity rows that satisfy the where predicate.
+//
+// entity is only used to determine entity type (can be a zero value). For composite entity,
+// keys of matched entities are resolved first through the same table JOIN as EntitySelect,
+// rows are then deleted from leaf table to root table, as DeleteMany does, all in a
+// transaction (the one accessor is bound to, or a new one). Returned result reports rows
+// affected in the root table in this case. Audited entities are deleted by keys as
+// composite entities are, see RegisterAudit.
+//
+// Usage example:
+/*
+	result, err := a.DeleteWhere(
+		context.Background(),
+		Manager{},
+		"manager",
+		ManagerCols.Company.Eq("foo.com"),
+	)
+*/
+func (a *Accessor) DeleteWhere(
+	ctx context.Context,
+	entity any,
+	tbl string,
+	where Sqlizer,
+	idFields ...string,
+) (sql.Result, error) {
+	if entity == nil {
+		return nil, errors.New("missing entity")
+	}
+
+	s, err := EntitySchema(entity, reflect.TypeOf(entity), tbl)
+	if err != nil {
+		return nil, err
+	}
+
+	// matched entities are unknown up front, cached entities of the type are dropped
+	defer a.invalidateCachedTable(s)
+
+	cols := auditedColumns(s.Entity)
+	if len(s.BaseMappings) == 0 && len(cols) == 0 {
+		return a.SqlizerExec(ctx, func(builder squirrel.StatementBuilderType) Sqlizer {
+			return builder.Delete(tbl).Where(withDiscriminator(s, where))
+		})
+	}
+
+	var affected int64
+	err = a.withinTx(ctx, func(ta *Accessor) error {
+		idColumns, ids, err := ta.selectKeys(ctx, s, where, idFields...)
+		if err != nil {
+			return err
+		}
+
+		return ta.auditedByKeys(ctx, AuditDelete, cols, s, tbl, ids, func() error {
+			affected, err = ta.deleteByKeys(ctx, s, idColumns, ids)
+			return err
+		}, idFields...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return bulkSqlResult{rowsAffected: affected}, nil
+}
+
+// DeleteWhereReturning works as DeleteWhere, deleted entities are scanned into dest, which
+// must be a pointer to a slice of entities.
+//
+// For regular entity, "RETURNING *" is appended to the DELETE statement, it requires the
+// dialect to support it (Postgres, SQLite 3.35+). For composite or audited entity, entities
+// are read by their keys before being deleted, in the transaction of the delete.
+func (a *Accessor) DeleteWhereReturning(
+	ctx context.Context,
+	dest any,
+	tbl string,
+	where Sqlizer,
+	idFields ...string,
+) error {
+	s, err := sliceEntitySchema(dest, tbl)
+	if err != nil {
+		return err
+	}
+
+	// matched entities are unknown up front, cached entities of the type are dropped
+	defer a.invalidateCachedTable(s)
+
+	cols := auditedColumns(s.Entity)
+	if len(s.BaseMappings) == 0 && len(cols) == 0 {
+		return a.SqlizerSelect(WithTable(ctx, tbl), dest, func(builder squirrel.StatementBuilderType) Sqlizer {
+			return builder.Delete(tbl).Where(withDiscriminator(s, where)).Suffix("RETURNING *")
+		})
+	}
+
+	return a.withinTx(ctx, func(ta *Accessor) error {
+		idColumns, ids, err := ta.selectKeys(ctx, s, where, idFields...)
+		if err != nil {
+			return err
+		}
+
+		if err = ta.ReadMany(ctx, dest, tbl, ids, idFields...); err != nil {
+			return err
+		}
+
+		return ta.auditedByKeys(ctx, AuditDelete, cols, s, tbl, ids, func() error {
+			_, err := ta.deleteByKeys(ctx, s, idColumns, ids)
+			return err
+		}, idFields...)
+	})
+}
+
+// withinTx runs fn in the transaction accessor is bound to, or in a new one
+func (a *Accessor) withinTx(ctx context.Context, fn func(ta *Accessor) error) error {
+	if _, ok := a.Db.(*sqlx.Tx); ok {
+		return fn(a)
+	} else if db, ok := a.Db.(*sqlx.DB); ok {
+		return ExecTx(ctx, db, nil, func(ctx context.Context, ta *Accessor) error {
+			// share snapshots taken by Track
+			ta.snapshots = a.snapshots
+			return fn(ta)
+		})
+	}
+
+	return errors.New("invalid accessor backend")
+}
+
+func sliceEntitySchema(dest any, tbl string) (*EntityMappingSchema, error) {
+	value := reflect.ValueOf(dest)
+	if value.Kind() != reflect.Ptr {
+		return nil, errors.New("must pass a pointer, not a value, to StructScan destination")
+	}
+	if value.IsNil() {
+		return nil, errors.New("nil pointer passed to StructScan destination")
+	}
+
+	slice, err := baseType(value.Type(), reflect.Slice)
+	if err != nil {
+		return nil, err
+	}
+
+	base := reflectx.Deref(slice.Elem())
+	return EntitySchema(reflect.New(base).Interface(), base, tbl)
+}
+
+// partitionSetMap groups columns to be updated by tables that own them
+func partitionSetMap(s *EntityMappingSchema, setMap map[string]any) (map[string]map[string]any, error) {
+	if len(setMap) == 0 {
+		return nil, errors.New("missing columns to update")
+	}
+
+	owners := map[string]string{}
+	for _, m := range s.Schemas() {
+		for _, col := range m.Columns {
+			owners[col] = m.TableName
+		}
+	}
+
+	encrypted := encryptedColumns(s.EntityType)
+	jsonCols := jsonColumns(s.EntityType)
+	nonUpdatable := nonUpdatableColumns(s.EntityType)
+
+	tableSets := map[string]map[string]any{}
+	for k, v := range setMap {
+		col := k
+		tbl := ""
+		if tokens := strings.SplitN(k, ".", 2); len(tokens) == 2 {
+			tbl, col = tokens[0], tokens[1]
+		}
+
+		owner, ok := owners[col]
+		if !ok || (tbl != "" && tbl != owner) {
+			return nil, fmt.Errorf("column %s is not mapped in entity type %s", k, s.EntityType.Name())
+		}
+
+		if nonUpdatable[col] {
+			return nil, fmt.Errorf("column %s is not updatable in entity type %s", k, s.EntityType.Name())
+		}
+
+		if _, isExpr := v.(Sqlizer); jsonCols[col] && v != nil && !isExpr {
+			var err error
+			if v, err = jsonValue(reflect.ValueOf(v)); err != nil {
+				return nil, fmt.Errorf("marshal json column %s: %w", col, err)
+			}
+		}
+
+		if deterministic, ok := encrypted[col]; ok {
+			var err error
+			if v, err = encryptValue(s.EntityType, s.TableName, col, v, deterministic); err != nil {
+				return nil, err
+			}
+		}
+
+		if _, ok := tableSets[owner]; !ok {
+			tableSets[owner] = map[string]any{}
+		}
+		tableSets[owner][col] = v
+	}
+
+	// autoUpdateTime columns are refreshed unless they are set explicitly
+	for _, col := range autoUpdateTimeColumns(s.EntityType) {
+		owner, ok := owners[col]
+		if !ok || nonUpdatable[col] {
+			continue
+		}
+		if _, ok := tableSets[owner][col]; ok {
+			continue
+		}
+
+		if _, ok := tableSets[owner]; !ok {
+			tableSets[owner] = map[string]any{}
+		}
+		tableSets[owner][col] = now()
+	}
+
+	return tableSets, nil
+}
+
+// selectKeys resolves keys of entity rows that satisfy the where predicate, a key is
+// a single value for single column key, or []any for composite key
+func (a *Accessor) selectKeys(
+	ctx context.Context,
+	s *EntityMappingSchema,
+	where Sqlizer,
+	idFields ...string,
+) ([]string, []any, error) {
+	if len(idFields) == 0 {
+		idFields = []string{"Id"}
+	}
+
+	idColumns, err := entityIdColumns(s.Entity, idFields...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := s.Tables()[0]
+	selection := make([]string, len(idColumns))
+	for i, col := range idColumns {
+		selection[i] = fmt.Sprintf("%s.%s", root, col)
+	}
+
+	builder, err := a.entitySelectBuilder(s, strings.Join(selection, ", "), idFields...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	q, args, err := builder.Where(where).ToSql()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := a.queryx(ctx, a.Db.Rebind(q), args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	ids := []any{}
+	for rows.Next() {
+		key, err := rows.SliceScan()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if len(key) == 1 {
+			ids = append(ids, key[0])
+		} else {
+			ids = append(ids, key)
+		}
+	}
+
+	return idColumns, ids, rows.Err()
+}
+
+func (a *Accessor) updateByKeys(
+	ctx context.Context,
+	s *EntityMappingSchema,
+	tableSets map[string]map[string]any,
+	idColumns []string,
+	ids []any,
+) error {
+	return chunkKeys(ids, func(chunk []any) error {
+		for _, m := range s.Schemas() {
+			sets, ok := tableSets[m.TableName]
+			if !ok {
+				continue
+			}
+
+			where, err := keysPredicate("", m.keyColumns(idColumns), chunk)
+			if err != nil {
+				return err
+			}
+
+			_, err = a.SqlizerExec(ctx, func(builder squirrel.StatementBuilderType) Sqlizer {
+				return builder.Update(m.TableName).SetMap(sets).Where(where)
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (a *Accessor) queryx(ctx context.Context, query string, args ...any) (*sqlx.Rows, error) {
+	if db, ok := a.Db.(*sqlx.DB); ok {
+		return db.Unsafe().QueryxContext(ctx, query, args...)
+	} else if tx, ok := a.Db.(*sqlx.Tx); ok {
+		return tx.Unsafe().QueryxContext(ctx, query, args...)
+	}
+
+	return nil, errors.New("invalid accessor backend")
+}
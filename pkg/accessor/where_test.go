@@ -0,0 +1,145 @@
+package accessor
+
+import (
+	"context"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *AccessorTestSuite) TestUpdateDeleteWhere() {
+	req := require.New(s.T())
+
+	a := New(s.Db)
+	ctx := context.Background()
+
+	result, err := a.UpdateWhere(ctx, PersonWithAge{}, "person",
+		map[string]any{"email": "changed@test", "person.age": 40},
+		squirrel.Eq{"last_name": "test"},
+	)
+	req.NoError(err)
+	affected, err := result.RowsAffected()
+	req.NoError(err)
+	req.Equal(int64(2), affected)
+
+	_, err = a.UpdateWhere(ctx, Person{}, "person", map[string]any{"unknown": 1}, nil)
+	req.Error(err)
+
+	var updated []PersonWithAge
+	err = a.UpdateWhereReturning(ctx, &updated, "person",
+		map[string]any{"age": 41},
+		squirrel.Eq{"first_name": "foo"},
+	)
+	req.NoError(err)
+	req.Equal(1, len(updated))
+	req.Equal("foo", updated[0].FirstName)
+	req.Equal(41, *updated[0].Age)
+
+	var deleted []*Person
+	err = a.DeleteWhereReturning(ctx, &deleted, "person", squirrel.Eq{"first_name": "bar"})
+	req.NoError(err)
+	req.Equal(1, len(deleted))
+	req.Equal("changed@test", deleted[0].Email)
+
+	result, err = a.DeleteWhere(ctx, Person{}, "person", squirrel.Eq{"first_name": "foo"})
+	req.NoError(err)
+	affected, err = result.RowsAffected()
+	req.NoError(err)
+	req.Equal(int64(1), affected)
+
+	// restore
+	s.setupTestDatabase()
+}
+
+func (s *AccessorTestSuite) TestUpdateDeleteWhereComposite() {
+	req := require.New(s.T())
+
+	s.setupCompositeTables()
+	defer s.teardownCompositeTables()
+
+	a := New(s.Db)
+	ctx := context.Background()
+
+	// predicate on base table, columns updated in two tables
+	result, err := a.UpdateWhere(ctx, GrandChildEntity{}, "grand_child",
+		map[string]any{"child_attr": "yellow", "grand_child.grand_child_attr": "banana"},
+		squirrel.Eq{"base.name": []string{"foo", "bar"}},
+	)
+	req.NoError(err)
+	affected, err := result.RowsAffected()
+	req.NoError(err)
+	req.Equal(int64(2), affected)
+
+	var updated []GrandChildEntity
+	err = a.UpdateWhereReturning(ctx, &updated, "grand_child",
+		map[string]any{"name": "qux"},
+		squirrel.Eq{"child.child_attr": "yellow", "base.name": "foo"},
+	)
+	req.NoError(err)
+	req.Equal(1, len(updated))
+	req.Equal("qux", updated[0].Name)
+	req.Equal("yellow", updated[0].ChildAttr)
+	req.Equal("banana", updated[0].GrandChildAttr)
+
+	var deleted []GrandChildEntity
+	err = a.DeleteWhereReturning(ctx, &deleted, "grand_child", squirrel.Eq{"child.child_attr": "yellow"})
+	req.NoError(err)
+	req.Equal(2, len(deleted))
+
+	result, err = a.DeleteWhere(ctx, GrandChildEntity{}, "grand_child", squirrel.Eq{"grand_child.grand_child_attr": "pear"})
+	req.NoError(err)
+	affected, err = result.RowsAffected()
+	req.NoError(err)
+	req.Equal(int64(1), affected)
+
+	for _, tbl := range []string{"base", "child", "grand_child"} {
+		n := 0
+		err = a.Get(ctx, &n, "SELECT COUNT(*) FROM "+tbl)
+		req.NoError(err)
+		req.Equal(0, n, tbl)
+	}
+}
+
+func (s *AccessorTestSuite) TestUpdateDeleteWhereCompositeTx() {
+	req := require.New(s.T())
+
+	s.setupCompositeTables()
+	defer s.teardownCompositeTables()
+
+	a := New(s.Db)
+	ctx := context.Background()
+
+	// tables updated before the failing one are rolled back
+	_ = s.Db.MustExec(`
+CREATE TRIGGER fail_grand_child BEFORE UPDATE ON grand_child
+BEGIN
+    SELECT RAISE(ABORT, 'failure');
+END;
+    `)
+
+	_, err := a.UpdateWhere(ctx, GrandChildEntity{}, "grand_child",
+		map[string]any{"name": "changed", "grand_child_attr": "changed"},
+		squirrel.Eq{"base.name": "foo"},
+	)
+	req.Error(err)
+
+	var e GrandChildEntity
+	req.NoError(a.FindByExample(ctx, &e, &GrandChildEntity{ChildEntity{BaseEntity: BaseEntity{Name: "foo"}}, ""}, "grand_child", nil))
+	req.Equal("apple", e.GrandChildAttr)
+
+	// composite entities that take DeleteCascade are deleted from the root table only
+	RegisterDeleteStrategy(DeleteCascade, GrandChildEntity{})
+	defer UnregisterDeleteStrategy(GrandChildEntity{})
+
+	result, err := a.DeleteWhere(ctx, GrandChildEntity{}, "grand_child", squirrel.Eq{"base.name": "foo"})
+	req.NoError(err)
+	affected, err := result.RowsAffected()
+	req.NoError(err)
+	req.Equal(int64(1), affected)
+
+	for tbl, expected := range map[string]int{"base": 2, "child": 3, "grand_child": 3} {
+		n := 0
+		req.NoError(a.Get(ctx, &n, "SELECT COUNT(*) FROM "+tbl))
+		req.Equal(expected, n, tbl)
+	}
+}
@@ -0,0 +1,87 @@
+package accessor
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/jmoiron/sqlx/reflectx"
+)
+
+// column attributes that restrict writes of columns
+const (
+	// column is never written, e.g. columns generated by database
+	attrReadonly = "readonly"
+
+	// column is written by Create only, e.g. `db:"added_at,insertonly"`
+	attrInsertonly = "insertonly"
+
+	// column is written by Update only
+	attrUpdateonly = "updateonly"
+
+	// column is omitted by Create when its value is zero, so that column default applies
+	attrOmitempty = "omitempty"
+)
+
+// CreateChangedColumnsOnly makes Create of entities wrapped by update trackers insert
+// only columns that are registered as changed (and primary key columns that are set),
+// so that column defaults apply to the rest. Entities without tracked changes are
+// inserted with all columns.
+var CreateChangedColumnsOnly = false
+
+type columnWriteModes struct {
+	nonInsertable map[string]bool
+	nonUpdatable  map[string]bool
+	omitempty     map[string]bool
+}
+
+var columnWriteModesCache sync.Map
+
+func entityColumnWriteModes(typ reflect.Type) *columnWriteModes {
+	typ = reflectx.Deref(typ)
+	if modes, ok := columnWriteModesCache.Load(typ); ok {
+		return modes.(*columnWriteModes)
+	}
+
+	modes := &columnWriteModes{
+		nonInsertable: map[string]bool{},
+		nonUpdatable:  map[string]bool{},
+		omitempty:     map[string]bool{},
+	}
+	for col, attrs := range entityColumnAttributes(typ) {
+		_, readonly := attrs[attrReadonly]
+		_, insertonly := attrs[attrInsertonly]
+		_, updateonly := attrs[attrUpdateonly]
+
+		if readonly || updateonly {
+			modes.nonInsertable[col] = true
+		}
+		// creation time is written once, unless the column is refreshed by updates too
+		_, created := attrs[attrAutoCreateTime]
+		_, updated := attrs[attrAutoUpdateTime]
+
+		if readonly || insertonly || created && !updated {
+			modes.nonUpdatable[col] = true
+		}
+		if _, ok := attrs[attrOmitempty]; ok {
+			modes.omitempty[col] = true
+		}
+	}
+
+	columnWriteModesCache.Store(typ, modes)
+	return modes
+}
+
+// nonInsertableColumns returns columns of entity type that Create should not write
+func nonInsertableColumns(typ reflect.Type) map[string]bool {
+	return entityColumnWriteModes(typ).nonInsertable
+}
+
+// nonUpdatableColumns returns columns of entity type that Update should not write
+func nonUpdatableColumns(typ reflect.Type) map[string]bool {
+	return entityColumnWriteModes(typ).nonUpdatable
+}
+
+// omitemptyColumns returns columns of entity type that Create omits when they are zero
+func omitemptyColumns(typ reflect.Type) map[string]bool {
+	return entityColumnWriteModes(typ).omitempty
+}
@@ -0,0 +1,116 @@
+package accessor
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type InsertonlyChildEntity struct {
+	BaseEntity `db:",table=base"`
+	ChildAttr  string `db:"child_attr,insertonly"`
+}
+
+type ReadonlyChildEntity struct {
+	BaseEntity `db:",table=base"`
+	ChildAttr  *string `db:"child_attr,readonly"`
+}
+
+func (s *AccessorTestSuite) TestColumnWriteModesComposite() {
+	req := require.New(s.T())
+
+	s.setupCompositeTables()
+	defer s.teardownCompositeTables()
+
+	a := New(s.Db)
+	ctx := context.Background()
+
+	e := InsertonlyChildEntity{BaseEntity: BaseEntity{Name: "qux"}, ChildAttr: "blue"}
+	req.NoError(a.Create(ctx, &e, "child"))
+
+	e.Name = "quux"
+	e.ChildAttr = "yellow"
+	_, err := a.Update(ctx, &e, "child")
+	req.NoError(err)
+
+	r := ChildEntity{}
+	r.Id = e.Id
+	req.NoError(a.Read(ctx, &r, "child"))
+	req.Equal("quux", r.Name)
+	req.Equal("blue", r.ChildAttr)
+
+	white := "white"
+	ro := ReadonlyChildEntity{BaseEntity: BaseEntity{Name: "corge"}, ChildAttr: &white}
+	req.NoError(a.Create(ctx, &ro, "child"))
+	req.Nil(ro.ChildAttr)
+
+	_, err = a.UpdateWhere(ctx, ReadonlyChildEntity{}, "child",
+		map[string]any{"child_attr": "black"},
+		nil,
+	)
+	req.Error(err)
+}
+
+type Gadget struct {
+	Id        int       `db:"id"`
+	Name      string    `db:"name,omitempty"`
+	Color     string    `db:"color,omitempty"`
+	CreatedAt time.Time `db:"created_at,omitempty"`
+}
+
+type GadgetChild struct {
+	Gadget `db:",table=gadget"`
+	Size   *string `db:"size,omitempty"`
+}
+
+func (s *AccessorTestSuite) TestCreateOmitempty() {
+	req := require.New(s.T())
+
+	_ = s.Db.MustExec(`
+CREATE TABLE IF NOT EXISTS gadget (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name text DEFAULT 'unnamed',
+    color text DEFAULT 'black',
+    created_at timestamp DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS gadget_child (
+    id integer primary key,
+    size text DEFAULT 'M'
+);
+    `)
+	defer func() {
+		_ = s.Db.MustExec(`
+DROP TABLE IF EXISTS gadget_child;
+DROP TABLE IF EXISTS gadget;
+    `)
+	}()
+
+	a := New(s.Db)
+	ctx := context.Background()
+
+	g := Gadget{Name: "phone"}
+	req.NoError(a.Create(ctx, &g, "gadget"))
+	req.Equal("phone", g.Name)
+	req.Equal("black", g.Color)
+	req.False(g.CreatedAt.IsZero())
+
+	// all columns take their defaults
+	g = Gadget{}
+	req.NoError(a.Create(ctx, &g, "gadget"))
+	req.True(g.Id > 0)
+	req.Equal("unnamed", g.Name)
+
+	small := "S"
+	c := GadgetChild{Gadget: Gadget{Color: "white"}}
+	req.NoError(a.Create(ctx, &c, "gadget_child"))
+	req.Equal("unnamed", c.Name)
+	req.Equal("white", c.Color)
+	req.Equal("M", *c.Size)
+
+	c = GadgetChild{Size: &small}
+	req.NoError(a.Create(ctx, &c, "gadget_child"))
+	req.Equal("black", c.Color)
+	req.Equal("S", *c.Size)
+}
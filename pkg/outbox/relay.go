@@ -0,0 +1,196 @@
+// Package outbox delivers messages written by accessor.Enqueue.
+//
+// A Relay polls accessor.OutboxTable for pending messages, dispatches them to a
+// Publisher and marks them as sent. Failed messages are retried after RetryDelay
+// (growing linearly with attempts), and dead-lettered once MaxAttempts is reached.
+//
+// On Postgres, rows are claimed with "FOR UPDATE SKIP LOCKED", so that multiple
+// relays (in one or more processes) can poll the same table. On other databases
+// (e.g. SQLite), polls of a Relay are serialized by a lock, only one relay should
+// be run against a database.
+//
+// Delivery is at-least-once, a message is published again if the relay crashes
+// before the poll transaction commits, publishers should be idempotent.
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kelveny/gdbc/pkg/accessor"
+	"github.com/kelveny/gdbc/pkg/logger"
+)
+
+// Publisher dispatches outbox messages to a message broker
+type Publisher interface {
+	Publish(ctx context.Context, msg accessor.OutboxMessage) error
+}
+
+// PublisherFunc adapts a function to Publisher
+type PublisherFunc func(ctx context.Context, msg accessor.OutboxMessage) error
+
+func (f PublisherFunc) Publish(ctx context.Context, msg accessor.OutboxMessage) error {
+	return f(ctx, msg)
+}
+
+type Relay struct {
+	Db        *sqlx.DB
+	Publisher Publisher
+
+	// maximum number of messages claimed by a poll
+	BatchSize int
+
+	// number of failed attempts after which a message is dead-lettered
+	MaxAttempts int
+
+	// delay before the first retry of a failed message
+	RetryDelay time.Duration
+
+	// interval between polls in Run
+	PollInterval time.Duration
+
+	mu sync.Mutex
+}
+
+// Usage example:
+/*
+	relay := outbox.NewRelay(db, outbox.PublisherFunc(
+		func(ctx context.Context, msg accessor.OutboxMessage) error {
+			return producer.Send(ctx, msg.Topic, msg.Payload)
+		},
+	))
+
+	go relay.Run(ctx)
+*/
+func NewRelay(db *sqlx.DB, publisher Publisher) *Relay {
+	return &Relay{
+		Db:           db,
+		Publisher:    publisher,
+		BatchSize:    100,
+		MaxAttempts:  5,
+		RetryDelay:   10 * time.Second,
+		PollInterval: time.Second,
+	}
+}
+
+// Run polls until ctx is done, poll errors are logged and polling continues
+func (r *Relay) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.Poll(ctx); err != nil && ctx.Err() == nil {
+			logger.Log(logger.WARN, "outbox relay poll failed: %v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll claims a batch of pending messages that are due, publishes them and records
+// delivery results in one transaction. It returns number of messages sent.
+func (r *Relay) Poll(ctx context.Context) (sent int, outErr error) {
+	skipLocked := r.skipLocked()
+	if !skipLocked {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+	}
+
+	outErr = accessor.ExecTx(ctx, r.Db, nil, func(ctx context.Context, a *accessor.Accessor) error {
+		t := accessor.Clock().UTC()
+
+		var msgs []accessor.OutboxMessage
+		err := a.SqlizerSelect(ctx, &msgs, func(builder squirrel.StatementBuilderType) accessor.Sqlizer {
+			q := builder.Select("*").
+				From(accessor.OutboxTable).
+				Where(squirrel.Eq{"status": accessor.OutboxPending}).
+				Where(squirrel.LtOrEq{"available_at": t}).
+				OrderBy("id").
+				Limit(uint64(r.BatchSize))
+
+			if skipLocked {
+				q = q.Suffix("FOR UPDATE SKIP LOCKED")
+			}
+			return q
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, msg := range msgs {
+			setMap, ok := r.dispatch(ctx, msg, t)
+			if ok {
+				sent++
+			}
+
+			_, err := a.SqlizerExec(ctx, func(builder squirrel.StatementBuilderType) accessor.Sqlizer {
+				return builder.Update(accessor.OutboxTable).
+					SetMap(setMap).
+					Where(squirrel.Eq{"id": msg.Id})
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if outErr != nil {
+		sent = 0
+	}
+	return
+}
+
+// dispatch publishes msg and returns columns to update as the result
+func (r *Relay) dispatch(ctx context.Context, msg accessor.OutboxMessage, t time.Time) (map[string]any, bool) {
+	attempts := msg.Attempts + 1
+
+	err := r.publish(ctx, msg)
+	if err == nil {
+		return map[string]any{
+			"status":   accessor.OutboxSent,
+			"attempts": attempts,
+			"sent_at":  t,
+		}, true
+	}
+
+	setMap := map[string]any{
+		"attempts":     attempts,
+		"last_error":   err.Error(),
+		"available_at": t.Add(r.RetryDelay * time.Duration(attempts)),
+	}
+
+	if attempts >= r.MaxAttempts {
+		setMap["status"] = accessor.OutboxDead
+	}
+	return setMap, false
+}
+
+// publish shields the poll transaction from publisher panics
+func (r *Relay) publish(ctx context.Context, msg accessor.OutboxMessage) (err error) {
+	defer func() {
+		if c := recover(); c != nil {
+			err = fmt.Errorf("panic in publishing message: %v", c)
+		}
+	}()
+
+	return r.Publisher.Publish(ctx, msg)
+}
+
+func (r *Relay) skipLocked() bool {
+	switch r.Db.DriverName() {
+	case "postgres", "pgx":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kelveny/gdbc/pkg/accessor"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func setupOutbox(t *testing.T) *sqlx.DB {
+	db, err := sqlx.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+
+	// keep the in-memory database on a single connection
+	db.SetMaxOpenConns(1)
+
+	_ = db.MustExec(`
+CREATE TABLE outbox (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    topic text NOT NULL,
+    payload blob,
+    status text NOT NULL,
+    attempts integer NOT NULL DEFAULT 0,
+    last_error text,
+    created_at timestamp NOT NULL,
+    available_at timestamp NOT NULL,
+    sent_at timestamp
+);
+    `)
+
+	return db
+}
+
+func Test_Enqueue(t *testing.T) {
+	req := require.New(t)
+
+	db := setupOutbox(t)
+	defer db.Close()
+
+	err := accessor.Enqueue(context.Background(), "order.created", "{}")
+	req.Error(err)
+
+	err = accessor.ExecTx(context.Background(), db, nil, func(ctx context.Context, a *accessor.Accessor) error {
+		return accessor.Enqueue(ctx, "order.created", map[string]any{"id": 1})
+	})
+	req.NoError(err)
+
+	// message is discarded with the transaction
+	err = accessor.ExecTx(context.Background(), db, nil, func(ctx context.Context, a *accessor.Accessor) error {
+		if err := accessor.Enqueue(ctx, "order.created", map[string]any{"id": 2}); err != nil {
+			return err
+		}
+		return errors.New("rollback")
+	})
+	req.Error(err)
+
+	var msgs []accessor.OutboxMessage
+	req.NoError(db.Select(&msgs, "SELECT * FROM outbox"))
+	req.Equal(1, len(msgs))
+	req.Equal("order.created", msgs[0].Topic)
+	req.JSONEq(`{"id":1}`, string(msgs[0].Payload))
+	req.Equal(accessor.OutboxPending, msgs[0].Status)
+}
+
+func Test_RelayPoll(t *testing.T) {
+	req := require.New(t)
+
+	db := setupOutbox(t)
+	defer db.Close()
+
+	err := accessor.ExecTx(context.Background(), db, nil, func(ctx context.Context, a *accessor.Accessor) error {
+		for _, topic := range []string{"good", "bad", "good"} {
+			if err := accessor.Enqueue(ctx, topic, []byte(topic)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	req.NoError(err)
+
+	published := []string{}
+	relay := NewRelay(db, PublisherFunc(func(ctx context.Context, msg accessor.OutboxMessage) error {
+		if msg.Topic == "bad" {
+			return errors.New("broker unavailable")
+		}
+		published = append(published, string(msg.Payload))
+		return nil
+	}))
+	relay.MaxAttempts = 2
+	relay.RetryDelay = time.Hour
+
+	sent, err := relay.Poll(context.Background())
+	req.NoError(err)
+	req.Equal(2, sent)
+	req.Equal([]string{"good", "good"}, published)
+
+	// failed message is not due until retry delay passes
+	sent, err = relay.Poll(context.Background())
+	req.NoError(err)
+	req.Equal(0, sent)
+
+	var msg accessor.OutboxMessage
+	req.NoError(db.Get(&msg, "SELECT * FROM outbox WHERE topic='bad'"))
+	req.Equal(accessor.OutboxPending, msg.Status)
+	req.Equal(1, msg.Attempts)
+	req.Equal("broker unavailable", msg.LastError.String)
+
+	relay.RetryDelay = 0
+	_, err = db.Exec("UPDATE outbox SET available_at=? WHERE topic='bad'", time.Now().UTC().Add(-time.Minute))
+	req.NoError(err)
+
+	sent, err = relay.Poll(context.Background())
+	req.NoError(err)
+	req.Equal(0, sent)
+
+	req.NoError(db.Get(&msg, "SELECT * FROM outbox WHERE topic='bad'"))
+	req.Equal(accessor.OutboxDead, msg.Status)
+	req.Equal(2, msg.Attempts)
+
+	var statuses []string
+	req.NoError(db.Select(&statuses, "SELECT status FROM outbox ORDER BY id"))
+	req.Equal([]string{accessor.OutboxSent, accessor.OutboxDead, accessor.OutboxSent}, statuses)
+}
@@ -4,6 +4,8 @@ package crosspkg
 
 import (
 	"time"
+
+	"github.com/kelveny/gdbc/pkg/accessor"
 )
 
 type Executive2EntityFields struct {
@@ -30,6 +32,32 @@ func (e *Executive2) TableColumns() *Executive2TableColumns {
 	}
 }
 
+type Executive2ColumnCriteria struct {
+	Term        accessor.TableColumn
+	Title       accessor.TableColumn
+	Company     accessor.TableColumn
+	Id          accessor.TableColumn
+	FirstName   accessor.TableColumn
+	LastName    accessor.TableColumn
+	Email       accessor.TableColumn
+	Age         accessor.TableColumn
+	CurrentMood accessor.TableColumn
+	AddedAt     accessor.TableColumn
+}
+
+var Executive2Cols = Executive2ColumnCriteria{
+	Term:        accessor.NewTableColumn("executive", "term"),
+	Title:       accessor.NewTableColumn("manager", "title"),
+	Company:     accessor.NewTableColumn("employee", "company"),
+	Id:          accessor.NewTableColumn("person", "id"),
+	FirstName:   accessor.NewTableColumn("person", "first_name"),
+	LastName:    accessor.NewTableColumn("person", "last_name"),
+	Email:       accessor.NewTableColumn("person", "email"),
+	Age:         accessor.NewTableColumn("person", "age"),
+	CurrentMood: accessor.NewTableColumn("person", "current_mood"),
+	AddedAt:     accessor.NewTableColumn("person", "added_at"),
+}
+
 type Executive2WithUpdateTracker struct {
 	Executive2
 	trackMap map[string]map[string]bool
@@ -50,6 +78,10 @@ func (e *Executive2WithUpdateTracker) registerChange(tbl string, col string) {
 	}
 }
 
+func (e *Executive2WithUpdateTracker) MarkChanged(tbl string, col string) {
+	e.registerChange(tbl, col)
+}
+
 func (e *Executive2WithUpdateTracker) ColumnsChanged(tbl ...string) []string {
 	cols := []string{}
 
@@ -73,18 +105,36 @@ func (e *Executive2WithUpdateTracker) SetTerm(val *string) *Executive2WithUpdate
 	return e
 }
 
+func (e *Executive2WithUpdateTracker) SetTermNull() *Executive2WithUpdateTracker {
+	e.Term = nil
+	e.registerChange("executive", "term")
+	return e
+}
+
 func (e *Executive2WithUpdateTracker) SetTitle(val *string) *Executive2WithUpdateTracker {
 	e.Title = val
 	e.registerChange("manager", "title")
 	return e
 }
 
+func (e *Executive2WithUpdateTracker) SetTitleNull() *Executive2WithUpdateTracker {
+	e.Title = nil
+	e.registerChange("manager", "title")
+	return e
+}
+
 func (e *Executive2WithUpdateTracker) SetCompany(val *string) *Executive2WithUpdateTracker {
 	e.Company = val
 	e.registerChange("employee", "company")
 	return e
 }
 
+func (e *Executive2WithUpdateTracker) SetCompanyNull() *Executive2WithUpdateTracker {
+	e.Company = nil
+	e.registerChange("employee", "company")
+	return e
+}
+
 func (e *Executive2WithUpdateTracker) SetId(val int) *Executive2WithUpdateTracker {
 	e.Id = val
 	e.registerChange("person", "id")
@@ -109,20 +159,44 @@ func (e *Executive2WithUpdateTracker) SetEmail(val *string) *Executive2WithUpdat
 	return e
 }
 
+func (e *Executive2WithUpdateTracker) SetEmailNull() *Executive2WithUpdateTracker {
+	e.Email = nil
+	e.registerChange("person", "email")
+	return e
+}
+
 func (e *Executive2WithUpdateTracker) SetAge(val *int) *Executive2WithUpdateTracker {
 	e.Age = val
 	e.registerChange("person", "age")
 	return e
 }
 
+func (e *Executive2WithUpdateTracker) SetAgeNull() *Executive2WithUpdateTracker {
+	e.Age = nil
+	e.registerChange("person", "age")
+	return e
+}
+
 func (e *Executive2WithUpdateTracker) SetCurrentMood(val *string) *Executive2WithUpdateTracker {
 	e.CurrentMood = val
 	e.registerChange("person", "current_mood")
 	return e
 }
 
+func (e *Executive2WithUpdateTracker) SetCurrentMoodNull() *Executive2WithUpdateTracker {
+	e.CurrentMood = nil
+	e.registerChange("person", "current_mood")
+	return e
+}
+
 func (e *Executive2WithUpdateTracker) SetAddedAt(val *time.Time) *Executive2WithUpdateTracker {
 	e.AddedAt = val
 	e.registerChange("person", "added_at")
 	return e
 }
+
+func (e *Executive2WithUpdateTracker) SetAddedAtNull() *Executive2WithUpdateTracker {
+	e.AddedAt = nil
+	e.registerChange("person", "added_at")
+	return e
+}
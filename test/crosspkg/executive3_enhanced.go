@@ -4,6 +4,8 @@ package crosspkg
 
 import (
 	"time"
+
+	"github.com/kelveny/gdbc/pkg/accessor"
 )
 
 type Executive3EntityFields struct {
@@ -30,6 +32,32 @@ func (e *Executive3) TableColumns() *Executive3TableColumns {
 	}
 }
 
+type Executive3ColumnCriteria struct {
+	Term        accessor.TableColumn
+	Title       accessor.TableColumn
+	Company     accessor.TableColumn
+	Id          accessor.TableColumn
+	FirstName   accessor.TableColumn
+	LastName    accessor.TableColumn
+	Email       accessor.TableColumn
+	Age         accessor.TableColumn
+	CurrentMood accessor.TableColumn
+	AddedAt     accessor.TableColumn
+}
+
+var Executive3Cols = Executive3ColumnCriteria{
+	Term:        accessor.NewTableColumn("executive", "term"),
+	Title:       accessor.NewTableColumn("manager", "title"),
+	Company:     accessor.NewTableColumn("employee", "company"),
+	Id:          accessor.NewTableColumn("person", "id"),
+	FirstName:   accessor.NewTableColumn("person", "first_name"),
+	LastName:    accessor.NewTableColumn("person", "last_name"),
+	Email:       accessor.NewTableColumn("person", "email"),
+	Age:         accessor.NewTableColumn("person", "age"),
+	CurrentMood: accessor.NewTableColumn("person", "current_mood"),
+	AddedAt:     accessor.NewTableColumn("person", "added_at"),
+}
+
 type Executive3WithUpdateTracker struct {
 	Executive3
 	trackMap map[string]map[string]bool
@@ -50,6 +78,10 @@ func (e *Executive3WithUpdateTracker) registerChange(tbl string, col string) {
 	}
 }
 
+func (e *Executive3WithUpdateTracker) MarkChanged(tbl string, col string) {
+	e.registerChange(tbl, col)
+}
+
 func (e *Executive3WithUpdateTracker) ColumnsChanged(tbl ...string) []string {
 	cols := []string{}
 
@@ -73,18 +105,36 @@ func (e *Executive3WithUpdateTracker) SetTerm(val *string) *Executive3WithUpdate
 	return e
 }
 
+func (e *Executive3WithUpdateTracker) SetTermNull() *Executive3WithUpdateTracker {
+	e.Term = nil
+	e.registerChange("executive", "term")
+	return e
+}
+
 func (e *Executive3WithUpdateTracker) SetTitle(val *string) *Executive3WithUpdateTracker {
 	e.Title = val
 	e.registerChange("manager", "title")
 	return e
 }
 
+func (e *Executive3WithUpdateTracker) SetTitleNull() *Executive3WithUpdateTracker {
+	e.Title = nil
+	e.registerChange("manager", "title")
+	return e
+}
+
 func (e *Executive3WithUpdateTracker) SetCompany(val *string) *Executive3WithUpdateTracker {
 	e.Company = val
 	e.registerChange("employee", "company")
 	return e
 }
 
+func (e *Executive3WithUpdateTracker) SetCompanyNull() *Executive3WithUpdateTracker {
+	e.Company = nil
+	e.registerChange("employee", "company")
+	return e
+}
+
 func (e *Executive3WithUpdateTracker) SetId(val int) *Executive3WithUpdateTracker {
 	e.Id = val
 	e.registerChange("person", "id")
@@ -109,20 +159,44 @@ func (e *Executive3WithUpdateTracker) SetEmail(val *string) *Executive3WithUpdat
 	return e
 }
 
+func (e *Executive3WithUpdateTracker) SetEmailNull() *Executive3WithUpdateTracker {
+	e.Email = nil
+	e.registerChange("person", "email")
+	return e
+}
+
 func (e *Executive3WithUpdateTracker) SetAge(val *int) *Executive3WithUpdateTracker {
 	e.Age = val
 	e.registerChange("person", "age")
 	return e
 }
 
+func (e *Executive3WithUpdateTracker) SetAgeNull() *Executive3WithUpdateTracker {
+	e.Age = nil
+	e.registerChange("person", "age")
+	return e
+}
+
 func (e *Executive3WithUpdateTracker) SetCurrentMood(val *string) *Executive3WithUpdateTracker {
 	e.CurrentMood = val
 	e.registerChange("person", "current_mood")
 	return e
 }
 
+func (e *Executive3WithUpdateTracker) SetCurrentMoodNull() *Executive3WithUpdateTracker {
+	e.CurrentMood = nil
+	e.registerChange("person", "current_mood")
+	return e
+}
+
 func (e *Executive3WithUpdateTracker) SetAddedAt(val *time.Time) *Executive3WithUpdateTracker {
 	e.AddedAt = val
 	e.registerChange("person", "added_at")
 	return e
 }
+
+func (e *Executive3WithUpdateTracker) SetAddedAtNull() *Executive3WithUpdateTracker {
+	e.AddedAt = nil
+	e.registerChange("person", "added_at")
+	return e
+}
@@ -4,6 +4,8 @@ package crosspkg
 
 import (
 	"time"
+
+	"github.com/kelveny/gdbc/pkg/accessor"
 )
 
 type Executive4EntityFields struct {
@@ -30,6 +32,32 @@ func (e *Executive4) TableColumns() *Executive4TableColumns {
 	}
 }
 
+type Executive4ColumnCriteria struct {
+	Term        accessor.TableColumn
+	Title       accessor.TableColumn
+	Company     accessor.TableColumn
+	Id          accessor.TableColumn
+	FirstName   accessor.TableColumn
+	LastName    accessor.TableColumn
+	Email       accessor.TableColumn
+	Age         accessor.TableColumn
+	CurrentMood accessor.TableColumn
+	AddedAt     accessor.TableColumn
+}
+
+var Executive4Cols = Executive4ColumnCriteria{
+	Term:        accessor.NewTableColumn("executive", "term"),
+	Title:       accessor.NewTableColumn("manager", "title"),
+	Company:     accessor.NewTableColumn("employee", "company"),
+	Id:          accessor.NewTableColumn("person", "id"),
+	FirstName:   accessor.NewTableColumn("person", "first_name"),
+	LastName:    accessor.NewTableColumn("person", "last_name"),
+	Email:       accessor.NewTableColumn("person", "email"),
+	Age:         accessor.NewTableColumn("person", "age"),
+	CurrentMood: accessor.NewTableColumn("person", "current_mood"),
+	AddedAt:     accessor.NewTableColumn("person", "added_at"),
+}
+
 type Executive4WithUpdateTracker struct {
 	Executive4
 	trackMap map[string]map[string]bool
@@ -50,6 +78,10 @@ func (e *Executive4WithUpdateTracker) registerChange(tbl string, col string) {
 	}
 }
 
+func (e *Executive4WithUpdateTracker) MarkChanged(tbl string, col string) {
+	e.registerChange(tbl, col)
+}
+
 func (e *Executive4WithUpdateTracker) ColumnsChanged(tbl ...string) []string {
 	cols := []string{}
 
@@ -73,18 +105,36 @@ func (e *Executive4WithUpdateTracker) SetTerm(val *string) *Executive4WithUpdate
 	return e
 }
 
+func (e *Executive4WithUpdateTracker) SetTermNull() *Executive4WithUpdateTracker {
+	e.Term = nil
+	e.registerChange("executive", "term")
+	return e
+}
+
 func (e *Executive4WithUpdateTracker) SetTitle(val *string) *Executive4WithUpdateTracker {
 	e.Title = val
 	e.registerChange("manager", "title")
 	return e
 }
 
+func (e *Executive4WithUpdateTracker) SetTitleNull() *Executive4WithUpdateTracker {
+	e.Title = nil
+	e.registerChange("manager", "title")
+	return e
+}
+
 func (e *Executive4WithUpdateTracker) SetCompany(val *string) *Executive4WithUpdateTracker {
 	e.Company = val
 	e.registerChange("employee", "company")
 	return e
 }
 
+func (e *Executive4WithUpdateTracker) SetCompanyNull() *Executive4WithUpdateTracker {
+	e.Company = nil
+	e.registerChange("employee", "company")
+	return e
+}
+
 func (e *Executive4WithUpdateTracker) SetId(val int) *Executive4WithUpdateTracker {
 	e.Id = val
 	e.registerChange("person", "id")
@@ -109,20 +159,44 @@ func (e *Executive4WithUpdateTracker) SetEmail(val *string) *Executive4WithUpdat
 	return e
 }
 
+func (e *Executive4WithUpdateTracker) SetEmailNull() *Executive4WithUpdateTracker {
+	e.Email = nil
+	e.registerChange("person", "email")
+	return e
+}
+
 func (e *Executive4WithUpdateTracker) SetAge(val *int) *Executive4WithUpdateTracker {
 	e.Age = val
 	e.registerChange("person", "age")
 	return e
 }
 
+func (e *Executive4WithUpdateTracker) SetAgeNull() *Executive4WithUpdateTracker {
+	e.Age = nil
+	e.registerChange("person", "age")
+	return e
+}
+
 func (e *Executive4WithUpdateTracker) SetCurrentMood(val *string) *Executive4WithUpdateTracker {
 	e.CurrentMood = val
 	e.registerChange("person", "current_mood")
 	return e
 }
 
+func (e *Executive4WithUpdateTracker) SetCurrentMoodNull() *Executive4WithUpdateTracker {
+	e.CurrentMood = nil
+	e.registerChange("person", "current_mood")
+	return e
+}
+
 func (e *Executive4WithUpdateTracker) SetAddedAt(val *time.Time) *Executive4WithUpdateTracker {
 	e.AddedAt = val
 	e.registerChange("person", "added_at")
 	return e
 }
+
+func (e *Executive4WithUpdateTracker) SetAddedAtNull() *Executive4WithUpdateTracker {
+	e.AddedAt = nil
+	e.registerChange("person", "added_at")
+	return e
+}
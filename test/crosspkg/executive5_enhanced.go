@@ -4,6 +4,8 @@ package crosspkg
 
 import (
 	"time"
+
+	"github.com/kelveny/gdbc/pkg/accessor"
 )
 
 type Executive5EntityFields struct {
@@ -30,6 +32,32 @@ func (e *Executive5) TableColumns() *Executive5TableColumns {
 	}
 }
 
+type Executive5ColumnCriteria struct {
+	Term        accessor.TableColumn
+	Title       accessor.TableColumn
+	Company     accessor.TableColumn
+	Id          accessor.TableColumn
+	FirstName   accessor.TableColumn
+	LastName    accessor.TableColumn
+	Email       accessor.TableColumn
+	Age         accessor.TableColumn
+	CurrentMood accessor.TableColumn
+	AddedAt     accessor.TableColumn
+}
+
+var Executive5Cols = Executive5ColumnCriteria{
+	Term:        accessor.NewTableColumn("executive", "term"),
+	Title:       accessor.NewTableColumn("manager", "title"),
+	Company:     accessor.NewTableColumn("employee", "company"),
+	Id:          accessor.NewTableColumn("person", "id"),
+	FirstName:   accessor.NewTableColumn("person", "first_name"),
+	LastName:    accessor.NewTableColumn("person", "last_name"),
+	Email:       accessor.NewTableColumn("person", "email"),
+	Age:         accessor.NewTableColumn("person", "age"),
+	CurrentMood: accessor.NewTableColumn("person", "current_mood"),
+	AddedAt:     accessor.NewTableColumn("person", "added_at"),
+}
+
 type Executive5WithUpdateTracker struct {
 	Executive5
 	trackMap map[string]map[string]bool
@@ -50,6 +78,10 @@ func (e *Executive5WithUpdateTracker) registerChange(tbl string, col string) {
 	}
 }
 
+func (e *Executive5WithUpdateTracker) MarkChanged(tbl string, col string) {
+	e.registerChange(tbl, col)
+}
+
 func (e *Executive5WithUpdateTracker) ColumnsChanged(tbl ...string) []string {
 	cols := []string{}
 
@@ -73,18 +105,36 @@ func (e *Executive5WithUpdateTracker) SetTerm(val *string) *Executive5WithUpdate
 	return e
 }
 
+func (e *Executive5WithUpdateTracker) SetTermNull() *Executive5WithUpdateTracker {
+	e.Term = nil
+	e.registerChange("executive", "term")
+	return e
+}
+
 func (e *Executive5WithUpdateTracker) SetTitle(val *string) *Executive5WithUpdateTracker {
 	e.Title = val
 	e.registerChange("manager", "title")
 	return e
 }
 
+func (e *Executive5WithUpdateTracker) SetTitleNull() *Executive5WithUpdateTracker {
+	e.Title = nil
+	e.registerChange("manager", "title")
+	return e
+}
+
 func (e *Executive5WithUpdateTracker) SetCompany(val *string) *Executive5WithUpdateTracker {
 	e.Company = val
 	e.registerChange("employee", "company")
 	return e
 }
 
+func (e *Executive5WithUpdateTracker) SetCompanyNull() *Executive5WithUpdateTracker {
+	e.Company = nil
+	e.registerChange("employee", "company")
+	return e
+}
+
 func (e *Executive5WithUpdateTracker) SetId(val int) *Executive5WithUpdateTracker {
 	e.Id = val
 	e.registerChange("person", "id")
@@ -109,20 +159,44 @@ func (e *Executive5WithUpdateTracker) SetEmail(val *string) *Executive5WithUpdat
 	return e
 }
 
+func (e *Executive5WithUpdateTracker) SetEmailNull() *Executive5WithUpdateTracker {
+	e.Email = nil
+	e.registerChange("person", "email")
+	return e
+}
+
 func (e *Executive5WithUpdateTracker) SetAge(val *int) *Executive5WithUpdateTracker {
 	e.Age = val
 	e.registerChange("person", "age")
 	return e
 }
 
+func (e *Executive5WithUpdateTracker) SetAgeNull() *Executive5WithUpdateTracker {
+	e.Age = nil
+	e.registerChange("person", "age")
+	return e
+}
+
 func (e *Executive5WithUpdateTracker) SetCurrentMood(val *string) *Executive5WithUpdateTracker {
 	e.CurrentMood = val
 	e.registerChange("person", "current_mood")
 	return e
 }
 
+func (e *Executive5WithUpdateTracker) SetCurrentMoodNull() *Executive5WithUpdateTracker {
+	e.CurrentMood = nil
+	e.registerChange("person", "current_mood")
+	return e
+}
+
 func (e *Executive5WithUpdateTracker) SetAddedAt(val *time.Time) *Executive5WithUpdateTracker {
 	e.AddedAt = val
 	e.registerChange("person", "added_at")
 	return e
 }
+
+func (e *Executive5WithUpdateTracker) SetAddedAtNull() *Executive5WithUpdateTracker {
+	e.AddedAt = nil
+	e.registerChange("person", "added_at")
+	return e
+}
@@ -4,6 +4,8 @@ package crosspkg
 
 import (
 	"time"
+
+	"github.com/kelveny/gdbc/pkg/accessor"
 )
 
 type Executive6EntityFields struct {
@@ -30,6 +32,32 @@ func (e *Executive6) TableColumns() *Executive6TableColumns {
 	}
 }
 
+type Executive6ColumnCriteria struct {
+	Term        accessor.TableColumn
+	Title       accessor.TableColumn
+	Company     accessor.TableColumn
+	Id          accessor.TableColumn
+	FirstName   accessor.TableColumn
+	LastName    accessor.TableColumn
+	Email       accessor.TableColumn
+	Age         accessor.TableColumn
+	CurrentMood accessor.TableColumn
+	AddedAt     accessor.TableColumn
+}
+
+var Executive6Cols = Executive6ColumnCriteria{
+	Term:        accessor.NewTableColumn("executive", "term"),
+	Title:       accessor.NewTableColumn("manager", "title"),
+	Company:     accessor.NewTableColumn("employee", "company"),
+	Id:          accessor.NewTableColumn("person", "id"),
+	FirstName:   accessor.NewTableColumn("person", "first_name"),
+	LastName:    accessor.NewTableColumn("person", "last_name"),
+	Email:       accessor.NewTableColumn("person", "email"),
+	Age:         accessor.NewTableColumn("person", "age"),
+	CurrentMood: accessor.NewTableColumn("person", "current_mood"),
+	AddedAt:     accessor.NewTableColumn("person", "added_at"),
+}
+
 type Executive6WithUpdateTracker struct {
 	Executive6
 	trackMap map[string]map[string]bool
@@ -50,6 +78,10 @@ func (e *Executive6WithUpdateTracker) registerChange(tbl string, col string) {
 	}
 }
 
+func (e *Executive6WithUpdateTracker) MarkChanged(tbl string, col string) {
+	e.registerChange(tbl, col)
+}
+
 func (e *Executive6WithUpdateTracker) ColumnsChanged(tbl ...string) []string {
 	cols := []string{}
 
@@ -73,18 +105,36 @@ func (e *Executive6WithUpdateTracker) SetTerm(val *string) *Executive6WithUpdate
 	return e
 }
 
+func (e *Executive6WithUpdateTracker) SetTermNull() *Executive6WithUpdateTracker {
+	e.Term = nil
+	e.registerChange("executive", "term")
+	return e
+}
+
 func (e *Executive6WithUpdateTracker) SetTitle(val *string) *Executive6WithUpdateTracker {
 	e.Title = val
 	e.registerChange("manager", "title")
 	return e
 }
 
+func (e *Executive6WithUpdateTracker) SetTitleNull() *Executive6WithUpdateTracker {
+	e.Title = nil
+	e.registerChange("manager", "title")
+	return e
+}
+
 func (e *Executive6WithUpdateTracker) SetCompany(val *string) *Executive6WithUpdateTracker {
 	e.Company = val
 	e.registerChange("employee", "company")
 	return e
 }
 
+func (e *Executive6WithUpdateTracker) SetCompanyNull() *Executive6WithUpdateTracker {
+	e.Company = nil
+	e.registerChange("employee", "company")
+	return e
+}
+
 func (e *Executive6WithUpdateTracker) SetId(val int) *Executive6WithUpdateTracker {
 	e.Id = val
 	e.registerChange("person", "id")
@@ -109,20 +159,44 @@ func (e *Executive6WithUpdateTracker) SetEmail(val *string) *Executive6WithUpdat
 	return e
 }
 
+func (e *Executive6WithUpdateTracker) SetEmailNull() *Executive6WithUpdateTracker {
+	e.Email = nil
+	e.registerChange("person", "email")
+	return e
+}
+
 func (e *Executive6WithUpdateTracker) SetAge(val *int) *Executive6WithUpdateTracker {
 	e.Age = val
 	e.registerChange("person", "age")
 	return e
 }
 
+func (e *Executive6WithUpdateTracker) SetAgeNull() *Executive6WithUpdateTracker {
+	e.Age = nil
+	e.registerChange("person", "age")
+	return e
+}
+
 func (e *Executive6WithUpdateTracker) SetCurrentMood(val *string) *Executive6WithUpdateTracker {
 	e.CurrentMood = val
 	e.registerChange("person", "current_mood")
 	return e
 }
 
+func (e *Executive6WithUpdateTracker) SetCurrentMoodNull() *Executive6WithUpdateTracker {
+	e.CurrentMood = nil
+	e.registerChange("person", "current_mood")
+	return e
+}
+
 func (e *Executive6WithUpdateTracker) SetAddedAt(val *time.Time) *Executive6WithUpdateTracker {
 	e.AddedAt = val
 	e.registerChange("person", "added_at")
 	return e
 }
+
+func (e *Executive6WithUpdateTracker) SetAddedAtNull() *Executive6WithUpdateTracker {
+	e.AddedAt = nil
+	e.registerChange("person", "added_at")
+	return e
+}
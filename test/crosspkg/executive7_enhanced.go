@@ -4,6 +4,8 @@ package crosspkg
 
 import (
 	"time"
+
+	"github.com/kelveny/gdbc/pkg/accessor"
 )
 
 type Executive7EntityFields struct {
@@ -30,6 +32,32 @@ func (e *Executive7) TableColumns() *Executive7TableColumns {
 	}
 }
 
+type Executive7ColumnCriteria struct {
+	Term        accessor.TableColumn
+	Title       accessor.TableColumn
+	Company     accessor.TableColumn
+	Id          accessor.TableColumn
+	FirstName   accessor.TableColumn
+	LastName    accessor.TableColumn
+	Email       accessor.TableColumn
+	Age         accessor.TableColumn
+	CurrentMood accessor.TableColumn
+	AddedAt     accessor.TableColumn
+}
+
+var Executive7Cols = Executive7ColumnCriteria{
+	Term:        accessor.NewTableColumn("executive", "term"),
+	Title:       accessor.NewTableColumn("manager", "title"),
+	Company:     accessor.NewTableColumn("employee", "company"),
+	Id:          accessor.NewTableColumn("person", "id"),
+	FirstName:   accessor.NewTableColumn("person", "first_name"),
+	LastName:    accessor.NewTableColumn("person", "last_name"),
+	Email:       accessor.NewTableColumn("person", "email"),
+	Age:         accessor.NewTableColumn("person", "age"),
+	CurrentMood: accessor.NewTableColumn("person", "current_mood"),
+	AddedAt:     accessor.NewTableColumn("person", "added_at"),
+}
+
 type Executive7WithUpdateTracker struct {
 	Executive7
 	trackMap map[string]map[string]bool
@@ -50,6 +78,10 @@ func (e *Executive7WithUpdateTracker) registerChange(tbl string, col string) {
 	}
 }
 
+func (e *Executive7WithUpdateTracker) MarkChanged(tbl string, col string) {
+	e.registerChange(tbl, col)
+}
+
 func (e *Executive7WithUpdateTracker) ColumnsChanged(tbl ...string) []string {
 	cols := []string{}
 
@@ -73,18 +105,36 @@ func (e *Executive7WithUpdateTracker) SetTerm(val *string) *Executive7WithUpdate
 	return e
 }
 
+func (e *Executive7WithUpdateTracker) SetTermNull() *Executive7WithUpdateTracker {
+	e.Term = nil
+	e.registerChange("executive", "term")
+	return e
+}
+
 func (e *Executive7WithUpdateTracker) SetTitle(val *string) *Executive7WithUpdateTracker {
 	e.Title = val
 	e.registerChange("manager", "title")
 	return e
 }
 
+func (e *Executive7WithUpdateTracker) SetTitleNull() *Executive7WithUpdateTracker {
+	e.Title = nil
+	e.registerChange("manager", "title")
+	return e
+}
+
 func (e *Executive7WithUpdateTracker) SetCompany(val *string) *Executive7WithUpdateTracker {
 	e.Company = val
 	e.registerChange("employee", "company")
 	return e
 }
 
+func (e *Executive7WithUpdateTracker) SetCompanyNull() *Executive7WithUpdateTracker {
+	e.Company = nil
+	e.registerChange("employee", "company")
+	return e
+}
+
 func (e *Executive7WithUpdateTracker) SetId(val int) *Executive7WithUpdateTracker {
 	e.Id = val
 	e.registerChange("person", "id")
@@ -109,20 +159,44 @@ func (e *Executive7WithUpdateTracker) SetEmail(val *string) *Executive7WithUpdat
 	return e
 }
 
+func (e *Executive7WithUpdateTracker) SetEmailNull() *Executive7WithUpdateTracker {
+	e.Email = nil
+	e.registerChange("person", "email")
+	return e
+}
+
 func (e *Executive7WithUpdateTracker) SetAge(val *int) *Executive7WithUpdateTracker {
 	e.Age = val
 	e.registerChange("person", "age")
 	return e
 }
 
+func (e *Executive7WithUpdateTracker) SetAgeNull() *Executive7WithUpdateTracker {
+	e.Age = nil
+	e.registerChange("person", "age")
+	return e
+}
+
 func (e *Executive7WithUpdateTracker) SetCurrentMood(val *string) *Executive7WithUpdateTracker {
 	e.CurrentMood = val
 	e.registerChange("person", "current_mood")
 	return e
 }
 
+func (e *Executive7WithUpdateTracker) SetCurrentMoodNull() *Executive7WithUpdateTracker {
+	e.CurrentMood = nil
+	e.registerChange("person", "current_mood")
+	return e
+}
+
 func (e *Executive7WithUpdateTracker) SetAddedAt(val *time.Time) *Executive7WithUpdateTracker {
 	e.AddedAt = val
 	e.registerChange("person", "added_at")
 	return e
 }
+
+func (e *Executive7WithUpdateTracker) SetAddedAtNull() *Executive7WithUpdateTracker {
+	e.AddedAt = nil
+	e.registerChange("person", "added_at")
+	return e
+}
@@ -4,6 +4,8 @@ package crosspkg
 
 import (
 	"time"
+
+	"github.com/kelveny/gdbc/pkg/accessor"
 )
 
 type Executive8EntityFields struct {
@@ -30,6 +32,32 @@ func (e *Executive8) TableColumns() *Executive8TableColumns {
 	}
 }
 
+type Executive8ColumnCriteria struct {
+	Term        accessor.TableColumn
+	Title       accessor.TableColumn
+	Company     accessor.TableColumn
+	Id          accessor.TableColumn
+	FirstName   accessor.TableColumn
+	LastName    accessor.TableColumn
+	Email       accessor.TableColumn
+	Age         accessor.TableColumn
+	CurrentMood accessor.TableColumn
+	AddedAt     accessor.TableColumn
+}
+
+var Executive8Cols = Executive8ColumnCriteria{
+	Term:        accessor.NewTableColumn("executive", "term"),
+	Title:       accessor.NewTableColumn("manager", "title"),
+	Company:     accessor.NewTableColumn("employee", "company"),
+	Id:          accessor.NewTableColumn("person", "id"),
+	FirstName:   accessor.NewTableColumn("person", "first_name"),
+	LastName:    accessor.NewTableColumn("person", "last_name"),
+	Email:       accessor.NewTableColumn("person", "email"),
+	Age:         accessor.NewTableColumn("person", "age"),
+	CurrentMood: accessor.NewTableColumn("person", "current_mood"),
+	AddedAt:     accessor.NewTableColumn("person", "added_at"),
+}
+
 type Executive8WithUpdateTracker struct {
 	Executive8
 	trackMap map[string]map[string]bool
@@ -50,6 +78,10 @@ func (e *Executive8WithUpdateTracker) registerChange(tbl string, col string) {
 	}
 }
 
+func (e *Executive8WithUpdateTracker) MarkChanged(tbl string, col string) {
+	e.registerChange(tbl, col)
+}
+
 func (e *Executive8WithUpdateTracker) ColumnsChanged(tbl ...string) []string {
 	cols := []string{}
 
@@ -73,18 +105,36 @@ func (e *Executive8WithUpdateTracker) SetTerm(val *string) *Executive8WithUpdate
 	return e
 }
 
+func (e *Executive8WithUpdateTracker) SetTermNull() *Executive8WithUpdateTracker {
+	e.Term = nil
+	e.registerChange("executive", "term")
+	return e
+}
+
 func (e *Executive8WithUpdateTracker) SetTitle(val *string) *Executive8WithUpdateTracker {
 	e.Title = val
 	e.registerChange("manager", "title")
 	return e
 }
 
+func (e *Executive8WithUpdateTracker) SetTitleNull() *Executive8WithUpdateTracker {
+	e.Title = nil
+	e.registerChange("manager", "title")
+	return e
+}
+
 func (e *Executive8WithUpdateTracker) SetCompany(val *string) *Executive8WithUpdateTracker {
 	e.Company = val
 	e.registerChange("employee", "company")
 	return e
 }
 
+func (e *Executive8WithUpdateTracker) SetCompanyNull() *Executive8WithUpdateTracker {
+	e.Company = nil
+	e.registerChange("employee", "company")
+	return e
+}
+
 func (e *Executive8WithUpdateTracker) SetId(val int) *Executive8WithUpdateTracker {
 	e.Id = val
 	e.registerChange("person", "id")
@@ -109,20 +159,44 @@ func (e *Executive8WithUpdateTracker) SetEmail(val *string) *Executive8WithUpdat
 	return e
 }
 
+func (e *Executive8WithUpdateTracker) SetEmailNull() *Executive8WithUpdateTracker {
+	e.Email = nil
+	e.registerChange("person", "email")
+	return e
+}
+
 func (e *Executive8WithUpdateTracker) SetAge(val *int) *Executive8WithUpdateTracker {
 	e.Age = val
 	e.registerChange("person", "age")
 	return e
 }
 
+func (e *Executive8WithUpdateTracker) SetAgeNull() *Executive8WithUpdateTracker {
+	e.Age = nil
+	e.registerChange("person", "age")
+	return e
+}
+
 func (e *Executive8WithUpdateTracker) SetCurrentMood(val *string) *Executive8WithUpdateTracker {
 	e.CurrentMood = val
 	e.registerChange("person", "current_mood")
 	return e
 }
 
+func (e *Executive8WithUpdateTracker) SetCurrentMoodNull() *Executive8WithUpdateTracker {
+	e.CurrentMood = nil
+	e.registerChange("person", "current_mood")
+	return e
+}
+
 func (e *Executive8WithUpdateTracker) SetAddedAt(val *time.Time) *Executive8WithUpdateTracker {
 	e.AddedAt = val
 	e.registerChange("person", "added_at")
 	return e
 }
+
+func (e *Executive8WithUpdateTracker) SetAddedAtNull() *Executive8WithUpdateTracker {
+	e.AddedAt = nil
+	e.registerChange("person", "added_at")
+	return e
+}
@@ -4,6 +4,8 @@ package crosspkg
 
 import (
 	"time"
+
+	"github.com/kelveny/gdbc/pkg/accessor"
 )
 
 type ExecutiveEntityFields struct {
@@ -30,6 +32,32 @@ func (e *Executive) TableColumns() *ExecutiveTableColumns {
 	}
 }
 
+type ExecutiveColumnCriteria struct {
+	Term        accessor.TableColumn
+	Title       accessor.TableColumn
+	Company     accessor.TableColumn
+	Id          accessor.TableColumn
+	FirstName   accessor.TableColumn
+	LastName    accessor.TableColumn
+	Email       accessor.TableColumn
+	Age         accessor.TableColumn
+	CurrentMood accessor.TableColumn
+	AddedAt     accessor.TableColumn
+}
+
+var ExecutiveCols = ExecutiveColumnCriteria{
+	Term:        accessor.NewTableColumn("executive", "term"),
+	Title:       accessor.NewTableColumn("manager", "title"),
+	Company:     accessor.NewTableColumn("employee", "company"),
+	Id:          accessor.NewTableColumn("person", "id"),
+	FirstName:   accessor.NewTableColumn("person", "first_name"),
+	LastName:    accessor.NewTableColumn("person", "last_name"),
+	Email:       accessor.NewTableColumn("person", "email"),
+	Age:         accessor.NewTableColumn("person", "age"),
+	CurrentMood: accessor.NewTableColumn("person", "current_mood"),
+	AddedAt:     accessor.NewTableColumn("person", "added_at"),
+}
+
 type ExecutiveWithUpdateTracker struct {
 	Executive
 	trackMap map[string]map[string]bool
@@ -50,6 +78,10 @@ func (e *ExecutiveWithUpdateTracker) registerChange(tbl string, col string) {
 	}
 }
 
+func (e *ExecutiveWithUpdateTracker) MarkChanged(tbl string, col string) {
+	e.registerChange(tbl, col)
+}
+
 func (e *ExecutiveWithUpdateTracker) ColumnsChanged(tbl ...string) []string {
 	cols := []string{}
 
@@ -73,18 +105,36 @@ func (e *ExecutiveWithUpdateTracker) SetTerm(val *string) *ExecutiveWithUpdateTr
 	return e
 }
 
+func (e *ExecutiveWithUpdateTracker) SetTermNull() *ExecutiveWithUpdateTracker {
+	e.Term = nil
+	e.registerChange("executive", "term")
+	return e
+}
+
 func (e *ExecutiveWithUpdateTracker) SetTitle(val *string) *ExecutiveWithUpdateTracker {
 	e.Title = val
 	e.registerChange("manager", "title")
 	return e
 }
 
+func (e *ExecutiveWithUpdateTracker) SetTitleNull() *ExecutiveWithUpdateTracker {
+	e.Title = nil
+	e.registerChange("manager", "title")
+	return e
+}
+
 func (e *ExecutiveWithUpdateTracker) SetCompany(val *string) *ExecutiveWithUpdateTracker {
 	e.Company = val
 	e.registerChange("employee", "company")
 	return e
 }
 
+func (e *ExecutiveWithUpdateTracker) SetCompanyNull() *ExecutiveWithUpdateTracker {
+	e.Company = nil
+	e.registerChange("employee", "company")
+	return e
+}
+
 func (e *ExecutiveWithUpdateTracker) SetId(val int) *ExecutiveWithUpdateTracker {
 	e.Id = val
 	e.registerChange("person", "id")
@@ -109,20 +159,44 @@ func (e *ExecutiveWithUpdateTracker) SetEmail(val *string) *ExecutiveWithUpdateT
 	return e
 }
 
+func (e *ExecutiveWithUpdateTracker) SetEmailNull() *ExecutiveWithUpdateTracker {
+	e.Email = nil
+	e.registerChange("person", "email")
+	return e
+}
+
 func (e *ExecutiveWithUpdateTracker) SetAge(val *int) *ExecutiveWithUpdateTracker {
 	e.Age = val
 	e.registerChange("person", "age")
 	return e
 }
 
+func (e *ExecutiveWithUpdateTracker) SetAgeNull() *ExecutiveWithUpdateTracker {
+	e.Age = nil
+	e.registerChange("person", "age")
+	return e
+}
+
 func (e *ExecutiveWithUpdateTracker) SetCurrentMood(val *string) *ExecutiveWithUpdateTracker {
 	e.CurrentMood = val
 	e.registerChange("person", "current_mood")
 	return e
 }
 
+func (e *ExecutiveWithUpdateTracker) SetCurrentMoodNull() *ExecutiveWithUpdateTracker {
+	e.CurrentMood = nil
+	e.registerChange("person", "current_mood")
+	return e
+}
+
 func (e *ExecutiveWithUpdateTracker) SetAddedAt(val *time.Time) *ExecutiveWithUpdateTracker {
 	e.AddedAt = val
 	e.registerChange("person", "added_at")
 	return e
 }
+
+func (e *ExecutiveWithUpdateTracker) SetAddedAtNull() *ExecutiveWithUpdateTracker {
+	e.AddedAt = nil
+	e.registerChange("person", "added_at")
+	return e
+}
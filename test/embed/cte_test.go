@@ -0,0 +1,102 @@
+package embed
+
+import (
+	"context"
+
+	"github.com/kelveny/gdbc/pkg/accessor"
+	"github.com/stretchr/testify/require"
+)
+
+type Vehicle struct {
+	Id    int    `db:"id"`
+	Make  string `db:"make"`
+	Badge string `db:"badge,readonly"`
+}
+
+// key columns of truck and fire_truck are named after the tables they refer to
+type Truck struct {
+	Vehicle `db:",table=vehicle,join=vehicle_id:id"`
+	Payload int `db:"payload"`
+}
+
+type FireTruck struct {
+	Truck  `db:",table=truck,join=truck_id:vehicle_id"`
+	Ladder int `db:"ladder"`
+}
+
+func (s *EmbeddedEntityTestSuite) setupVehicleTables() {
+	_ = s.Db.MustExec(`
+DROP TABLE IF EXISTS fire_truck;
+DROP TABLE IF EXISTS truck;
+DROP TABLE IF EXISTS vehicle;
+
+CREATE TABLE vehicle (
+	id serial primary key,
+	make text,
+	badge text GENERATED ALWAYS AS (upper(make)) STORED
+);
+
+CREATE TABLE truck (
+	vehicle_id integer primary key REFERENCES vehicle(id),
+	payload integer
+);
+
+CREATE TABLE fire_truck (
+	truck_id integer primary key REFERENCES truck(vehicle_id),
+	ladder integer
+);
+	`)
+}
+
+func (s *EmbeddedEntityTestSuite) teardownVehicleTables() {
+	_ = s.Db.MustExec(`
+DROP TABLE IF EXISTS fire_truck;
+DROP TABLE IF EXISTS truck;
+DROP TABLE IF EXISTS vehicle;
+	`)
+}
+
+func (s *EmbeddedEntityTestSuite) TestCreateWithCte() {
+	req := require.New(s.T())
+
+	s.setupVehicleTables()
+	defer s.teardownVehicleTables()
+
+	a := accessor.New(s.Db)
+	ctx := context.Background()
+
+	ft := FireTruck{Ladder: 30}
+	ft.Make = "pierce"
+	ft.Payload = 2000
+	req.NoError(a.Create(ctx, &ft, "fire_truck"))
+
+	// ID is backfilled and the joined row is scanned back
+	req.NotZero(ft.Id)
+	req.Equal("PIERCE", ft.Badge)
+	req.Equal("pierce", ft.Make)
+	req.Equal(2000, ft.Payload)
+	req.Equal(30, ft.Ladder)
+
+	// ID is propagated to key columns of derived tables
+	var payload, ladder int
+	req.NoError(s.Db.Get(&payload, "SELECT payload FROM truck WHERE vehicle_id = $1", ft.Id))
+	req.Equal(2000, payload)
+	req.NoError(s.Db.Get(&ladder, "SELECT ladder FROM fire_truck WHERE truck_id = $1", ft.Id))
+	req.Equal(30, ladder)
+
+	// entities created one after another get IDs of their own
+	other := FireTruck{Ladder: 20}
+	other.Make = "rosenbauer"
+	req.NoError(a.Create(ctx, &other, "fire_truck"))
+	req.NotZero(other.Id)
+	req.NotEqual(ft.Id, other.Id)
+
+	r := FireTruck{}
+	r.Id = ft.Id
+	req.NoError(a.Read(ctx, &r, "fire_truck"))
+	req.Equal(ft, r)
+
+	var count int
+	req.NoError(s.Db.Get(&count, "SELECT count(*) FROM fire_truck"))
+	req.Equal(2, count)
+}
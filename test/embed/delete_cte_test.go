@@ -0,0 +1,72 @@
+package embed
+
+import (
+	"context"
+
+	"github.com/kelveny/gdbc/pkg/accessor"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *EmbeddedEntityTestSuite) TestDeleteWithCte() {
+	req := require.New(s.T())
+
+	s.setupVehicleTables()
+	defer s.teardownVehicleTables()
+
+	a := accessor.New(s.Db)
+	ctx := context.Background()
+
+	ft := FireTruck{Ladder: 30}
+	ft.Make = "pierce"
+	req.NoError(a.Create(ctx, &ft, "fire_truck"))
+
+	other := FireTruck{Ladder: 20}
+	other.Make = "rosenbauer"
+	req.NoError(a.Create(ctx, &other, "fire_truck"))
+
+	// foreign keys without ON DELETE CASCADE are checked at the end of the statement
+	result, err := a.Delete(accessor.WithDeleteStrategy(ctx, accessor.DeleteWithCte), &FireTruck{
+		Truck: Truck{Vehicle: Vehicle{Id: ft.Id}},
+	}, "fire_truck")
+	req.NoError(err)
+
+	composite, ok := result.(accessor.CompositeResult)
+	req.True(ok)
+	req.Equal("vehicle", composite.RootTable)
+	req.Equal(map[string]int64{"vehicle": 1, "truck": 1, "fire_truck": 1}, composite.TableRowsAffected)
+
+	affected, err := result.RowsAffected()
+	req.NoError(err)
+	req.Equal(int64(1), affected)
+
+	// rows are deleted by key columns of each table, other entities are left alone
+	for _, q := range []string{
+		"SELECT count(*) FROM vehicle WHERE id = $1",
+		"SELECT count(*) FROM truck WHERE vehicle_id = $1",
+		"SELECT count(*) FROM fire_truck WHERE truck_id = $1",
+	} {
+		var count int
+		req.NoError(s.Db.Get(&count, q, ft.Id))
+		req.Equal(0, count)
+
+		req.NoError(s.Db.Get(&count, q, other.Id))
+		req.Equal(1, count)
+	}
+
+	// nothing is left to delete
+	result, err = a.Delete(accessor.WithDeleteStrategy(ctx, accessor.DeleteWithCte), &ft, "fire_truck")
+	req.NoError(err)
+	req.Equal(map[string]int64{"vehicle": 0, "truck": 0, "fire_truck": 0}, result.(accessor.CompositeResult).TableRowsAffected)
+
+	// strategy registered for the entity type
+	accessor.RegisterDeleteStrategy(accessor.DeleteWithCte, FireTruck{})
+	defer accessor.UnregisterDeleteStrategy(FireTruck{})
+
+	result, err = a.Delete(ctx, &other, "fire_truck")
+	req.NoError(err)
+	req.Equal(map[string]int64{"vehicle": 1, "truck": 1, "fire_truck": 1}, result.(accessor.CompositeResult).TableRowsAffected)
+
+	var count int
+	req.NoError(s.Db.Get(&count, "SELECT count(*) FROM vehicle"))
+	req.Equal(0, count)
+}
@@ -377,9 +377,10 @@ func (s *EmbeddedEntityTestSuite) TestEmbedded() {
 		&mgrList2,
 		m.TableName(),
 		func(builder squirrel.SelectBuilder) accessor.Sqlizer {
-			return builder.Where(squirrel.Eq{
-				m.Employee.TableName() + "." + m.Employee.EntityFields().Company: "bar.com",
-			})
+			return builder.
+				Where(ManagerCols.Company.Eq("bar.com")).
+				Where(ManagerCols.Title.IsNotNull()).
+				OrderBy(ManagerCols.Id.Asc())
 		},
 	)
 	req.NoError(err)
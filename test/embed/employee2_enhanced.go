@@ -4,6 +4,8 @@ package embed
 
 import (
 	"time"
+
+	"github.com/kelveny/gdbc/pkg/accessor"
 )
 
 type Employee2EntityFields struct {
@@ -30,6 +32,28 @@ func (e *Employee2) TableColumns() *Employee2TableColumns {
 	}
 }
 
+type Employee2ColumnCriteria struct {
+	Company     accessor.TableColumn
+	Id          accessor.TableColumn
+	FirstName   accessor.TableColumn
+	LastName    accessor.TableColumn
+	Email       accessor.TableColumn
+	Age         accessor.TableColumn
+	CurrentMood accessor.TableColumn
+	AddedAt     accessor.TableColumn
+}
+
+var Employee2Cols = Employee2ColumnCriteria{
+	Company:     accessor.NewTableColumn("employee", "company"),
+	Id:          accessor.NewTableColumn("person", "id"),
+	FirstName:   accessor.NewTableColumn("person", "first_name"),
+	LastName:    accessor.NewTableColumn("person", "last_name"),
+	Email:       accessor.NewTableColumn("person", "email"),
+	Age:         accessor.NewTableColumn("person", "age"),
+	CurrentMood: accessor.NewTableColumn("person", "current_mood"),
+	AddedAt:     accessor.NewTableColumn("person", "added_at"),
+}
+
 type Employee2WithUpdateTracker struct {
 	Employee2
 	trackMap map[string]map[string]bool
@@ -50,6 +74,10 @@ func (e *Employee2WithUpdateTracker) registerChange(tbl string, col string) {
 	}
 }
 
+func (e *Employee2WithUpdateTracker) MarkChanged(tbl string, col string) {
+	e.registerChange(tbl, col)
+}
+
 func (e *Employee2WithUpdateTracker) ColumnsChanged(tbl ...string) []string {
 	cols := []string{}
 
@@ -73,6 +101,12 @@ func (e *Employee2WithUpdateTracker) SetCompany(val *string) *Employee2WithUpdat
 	return e
 }
 
+func (e *Employee2WithUpdateTracker) SetCompanyNull() *Employee2WithUpdateTracker {
+	e.Company = nil
+	e.registerChange("employee", "company")
+	return e
+}
+
 func (e *Employee2WithUpdateTracker) SetId(val int) *Employee2WithUpdateTracker {
 	e.Id = val
 	e.registerChange("person", "id")
@@ -97,20 +131,44 @@ func (e *Employee2WithUpdateTracker) SetEmail(val *string) *Employee2WithUpdateT
 	return e
 }
 
+func (e *Employee2WithUpdateTracker) SetEmailNull() *Employee2WithUpdateTracker {
+	e.Email = nil
+	e.registerChange("person", "email")
+	return e
+}
+
 func (e *Employee2WithUpdateTracker) SetAge(val *int) *Employee2WithUpdateTracker {
 	e.Age = val
 	e.registerChange("person", "age")
 	return e
 }
 
+func (e *Employee2WithUpdateTracker) SetAgeNull() *Employee2WithUpdateTracker {
+	e.Age = nil
+	e.registerChange("person", "age")
+	return e
+}
+
 func (e *Employee2WithUpdateTracker) SetCurrentMood(val *string) *Employee2WithUpdateTracker {
 	e.CurrentMood = val
 	e.registerChange("person", "current_mood")
 	return e
 }
 
+func (e *Employee2WithUpdateTracker) SetCurrentMoodNull() *Employee2WithUpdateTracker {
+	e.CurrentMood = nil
+	e.registerChange("person", "current_mood")
+	return e
+}
+
 func (e *Employee2WithUpdateTracker) SetAddedAt(val *time.Time) *Employee2WithUpdateTracker {
 	e.AddedAt = val
 	e.registerChange("person", "added_at")
 	return e
 }
+
+func (e *Employee2WithUpdateTracker) SetAddedAtNull() *Employee2WithUpdateTracker {
+	e.AddedAt = nil
+	e.registerChange("person", "added_at")
+	return e
+}
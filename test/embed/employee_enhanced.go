@@ -4,6 +4,8 @@ package embed
 
 import (
 	"time"
+
+	"github.com/kelveny/gdbc/pkg/accessor"
 )
 
 type EmployeeEntityFields struct {
@@ -30,6 +32,28 @@ func (e *Employee) TableColumns() *EmployeeTableColumns {
 	}
 }
 
+type EmployeeColumnCriteria struct {
+	Company     accessor.TableColumn
+	Id          accessor.TableColumn
+	FirstName   accessor.TableColumn
+	LastName    accessor.TableColumn
+	Email       accessor.TableColumn
+	Age         accessor.TableColumn
+	CurrentMood accessor.TableColumn
+	AddedAt     accessor.TableColumn
+}
+
+var EmployeeCols = EmployeeColumnCriteria{
+	Company:     accessor.NewTableColumn("employee", "company"),
+	Id:          accessor.NewTableColumn("person", "id"),
+	FirstName:   accessor.NewTableColumn("person", "first_name"),
+	LastName:    accessor.NewTableColumn("person", "last_name"),
+	Email:       accessor.NewTableColumn("person", "email"),
+	Age:         accessor.NewTableColumn("person", "age"),
+	CurrentMood: accessor.NewTableColumn("person", "current_mood"),
+	AddedAt:     accessor.NewTableColumn("person", "added_at"),
+}
+
 type EmployeeWithUpdateTracker struct {
 	Employee
 	trackMap map[string]map[string]bool
@@ -50,6 +74,10 @@ func (e *EmployeeWithUpdateTracker) registerChange(tbl string, col string) {
 	}
 }
 
+func (e *EmployeeWithUpdateTracker) MarkChanged(tbl string, col string) {
+	e.registerChange(tbl, col)
+}
+
 func (e *EmployeeWithUpdateTracker) ColumnsChanged(tbl ...string) []string {
 	cols := []string{}
 
@@ -73,6 +101,12 @@ func (e *EmployeeWithUpdateTracker) SetCompany(val *string) *EmployeeWithUpdateT
 	return e
 }
 
+func (e *EmployeeWithUpdateTracker) SetCompanyNull() *EmployeeWithUpdateTracker {
+	e.Company = nil
+	e.registerChange("employee", "company")
+	return e
+}
+
 func (e *EmployeeWithUpdateTracker) SetId(val int) *EmployeeWithUpdateTracker {
 	e.Id = val
 	e.registerChange("person", "id")
@@ -97,20 +131,44 @@ func (e *EmployeeWithUpdateTracker) SetEmail(val *string) *EmployeeWithUpdateTra
 	return e
 }
 
+func (e *EmployeeWithUpdateTracker) SetEmailNull() *EmployeeWithUpdateTracker {
+	e.Email = nil
+	e.registerChange("person", "email")
+	return e
+}
+
 func (e *EmployeeWithUpdateTracker) SetAge(val *int) *EmployeeWithUpdateTracker {
 	e.Age = val
 	e.registerChange("person", "age")
 	return e
 }
 
+func (e *EmployeeWithUpdateTracker) SetAgeNull() *EmployeeWithUpdateTracker {
+	e.Age = nil
+	e.registerChange("person", "age")
+	return e
+}
+
 func (e *EmployeeWithUpdateTracker) SetCurrentMood(val *string) *EmployeeWithUpdateTracker {
 	e.CurrentMood = val
 	e.registerChange("person", "current_mood")
 	return e
 }
 
+func (e *EmployeeWithUpdateTracker) SetCurrentMoodNull() *EmployeeWithUpdateTracker {
+	e.CurrentMood = nil
+	e.registerChange("person", "current_mood")
+	return e
+}
+
 func (e *EmployeeWithUpdateTracker) SetAddedAt(val *time.Time) *EmployeeWithUpdateTracker {
 	e.AddedAt = val
 	e.registerChange("person", "added_at")
 	return e
 }
+
+func (e *EmployeeWithUpdateTracker) SetAddedAtNull() *EmployeeWithUpdateTracker {
+	e.AddedAt = nil
+	e.registerChange("person", "added_at")
+	return e
+}
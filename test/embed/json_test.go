@@ -0,0 +1,66 @@
+package embed
+
+import (
+	"context"
+
+	"github.com/kelveny/gdbc/pkg/accessor"
+	"github.com/kelveny/gdbc/test/enhancer"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *EmbeddedEntityTestSuite) TestJsonPatch() {
+	req := require.New(s.T())
+
+	// profile and roles are json, settings is jsonb
+	_ = s.Db.MustExec(`
+DROP TABLE IF EXISTS account;
+
+CREATE TABLE account (
+	id serial primary key,
+	name text,
+	profile json,
+	settings jsonb,
+	roles json
+);
+	`)
+	defer func() {
+		_ = s.Db.MustExec(`DROP TABLE IF EXISTS account;`)
+	}()
+
+	a := accessor.New(s.Db)
+	ctx := context.Background()
+
+	acct := enhancer.Account{
+		Name: "foo",
+		Profile: enhancer.Profile{
+			Nickname: "f",
+			Address:  enhancer.Address{Street: "1 Main St", City: "San Jose"},
+		},
+		Roles: []string{"admin", "dev"},
+	}
+	req.NoError(a.Create(ctx, &acct, acct.TableName()))
+
+	u := enhancer.AccountWithUpdateTracker{}
+	u.Id = acct.Id
+	u.PatchProfile([]string{"address", "city"}, "San Francisco").
+		PatchSettings([]string{"lang"}, "en").
+		PatchRoles([]string{"1"}, "ops")
+
+	result, err := a.Update(ctx, &u, u.TableName())
+	req.NoError(err)
+	affected, err := result.RowsAffected()
+	req.NoError(err)
+	req.Equal(int64(1), affected)
+
+	r := enhancer.Account{Id: acct.Id}
+	req.NoError(a.Read(ctx, &r, r.TableName()))
+	req.Equal("f", r.Profile.Nickname)
+	req.Equal("1 Main St", r.Profile.Address.Street)
+	req.Equal("San Francisco", r.Profile.Address.City)
+	req.Equal(map[string]string{"lang": "en"}, r.Settings)
+	req.Equal([]string{"admin", "ops"}, r.Roles)
+
+	var kind string
+	req.NoError(s.Db.Get(&kind, "SELECT pg_typeof(profile)::text FROM account WHERE id = $1", acct.Id))
+	req.Equal("json", kind)
+}
@@ -4,6 +4,8 @@ package embed
 
 import (
 	"time"
+
+	"github.com/kelveny/gdbc/pkg/accessor"
 )
 
 type Manager2EntityFields struct {
@@ -30,6 +32,30 @@ func (e *Manager2) TableColumns() *Manager2TableColumns {
 	}
 }
 
+type Manager2ColumnCriteria struct {
+	Title       accessor.TableColumn
+	Company     accessor.TableColumn
+	Id          accessor.TableColumn
+	FirstName   accessor.TableColumn
+	LastName    accessor.TableColumn
+	Email       accessor.TableColumn
+	Age         accessor.TableColumn
+	CurrentMood accessor.TableColumn
+	AddedAt     accessor.TableColumn
+}
+
+var Manager2Cols = Manager2ColumnCriteria{
+	Title:       accessor.NewTableColumn("manager", "title"),
+	Company:     accessor.NewTableColumn("employee", "company"),
+	Id:          accessor.NewTableColumn("person", "id"),
+	FirstName:   accessor.NewTableColumn("person", "first_name"),
+	LastName:    accessor.NewTableColumn("person", "last_name"),
+	Email:       accessor.NewTableColumn("person", "email"),
+	Age:         accessor.NewTableColumn("person", "age"),
+	CurrentMood: accessor.NewTableColumn("person", "current_mood"),
+	AddedAt:     accessor.NewTableColumn("person", "added_at"),
+}
+
 type Manager2WithUpdateTracker struct {
 	Manager2
 	trackMap map[string]map[string]bool
@@ -50,6 +76,10 @@ func (e *Manager2WithUpdateTracker) registerChange(tbl string, col string) {
 	}
 }
 
+func (e *Manager2WithUpdateTracker) MarkChanged(tbl string, col string) {
+	e.registerChange(tbl, col)
+}
+
 func (e *Manager2WithUpdateTracker) ColumnsChanged(tbl ...string) []string {
 	cols := []string{}
 
@@ -73,12 +103,24 @@ func (e *Manager2WithUpdateTracker) SetTitle(val *string) *Manager2WithUpdateTra
 	return e
 }
 
+func (e *Manager2WithUpdateTracker) SetTitleNull() *Manager2WithUpdateTracker {
+	e.Title = nil
+	e.registerChange("manager", "title")
+	return e
+}
+
 func (e *Manager2WithUpdateTracker) SetCompany(val *string) *Manager2WithUpdateTracker {
 	e.Company = val
 	e.registerChange("employee", "company")
 	return e
 }
 
+func (e *Manager2WithUpdateTracker) SetCompanyNull() *Manager2WithUpdateTracker {
+	e.Company = nil
+	e.registerChange("employee", "company")
+	return e
+}
+
 func (e *Manager2WithUpdateTracker) SetId(val int) *Manager2WithUpdateTracker {
 	e.Id = val
 	e.registerChange("person", "id")
@@ -103,20 +145,44 @@ func (e *Manager2WithUpdateTracker) SetEmail(val *string) *Manager2WithUpdateTra
 	return e
 }
 
+func (e *Manager2WithUpdateTracker) SetEmailNull() *Manager2WithUpdateTracker {
+	e.Email = nil
+	e.registerChange("person", "email")
+	return e
+}
+
 func (e *Manager2WithUpdateTracker) SetAge(val *int) *Manager2WithUpdateTracker {
 	e.Age = val
 	e.registerChange("person", "age")
 	return e
 }
 
+func (e *Manager2WithUpdateTracker) SetAgeNull() *Manager2WithUpdateTracker {
+	e.Age = nil
+	e.registerChange("person", "age")
+	return e
+}
+
 func (e *Manager2WithUpdateTracker) SetCurrentMood(val *string) *Manager2WithUpdateTracker {
 	e.CurrentMood = val
 	e.registerChange("person", "current_mood")
 	return e
 }
 
+func (e *Manager2WithUpdateTracker) SetCurrentMoodNull() *Manager2WithUpdateTracker {
+	e.CurrentMood = nil
+	e.registerChange("person", "current_mood")
+	return e
+}
+
 func (e *Manager2WithUpdateTracker) SetAddedAt(val *time.Time) *Manager2WithUpdateTracker {
 	e.AddedAt = val
 	e.registerChange("person", "added_at")
 	return e
 }
+
+func (e *Manager2WithUpdateTracker) SetAddedAtNull() *Manager2WithUpdateTracker {
+	e.AddedAt = nil
+	e.registerChange("person", "added_at")
+	return e
+}
@@ -4,6 +4,8 @@ package embed
 
 import (
 	"time"
+
+	"github.com/kelveny/gdbc/pkg/accessor"
 )
 
 type Manager3EntityFields struct {
@@ -30,6 +32,30 @@ func (e *Manager3) TableColumns() *Manager3TableColumns {
 	}
 }
 
+type Manager3ColumnCriteria struct {
+	Title       accessor.TableColumn
+	Company     accessor.TableColumn
+	Id          accessor.TableColumn
+	FirstName   accessor.TableColumn
+	LastName    accessor.TableColumn
+	Email       accessor.TableColumn
+	Age         accessor.TableColumn
+	CurrentMood accessor.TableColumn
+	AddedAt     accessor.TableColumn
+}
+
+var Manager3Cols = Manager3ColumnCriteria{
+	Title:       accessor.NewTableColumn("manager", "title"),
+	Company:     accessor.NewTableColumn("employee", "company"),
+	Id:          accessor.NewTableColumn("person", "id"),
+	FirstName:   accessor.NewTableColumn("person", "first_name"),
+	LastName:    accessor.NewTableColumn("person", "last_name"),
+	Email:       accessor.NewTableColumn("person", "email"),
+	Age:         accessor.NewTableColumn("person", "age"),
+	CurrentMood: accessor.NewTableColumn("person", "current_mood"),
+	AddedAt:     accessor.NewTableColumn("person", "added_at"),
+}
+
 type Manager3WithUpdateTracker struct {
 	Manager3
 	trackMap map[string]map[string]bool
@@ -50,6 +76,10 @@ func (e *Manager3WithUpdateTracker) registerChange(tbl string, col string) {
 	}
 }
 
+func (e *Manager3WithUpdateTracker) MarkChanged(tbl string, col string) {
+	e.registerChange(tbl, col)
+}
+
 func (e *Manager3WithUpdateTracker) ColumnsChanged(tbl ...string) []string {
 	cols := []string{}
 
@@ -73,12 +103,24 @@ func (e *Manager3WithUpdateTracker) SetTitle(val *string) *Manager3WithUpdateTra
 	return e
 }
 
+func (e *Manager3WithUpdateTracker) SetTitleNull() *Manager3WithUpdateTracker {
+	e.Title = nil
+	e.registerChange("manager", "title")
+	return e
+}
+
 func (e *Manager3WithUpdateTracker) SetCompany(val *string) *Manager3WithUpdateTracker {
 	e.Company = val
 	e.registerChange("employee", "company")
 	return e
 }
 
+func (e *Manager3WithUpdateTracker) SetCompanyNull() *Manager3WithUpdateTracker {
+	e.Company = nil
+	e.registerChange("employee", "company")
+	return e
+}
+
 func (e *Manager3WithUpdateTracker) SetId(val int) *Manager3WithUpdateTracker {
 	e.Id = val
 	e.registerChange("person", "id")
@@ -103,20 +145,44 @@ func (e *Manager3WithUpdateTracker) SetEmail(val *string) *Manager3WithUpdateTra
 	return e
 }
 
+func (e *Manager3WithUpdateTracker) SetEmailNull() *Manager3WithUpdateTracker {
+	e.Email = nil
+	e.registerChange("person", "email")
+	return e
+}
+
 func (e *Manager3WithUpdateTracker) SetAge(val *int) *Manager3WithUpdateTracker {
 	e.Age = val
 	e.registerChange("person", "age")
 	return e
 }
 
+func (e *Manager3WithUpdateTracker) SetAgeNull() *Manager3WithUpdateTracker {
+	e.Age = nil
+	e.registerChange("person", "age")
+	return e
+}
+
 func (e *Manager3WithUpdateTracker) SetCurrentMood(val *string) *Manager3WithUpdateTracker {
 	e.CurrentMood = val
 	e.registerChange("person", "current_mood")
 	return e
 }
 
+func (e *Manager3WithUpdateTracker) SetCurrentMoodNull() *Manager3WithUpdateTracker {
+	e.CurrentMood = nil
+	e.registerChange("person", "current_mood")
+	return e
+}
+
 func (e *Manager3WithUpdateTracker) SetAddedAt(val *time.Time) *Manager3WithUpdateTracker {
 	e.AddedAt = val
 	e.registerChange("person", "added_at")
 	return e
 }
+
+func (e *Manager3WithUpdateTracker) SetAddedAtNull() *Manager3WithUpdateTracker {
+	e.AddedAt = nil
+	e.registerChange("person", "added_at")
+	return e
+}
@@ -4,6 +4,8 @@ package embed
 
 import (
 	"time"
+
+	"github.com/kelveny/gdbc/pkg/accessor"
 )
 
 type Manager4EntityFields struct {
@@ -30,6 +32,30 @@ func (e *Manager4) TableColumns() *Manager4TableColumns {
 	}
 }
 
+type Manager4ColumnCriteria struct {
+	Title       accessor.TableColumn
+	Company     accessor.TableColumn
+	Id          accessor.TableColumn
+	FirstName   accessor.TableColumn
+	LastName    accessor.TableColumn
+	Email       accessor.TableColumn
+	Age         accessor.TableColumn
+	CurrentMood accessor.TableColumn
+	AddedAt     accessor.TableColumn
+}
+
+var Manager4Cols = Manager4ColumnCriteria{
+	Title:       accessor.NewTableColumn("manager", "title"),
+	Company:     accessor.NewTableColumn("employee", "company"),
+	Id:          accessor.NewTableColumn("person", "id"),
+	FirstName:   accessor.NewTableColumn("person", "first_name"),
+	LastName:    accessor.NewTableColumn("person", "last_name"),
+	Email:       accessor.NewTableColumn("person", "email"),
+	Age:         accessor.NewTableColumn("person", "age"),
+	CurrentMood: accessor.NewTableColumn("person", "current_mood"),
+	AddedAt:     accessor.NewTableColumn("person", "added_at"),
+}
+
 type Manager4WithUpdateTracker struct {
 	Manager4
 	trackMap map[string]map[string]bool
@@ -50,6 +76,10 @@ func (e *Manager4WithUpdateTracker) registerChange(tbl string, col string) {
 	}
 }
 
+func (e *Manager4WithUpdateTracker) MarkChanged(tbl string, col string) {
+	e.registerChange(tbl, col)
+}
+
 func (e *Manager4WithUpdateTracker) ColumnsChanged(tbl ...string) []string {
 	cols := []string{}
 
@@ -73,12 +103,24 @@ func (e *Manager4WithUpdateTracker) SetTitle(val *string) *Manager4WithUpdateTra
 	return e
 }
 
+func (e *Manager4WithUpdateTracker) SetTitleNull() *Manager4WithUpdateTracker {
+	e.Title = nil
+	e.registerChange("manager", "title")
+	return e
+}
+
 func (e *Manager4WithUpdateTracker) SetCompany(val *string) *Manager4WithUpdateTracker {
 	e.Company = val
 	e.registerChange("employee", "company")
 	return e
 }
 
+func (e *Manager4WithUpdateTracker) SetCompanyNull() *Manager4WithUpdateTracker {
+	e.Company = nil
+	e.registerChange("employee", "company")
+	return e
+}
+
 func (e *Manager4WithUpdateTracker) SetId(val int) *Manager4WithUpdateTracker {
 	e.Id = val
 	e.registerChange("person", "id")
@@ -103,20 +145,44 @@ func (e *Manager4WithUpdateTracker) SetEmail(val *string) *Manager4WithUpdateTra
 	return e
 }
 
+func (e *Manager4WithUpdateTracker) SetEmailNull() *Manager4WithUpdateTracker {
+	e.Email = nil
+	e.registerChange("person", "email")
+	return e
+}
+
 func (e *Manager4WithUpdateTracker) SetAge(val *int) *Manager4WithUpdateTracker {
 	e.Age = val
 	e.registerChange("person", "age")
 	return e
 }
 
+func (e *Manager4WithUpdateTracker) SetAgeNull() *Manager4WithUpdateTracker {
+	e.Age = nil
+	e.registerChange("person", "age")
+	return e
+}
+
 func (e *Manager4WithUpdateTracker) SetCurrentMood(val *string) *Manager4WithUpdateTracker {
 	e.CurrentMood = val
 	e.registerChange("person", "current_mood")
 	return e
 }
 
+func (e *Manager4WithUpdateTracker) SetCurrentMoodNull() *Manager4WithUpdateTracker {
+	e.CurrentMood = nil
+	e.registerChange("person", "current_mood")
+	return e
+}
+
 func (e *Manager4WithUpdateTracker) SetAddedAt(val *time.Time) *Manager4WithUpdateTracker {
 	e.AddedAt = val
 	e.registerChange("person", "added_at")
 	return e
 }
+
+func (e *Manager4WithUpdateTracker) SetAddedAtNull() *Manager4WithUpdateTracker {
+	e.AddedAt = nil
+	e.registerChange("person", "added_at")
+	return e
+}
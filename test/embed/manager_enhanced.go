@@ -4,6 +4,8 @@ package embed
 
 import (
 	"time"
+
+	"github.com/kelveny/gdbc/pkg/accessor"
 )
 
 type ManagerEntityFields struct {
@@ -30,6 +32,30 @@ func (e *Manager) TableColumns() *ManagerTableColumns {
 	}
 }
 
+type ManagerColumnCriteria struct {
+	Title       accessor.TableColumn
+	Company     accessor.TableColumn
+	Id          accessor.TableColumn
+	FirstName   accessor.TableColumn
+	LastName    accessor.TableColumn
+	Email       accessor.TableColumn
+	Age         accessor.TableColumn
+	CurrentMood accessor.TableColumn
+	AddedAt     accessor.TableColumn
+}
+
+var ManagerCols = ManagerColumnCriteria{
+	Title:       accessor.NewTableColumn("manager", "title"),
+	Company:     accessor.NewTableColumn("employee", "company"),
+	Id:          accessor.NewTableColumn("person", "id"),
+	FirstName:   accessor.NewTableColumn("person", "first_name"),
+	LastName:    accessor.NewTableColumn("person", "last_name"),
+	Email:       accessor.NewTableColumn("person", "email"),
+	Age:         accessor.NewTableColumn("person", "age"),
+	CurrentMood: accessor.NewTableColumn("person", "current_mood"),
+	AddedAt:     accessor.NewTableColumn("person", "added_at"),
+}
+
 type ManagerWithUpdateTracker struct {
 	Manager
 	trackMap map[string]map[string]bool
@@ -50,6 +76,10 @@ func (e *ManagerWithUpdateTracker) registerChange(tbl string, col string) {
 	}
 }
 
+func (e *ManagerWithUpdateTracker) MarkChanged(tbl string, col string) {
+	e.registerChange(tbl, col)
+}
+
 func (e *ManagerWithUpdateTracker) ColumnsChanged(tbl ...string) []string {
 	cols := []string{}
 
@@ -73,12 +103,24 @@ func (e *ManagerWithUpdateTracker) SetTitle(val *string) *ManagerWithUpdateTrack
 	return e
 }
 
+func (e *ManagerWithUpdateTracker) SetTitleNull() *ManagerWithUpdateTracker {
+	e.Title = nil
+	e.registerChange("manager", "title")
+	return e
+}
+
 func (e *ManagerWithUpdateTracker) SetCompany(val *string) *ManagerWithUpdateTracker {
 	e.Company = val
 	e.registerChange("employee", "company")
 	return e
 }
 
+func (e *ManagerWithUpdateTracker) SetCompanyNull() *ManagerWithUpdateTracker {
+	e.Company = nil
+	e.registerChange("employee", "company")
+	return e
+}
+
 func (e *ManagerWithUpdateTracker) SetId(val int) *ManagerWithUpdateTracker {
 	e.Id = val
 	e.registerChange("person", "id")
@@ -103,20 +145,44 @@ func (e *ManagerWithUpdateTracker) SetEmail(val *string) *ManagerWithUpdateTrack
 	return e
 }
 
+func (e *ManagerWithUpdateTracker) SetEmailNull() *ManagerWithUpdateTracker {
+	e.Email = nil
+	e.registerChange("person", "email")
+	return e
+}
+
 func (e *ManagerWithUpdateTracker) SetAge(val *int) *ManagerWithUpdateTracker {
 	e.Age = val
 	e.registerChange("person", "age")
 	return e
 }
 
+func (e *ManagerWithUpdateTracker) SetAgeNull() *ManagerWithUpdateTracker {
+	e.Age = nil
+	e.registerChange("person", "age")
+	return e
+}
+
 func (e *ManagerWithUpdateTracker) SetCurrentMood(val *string) *ManagerWithUpdateTracker {
 	e.CurrentMood = val
 	e.registerChange("person", "current_mood")
 	return e
 }
 
+func (e *ManagerWithUpdateTracker) SetCurrentMoodNull() *ManagerWithUpdateTracker {
+	e.CurrentMood = nil
+	e.registerChange("person", "current_mood")
+	return e
+}
+
 func (e *ManagerWithUpdateTracker) SetAddedAt(val *time.Time) *ManagerWithUpdateTracker {
 	e.AddedAt = val
 	e.registerChange("person", "added_at")
 	return e
 }
+
+func (e *ManagerWithUpdateTracker) SetAddedAtNull() *ManagerWithUpdateTracker {
+	e.AddedAt = nil
+	e.registerChange("person", "added_at")
+	return e
+}
@@ -4,6 +4,8 @@ package embed
 
 import (
 	"time"
+
+	"github.com/kelveny/gdbc/pkg/accessor"
 )
 
 type PersonEntityFields struct {
@@ -54,6 +56,26 @@ func (e *Person) TableColumns() *PersonTableColumns {
 	}
 }
 
+type PersonColumnCriteria struct {
+	Id          accessor.TableColumn
+	FirstName   accessor.TableColumn
+	LastName    accessor.TableColumn
+	Email       accessor.TableColumn
+	Age         accessor.TableColumn
+	CurrentMood accessor.TableColumn
+	AddedAt     accessor.TableColumn
+}
+
+var PersonCols = PersonColumnCriteria{
+	Id:          accessor.NewTableColumn("person", "id"),
+	FirstName:   accessor.NewTableColumn("person", "first_name"),
+	LastName:    accessor.NewTableColumn("person", "last_name"),
+	Email:       accessor.NewTableColumn("person", "email"),
+	Age:         accessor.NewTableColumn("person", "age"),
+	CurrentMood: accessor.NewTableColumn("person", "current_mood"),
+	AddedAt:     accessor.NewTableColumn("person", "added_at"),
+}
+
 type PersonWithUpdateTracker struct {
 	Person
 	trackMap map[string]map[string]bool
@@ -74,6 +96,10 @@ func (e *PersonWithUpdateTracker) registerChange(tbl string, col string) {
 	}
 }
 
+func (e *PersonWithUpdateTracker) MarkChanged(tbl string, col string) {
+	e.registerChange(tbl, col)
+}
+
 func (e *PersonWithUpdateTracker) ColumnsChanged(tbl ...string) []string {
 	cols := []string{}
 
@@ -115,20 +141,44 @@ func (e *PersonWithUpdateTracker) SetEmail(val *string) *PersonWithUpdateTracker
 	return e
 }
 
+func (e *PersonWithUpdateTracker) SetEmailNull() *PersonWithUpdateTracker {
+	e.Email = nil
+	e.registerChange("person", "email")
+	return e
+}
+
 func (e *PersonWithUpdateTracker) SetAge(val *int) *PersonWithUpdateTracker {
 	e.Age = val
 	e.registerChange("person", "age")
 	return e
 }
 
+func (e *PersonWithUpdateTracker) SetAgeNull() *PersonWithUpdateTracker {
+	e.Age = nil
+	e.registerChange("person", "age")
+	return e
+}
+
 func (e *PersonWithUpdateTracker) SetCurrentMood(val *string) *PersonWithUpdateTracker {
 	e.CurrentMood = val
 	e.registerChange("person", "current_mood")
 	return e
 }
 
+func (e *PersonWithUpdateTracker) SetCurrentMoodNull() *PersonWithUpdateTracker {
+	e.CurrentMood = nil
+	e.registerChange("person", "current_mood")
+	return e
+}
+
 func (e *PersonWithUpdateTracker) SetAddedAt(val *time.Time) *PersonWithUpdateTracker {
 	e.AddedAt = val
 	e.registerChange("person", "added_at")
 	return e
 }
+
+func (e *PersonWithUpdateTracker) SetAddedAtNull() *PersonWithUpdateTracker {
+	e.AddedAt = nil
+	e.registerChange("person", "added_at")
+	return e
+}
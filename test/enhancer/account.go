@@ -0,0 +1,21 @@
+package enhancer
+
+type Address struct {
+	Street string `json:"street"`
+	City   string `json:"city"`
+}
+
+type Profile struct {
+	Nickname string   `json:"nickname"`
+	Address  Address  `json:"address"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+//go:generate gdbc -entity Account -table account
+type Account struct {
+	Id       int               `db:"id"`
+	Name     string            `db:"name"`
+	Profile  Profile           `db:"profile,json"`
+	Settings map[string]string `db:"settings,json"`
+	Roles    []string          `db:"roles,json"`
+}
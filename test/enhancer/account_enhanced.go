@@ -0,0 +1,181 @@
+// CODE GENERATED AUTOMATICALLY WITH github.com/kelveny/gdbc entity enhancer
+// THIS FILE SHOULD NOT BE EDITED BY HAND
+package enhancer
+
+import (
+	"github.com/kelveny/gdbc/pkg/accessor"
+)
+
+type AccountEntityFields struct {
+	Id       string
+	Name     string
+	Profile  string
+	Settings string
+	Roles    string
+}
+
+type AccountTableColumns struct {
+	Id       string
+	Name     string
+	Profile  string
+	Settings string
+	Roles    string
+}
+
+func (e *Account) TableName() string {
+	return "account"
+}
+
+func (e *Account) EntityFields() *AccountEntityFields {
+	return &AccountEntityFields{
+		Id:       "Id",
+		Name:     "Name",
+		Profile:  "Profile",
+		Settings: "Settings",
+		Roles:    "Roles",
+	}
+}
+
+func (e *Account) TableColumns() *AccountTableColumns {
+	return &AccountTableColumns{
+		Id:       "id",
+		Name:     "name",
+		Profile:  "profile",
+		Settings: "settings",
+		Roles:    "roles",
+	}
+}
+
+type AccountColumnCriteria struct {
+	Id       accessor.TableColumn
+	Name     accessor.TableColumn
+	Profile  accessor.TableColumn
+	Settings accessor.TableColumn
+	Roles    accessor.TableColumn
+}
+
+var AccountCols = AccountColumnCriteria{
+	Id:       accessor.NewTableColumn("account", "id"),
+	Name:     accessor.NewTableColumn("account", "name"),
+	Profile:  accessor.NewTableColumn("account", "profile"),
+	Settings: accessor.NewTableColumn("account", "settings"),
+	Roles:    accessor.NewTableColumn("account", "roles"),
+}
+
+type AccountWithUpdateTracker struct {
+	Account
+	trackMap map[string]map[string]bool
+	patchMap map[string][]accessor.JsonPatch
+}
+
+func (e *AccountWithUpdateTracker) registerChange(tbl string, col string) {
+	if e.trackMap == nil {
+		e.trackMap = make(map[string]map[string]bool)
+	}
+
+	if m, ok := e.trackMap[tbl]; ok {
+		m[col] = true
+	} else {
+		m = make(map[string]bool)
+		e.trackMap[tbl] = m
+
+		m[col] = true
+	}
+}
+
+func (e *AccountWithUpdateTracker) MarkChanged(tbl string, col string) {
+	e.registerChange(tbl, col)
+}
+
+func (e *AccountWithUpdateTracker) ColumnsChanged(tbl ...string) []string {
+	cols := []string{}
+
+	if tbl == nil {
+		tbl = []string{"account"}
+	}
+
+	if e.trackMap != nil {
+		m := e.trackMap[tbl[0]]
+		for col := range m {
+			cols = append(cols, col)
+		}
+	}
+
+	return cols
+}
+
+func (e *AccountWithUpdateTracker) registerPatch(tbl string, col string, patch accessor.JsonPatch) {
+	if e.patchMap == nil {
+		e.patchMap = make(map[string][]accessor.JsonPatch)
+	}
+
+	key := tbl + "." + col
+	e.patchMap[key] = append(e.patchMap[key], patch)
+	e.registerChange(tbl, col)
+}
+
+func (e *AccountWithUpdateTracker) JsonPatches(tbl string, col string) []accessor.JsonPatch {
+	return e.patchMap[tbl+"."+col]
+}
+
+func (e *AccountWithUpdateTracker) SetId(val int) *AccountWithUpdateTracker {
+	e.Id = val
+	e.registerChange("account", "id")
+	return e
+}
+
+func (e *AccountWithUpdateTracker) SetName(val string) *AccountWithUpdateTracker {
+	e.Name = val
+	e.registerChange("account", "name")
+	return e
+}
+
+func (e *AccountWithUpdateTracker) SetProfile(val Profile) *AccountWithUpdateTracker {
+	e.Profile = val
+	delete(e.patchMap, "account.profile")
+	e.registerChange("account", "profile")
+	return e
+}
+
+func (e *AccountWithUpdateTracker) PatchProfile(path []string, val any) *AccountWithUpdateTracker {
+	e.registerPatch("account", "profile", accessor.JsonPatch{Path: path, Value: val})
+	return e
+}
+
+func (e *AccountWithUpdateTracker) SetSettings(val map[string]string) *AccountWithUpdateTracker {
+	e.Settings = val
+	delete(e.patchMap, "account.settings")
+	e.registerChange("account", "settings")
+	return e
+}
+
+func (e *AccountWithUpdateTracker) SetSettingsNull() *AccountWithUpdateTracker {
+	e.Settings = nil
+	delete(e.patchMap, "account.settings")
+	e.registerChange("account", "settings")
+	return e
+}
+
+func (e *AccountWithUpdateTracker) PatchSettings(path []string, val any) *AccountWithUpdateTracker {
+	e.registerPatch("account", "settings", accessor.JsonPatch{Path: path, Value: val})
+	return e
+}
+
+func (e *AccountWithUpdateTracker) SetRoles(val []string) *AccountWithUpdateTracker {
+	e.Roles = val
+	delete(e.patchMap, "account.roles")
+	e.registerChange("account", "roles")
+	return e
+}
+
+func (e *AccountWithUpdateTracker) SetRolesNull() *AccountWithUpdateTracker {
+	e.Roles = nil
+	delete(e.patchMap, "account.roles")
+	e.registerChange("account", "roles")
+	return e
+}
+
+func (e *AccountWithUpdateTracker) PatchRoles(path []string, val any) *AccountWithUpdateTracker {
+	e.registerPatch("account", "roles", accessor.JsonPatch{Path: path, Value: val})
+	return e
+}
@@ -0,0 +1,118 @@
+package enhancer
+
+import (
+	"context"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/kelveny/gdbc/pkg/accessor"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *TestSuite) setupAccountTable() {
+	_ = s.Db.MustExec(`
+CREATE TABLE IF NOT EXISTS account (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name text,
+	profile text,
+	settings text,
+	roles text
+);
+	`)
+}
+
+func (s *TestSuite) teardownAccountTable() {
+	_ = s.Db.MustExec(`
+DROP TABLE IF EXISTS account;
+	`)
+}
+
+func (s *TestSuite) TestJsonColumns() {
+	assert := require.New(s.T())
+
+	s.setupAccountTable()
+	defer s.teardownAccountTable()
+
+	a := accessor.New(s.Db)
+	ctx := context.Background()
+
+	acct := Account{
+		Name: "foo",
+		Profile: Profile{
+			Nickname: "f",
+			Address:  Address{Street: "1 Main St", City: "San Jose"},
+			Tags:     []string{"vip"},
+		},
+		Settings: map[string]string{"theme": "dark"},
+		Roles:    []string{"admin", "dev"},
+	}
+	assert.NoError(a.Create(ctx, &acct, acct.TableName()))
+	assert.True(acct.Id > 0)
+
+	var raw string
+	assert.NoError(s.Db.Get(&raw, "SELECT profile FROM account WHERE id=?", acct.Id))
+	assert.JSONEq(`{"nickname":"f","address":{"street":"1 Main St","city":"San Jose"},"tags":["vip"]}`, raw)
+
+	other := Account{Name: "bar"}
+	assert.NoError(a.Create(ctx, &other, other.TableName()))
+
+	// nil maps and slices are stored as NULL
+	var cnt int
+	assert.NoError(s.Db.Get(&cnt, "SELECT count(*) FROM account WHERE settings IS NULL AND roles IS NULL"))
+	assert.Equal(1, cnt)
+
+	r := Account{Id: acct.Id}
+	assert.NoError(a.Read(ctx, &r, r.TableName()))
+	assert.Equal(acct, r)
+
+	var list []*Account
+	assert.NoError(a.EntitySelect(ctx, &list, "account", func(builder squirrel.SelectBuilder) accessor.Sqlizer {
+		return builder.OrderBy(AccountCols.Id.Asc())
+	}))
+	assert.Equal(2, len(list))
+	assert.Equal(acct, *list[0])
+	assert.Equal("bar", list[1].Name)
+	assert.Nil(list[1].Settings)
+	assert.Nil(list[1].Roles)
+
+	var accounts []Account
+	assert.NoError(a.Select(ctx, &accounts, "SELECT * FROM account WHERE name=?", "foo"))
+	assert.Equal(1, len(accounts))
+	assert.Equal(acct, accounts[0])
+
+	// partial updates keep the rest of the documents intact
+	u := AccountWithUpdateTracker{}
+	u.Id = acct.Id
+	u.PatchProfile([]string{"address", "city"}, "San Francisco").
+		PatchSettings([]string{"lang"}, "en").
+		PatchRoles([]string{"1"}, "ops")
+
+	result, err := a.Update(ctx, &u, u.TableName())
+	assert.NoError(err)
+	affected, err := result.RowsAffected()
+	assert.NoError(err)
+	assert.True(affected == 1)
+
+	r = Account{Id: acct.Id}
+	assert.NoError(a.Read(ctx, &r, r.TableName()))
+	assert.Equal("foo", r.Name)
+	assert.Equal("f", r.Profile.Nickname)
+	assert.Equal("1 Main St", r.Profile.Address.Street)
+	assert.Equal("San Francisco", r.Profile.Address.City)
+	assert.Equal([]string{"vip"}, r.Profile.Tags)
+	assert.Equal(map[string]string{"theme": "dark", "lang": "en"}, r.Settings)
+	assert.Equal([]string{"admin", "ops"}, r.Roles)
+
+	// setting a json field replaces the whole document and drops pending patches
+	u = AccountWithUpdateTracker{}
+	u.Id = acct.Id
+	u.PatchSettings([]string{"lang"}, "fr").
+		SetSettings(map[string]string{"tz": "UTC"})
+
+	_, err = a.Update(ctx, &u, u.TableName())
+	assert.NoError(err)
+
+	r = Account{Id: acct.Id}
+	assert.NoError(a.Read(ctx, &r, r.TableName()))
+	assert.Equal(map[string]string{"tz": "UTC"}, r.Settings)
+	assert.Equal("San Francisco", r.Profile.Address.City)
+}
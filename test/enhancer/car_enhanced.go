@@ -0,0 +1,118 @@
+// CODE GENERATED AUTOMATICALLY WITH github.com/kelveny/gdbc entity enhancer
+// THIS FILE SHOULD NOT BE EDITED BY HAND
+package enhancer
+
+import (
+	"github.com/kelveny/gdbc/pkg/accessor"
+)
+
+type CarEntityFields struct {
+	Seats string
+	Id    string
+	Make  string
+}
+
+type CarTableColumns struct {
+	Seats string
+	Id    string
+	Make  string
+}
+
+func (e *Car) TableName() string {
+	return "vehicle"
+}
+
+func (e *Car) DiscriminatorColumn() string {
+	return "kind"
+}
+
+func (e *Car) DiscriminatorValue() string {
+	return "car"
+}
+
+func (e *Car) EntityFields() *CarEntityFields {
+	return &CarEntityFields{
+		Seats: "Seats",
+		Id:    "Id",
+		Make:  "Make",
+	}
+}
+
+func (e *Car) TableColumns() *CarTableColumns {
+	return &CarTableColumns{
+		Seats: "seats",
+		Id:    "id",
+		Make:  "make",
+	}
+}
+
+type CarColumnCriteria struct {
+	Seats accessor.TableColumn
+	Id    accessor.TableColumn
+	Make  accessor.TableColumn
+}
+
+var CarCols = CarColumnCriteria{
+	Seats: accessor.NewTableColumn("vehicle", "seats"),
+	Id:    accessor.NewTableColumn("vehicle", "id"),
+	Make:  accessor.NewTableColumn("vehicle", "make"),
+}
+
+type CarWithUpdateTracker struct {
+	Car
+	trackMap map[string]map[string]bool
+}
+
+func (e *CarWithUpdateTracker) registerChange(tbl string, col string) {
+	if e.trackMap == nil {
+		e.trackMap = make(map[string]map[string]bool)
+	}
+
+	if m, ok := e.trackMap[tbl]; ok {
+		m[col] = true
+	} else {
+		m = make(map[string]bool)
+		e.trackMap[tbl] = m
+
+		m[col] = true
+	}
+}
+
+func (e *CarWithUpdateTracker) MarkChanged(tbl string, col string) {
+	e.registerChange(tbl, col)
+}
+
+func (e *CarWithUpdateTracker) ColumnsChanged(tbl ...string) []string {
+	cols := []string{}
+
+	if tbl == nil {
+		tbl = []string{"vehicle"}
+	}
+
+	if e.trackMap != nil {
+		m := e.trackMap[tbl[0]]
+		for col := range m {
+			cols = append(cols, col)
+		}
+	}
+
+	return cols
+}
+
+func (e *CarWithUpdateTracker) SetSeats(val int) *CarWithUpdateTracker {
+	e.Seats = val
+	e.registerChange("vehicle", "seats")
+	return e
+}
+
+func (e *CarWithUpdateTracker) SetId(val int) *CarWithUpdateTracker {
+	e.Id = val
+	e.registerChange("vehicle", "id")
+	return e
+}
+
+func (e *CarWithUpdateTracker) SetMake(val string) *CarWithUpdateTracker {
+	e.Make = val
+	e.registerChange("vehicle", "make")
+	return e
+}
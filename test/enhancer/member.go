@@ -0,0 +1,23 @@
+package enhancer
+
+import (
+	"time"
+
+	"github.com/kelveny/gdbc/pkg/accessor"
+)
+
+type Mood string
+
+const (
+	MoodHappy Mood = "happy"
+	MoodSad   Mood = "sad"
+)
+
+//go:generate gdbc -entity Member -table member
+type Member struct {
+	Id       int                      `db:"id"`
+	Name     string                   `db:"name"`
+	Age      accessor.Null[int]       `db:"age"`
+	Mood     accessor.Null[Mood]      `db:"mood"`
+	JoinedAt accessor.Null[time.Time] `db:"joined_at"`
+}
@@ -0,0 +1,154 @@
+// CODE GENERATED AUTOMATICALLY WITH github.com/kelveny/gdbc entity enhancer
+// THIS FILE SHOULD NOT BE EDITED BY HAND
+package enhancer
+
+import (
+	"time"
+
+	"github.com/kelveny/gdbc/pkg/accessor"
+)
+
+type MemberEntityFields struct {
+	Id       string
+	Name     string
+	Age      string
+	Mood     string
+	JoinedAt string
+}
+
+type MemberTableColumns struct {
+	Id       string
+	Name     string
+	Age      string
+	Mood     string
+	JoinedAt string
+}
+
+func (e *Member) TableName() string {
+	return "member"
+}
+
+func (e *Member) EntityFields() *MemberEntityFields {
+	return &MemberEntityFields{
+		Id:       "Id",
+		Name:     "Name",
+		Age:      "Age",
+		Mood:     "Mood",
+		JoinedAt: "JoinedAt",
+	}
+}
+
+func (e *Member) TableColumns() *MemberTableColumns {
+	return &MemberTableColumns{
+		Id:       "id",
+		Name:     "name",
+		Age:      "age",
+		Mood:     "mood",
+		JoinedAt: "joined_at",
+	}
+}
+
+type MemberColumnCriteria struct {
+	Id       accessor.TableColumn
+	Name     accessor.TableColumn
+	Age      accessor.TableColumn
+	Mood     accessor.TableColumn
+	JoinedAt accessor.TableColumn
+}
+
+var MemberCols = MemberColumnCriteria{
+	Id:       accessor.NewTableColumn("member", "id"),
+	Name:     accessor.NewTableColumn("member", "name"),
+	Age:      accessor.NewTableColumn("member", "age"),
+	Mood:     accessor.NewTableColumn("member", "mood"),
+	JoinedAt: accessor.NewTableColumn("member", "joined_at"),
+}
+
+type MemberWithUpdateTracker struct {
+	Member
+	trackMap map[string]map[string]bool
+}
+
+func (e *MemberWithUpdateTracker) registerChange(tbl string, col string) {
+	if e.trackMap == nil {
+		e.trackMap = make(map[string]map[string]bool)
+	}
+
+	if m, ok := e.trackMap[tbl]; ok {
+		m[col] = true
+	} else {
+		m = make(map[string]bool)
+		e.trackMap[tbl] = m
+
+		m[col] = true
+	}
+}
+
+func (e *MemberWithUpdateTracker) MarkChanged(tbl string, col string) {
+	e.registerChange(tbl, col)
+}
+
+func (e *MemberWithUpdateTracker) ColumnsChanged(tbl ...string) []string {
+	cols := []string{}
+
+	if tbl == nil {
+		tbl = []string{"member"}
+	}
+
+	if e.trackMap != nil {
+		m := e.trackMap[tbl[0]]
+		for col := range m {
+			cols = append(cols, col)
+		}
+	}
+
+	return cols
+}
+
+func (e *MemberWithUpdateTracker) SetId(val int) *MemberWithUpdateTracker {
+	e.Id = val
+	e.registerChange("member", "id")
+	return e
+}
+
+func (e *MemberWithUpdateTracker) SetName(val string) *MemberWithUpdateTracker {
+	e.Name = val
+	e.registerChange("member", "name")
+	return e
+}
+
+func (e *MemberWithUpdateTracker) SetAge(val int) *MemberWithUpdateTracker {
+	e.Age = accessor.NewNull(val)
+	e.registerChange("member", "age")
+	return e
+}
+
+func (e *MemberWithUpdateTracker) SetAgeNull() *MemberWithUpdateTracker {
+	e.Age = accessor.Null[int]{}
+	e.registerChange("member", "age")
+	return e
+}
+
+func (e *MemberWithUpdateTracker) SetMood(val Mood) *MemberWithUpdateTracker {
+	e.Mood = accessor.NewNull(val)
+	e.registerChange("member", "mood")
+	return e
+}
+
+func (e *MemberWithUpdateTracker) SetMoodNull() *MemberWithUpdateTracker {
+	e.Mood = accessor.Null[Mood]{}
+	e.registerChange("member", "mood")
+	return e
+}
+
+func (e *MemberWithUpdateTracker) SetJoinedAt(val time.Time) *MemberWithUpdateTracker {
+	e.JoinedAt = accessor.NewNull(val)
+	e.registerChange("member", "joined_at")
+	return e
+}
+
+func (e *MemberWithUpdateTracker) SetJoinedAtNull() *MemberWithUpdateTracker {
+	e.JoinedAt = accessor.Null[time.Time]{}
+	e.registerChange("member", "joined_at")
+	return e
+}
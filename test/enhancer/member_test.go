@@ -0,0 +1,132 @@
+package enhancer
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/kelveny/gdbc/pkg/accessor"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *TestSuite) TestNullFields() {
+	assert := require.New(s.T())
+
+	_ = s.Db.MustExec(`
+CREATE TABLE IF NOT EXISTS member (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name text,
+	age integer,
+	mood text,
+	joined_at timestamp
+);
+	`)
+	defer func() {
+		_ = s.Db.MustExec(`DROP TABLE IF EXISTS member;`)
+	}()
+
+	a := accessor.New(s.Db)
+	ctx := context.Background()
+
+	m := Member{Name: "foo"}
+	assert.NoError(a.Create(ctx, &m, m.TableName()))
+
+	var cnt int
+	assert.NoError(s.Db.Get(&cnt, "SELECT count(*) FROM member WHERE age IS NULL AND mood IS NULL AND joined_at IS NULL"))
+	assert.Equal(1, cnt)
+
+	r := Member{Id: m.Id}
+	assert.NoError(a.Read(ctx, &r, r.TableName()))
+	assert.False(r.Age.Valid)
+	assert.False(r.Mood.Valid)
+	assert.False(r.JoinedAt.Valid)
+
+	joinedAt := time.Date(2023, 10, 1, 8, 30, 0, 0, time.UTC)
+
+	u := MemberWithUpdateTracker{}
+	u.Id = m.Id
+	u.SetAge(0).SetMood(MoodHappy).SetJoinedAt(joinedAt)
+
+	_, err := a.Update(ctx, &u, u.TableName())
+	assert.NoError(err)
+
+	r = Member{Id: m.Id}
+	assert.NoError(a.Read(ctx, &r, r.TableName()))
+	assert.Equal(accessor.NewNull(0), r.Age)
+	assert.Equal(accessor.NewNull(MoodHappy), r.Mood)
+	assert.True(r.JoinedAt.Valid)
+	assert.True(joinedAt.Equal(r.JoinedAt.V))
+
+	// NULL is written explicitly through tracker
+	u = MemberWithUpdateTracker{}
+	u.Id = m.Id
+	u.SetMoodNull()
+
+	_, err = a.Update(ctx, &u, u.TableName())
+	assert.NoError(err)
+
+	r = Member{Id: m.Id}
+	assert.NoError(a.Read(ctx, &r, r.TableName()))
+	assert.Equal(accessor.NewNull(0), r.Age)
+	assert.False(r.Mood.Valid)
+
+	u = MemberWithUpdateTracker{}
+	u.Id = m.Id
+	u.SetMood(MoodHappy)
+
+	_, err = a.Update(ctx, &u, u.TableName())
+	assert.NoError(err)
+
+	var moods []accessor.Null[Mood]
+	assert.NoError(a.Select(ctx, &moods, "SELECT mood FROM member"))
+	assert.Equal([]accessor.Null[Mood]{accessor.NewNull(MoodHappy)}, moods)
+
+	data, err := json.Marshal(Member{Id: 1, Mood: accessor.NewNull(MoodSad)})
+	assert.NoError(err)
+	assert.JSONEq(`{"Id":1,"Name":"","Age":null,"Mood":"sad","JoinedAt":null}`, string(data))
+}
+
+func (s *TestSuite) TestCreateChangedColumnsOnly() {
+	assert := require.New(s.T())
+
+	_ = s.Db.MustExec(`
+CREATE TABLE IF NOT EXISTS member (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name text,
+	age integer DEFAULT 18,
+	mood text DEFAULT 'happy',
+	joined_at timestamp DEFAULT CURRENT_TIMESTAMP
+);
+	`)
+	defer func() {
+		accessor.CreateChangedColumnsOnly = false
+		_ = s.Db.MustExec(`DROP TABLE IF EXISTS member;`)
+	}()
+
+	a := accessor.New(s.Db)
+	ctx := context.Background()
+
+	u := MemberWithUpdateTracker{}
+	u.SetName("foo").SetMood(MoodSad)
+
+	// all columns are inserted by default
+	assert.NoError(a.Create(ctx, &u, u.TableName()))
+	assert.False(u.Age.Valid)
+	assert.False(u.JoinedAt.Valid)
+
+	accessor.CreateChangedColumnsOnly = true
+
+	u = MemberWithUpdateTracker{}
+	u.SetName("bar").SetMood(MoodSad)
+	assert.NoError(a.Create(ctx, &u, u.TableName()))
+	assert.Equal("bar", u.Name)
+	assert.Equal(accessor.NewNull(18), u.Age)
+	assert.Equal(accessor.NewNull(MoodSad), u.Mood)
+	assert.True(u.JoinedAt.Valid)
+
+	// entities without tracked changes are inserted with all columns
+	u = MemberWithUpdateTracker{}
+	u.Name = "baz"
+	assert.NoError(a.Create(ctx, &u, u.TableName()))
+	assert.False(u.Age.Valid)
+}
@@ -0,0 +1,98 @@
+// CODE GENERATED AUTOMATICALLY WITH github.com/kelveny/gdbc entity enhancer
+// THIS FILE SHOULD NOT BE EDITED BY HAND
+package enhancer
+
+import (
+	"github.com/kelveny/gdbc/pkg/accessor"
+)
+
+type PartyEntityFields struct {
+	Id   string
+	Name string
+}
+
+type PartyTableColumns struct {
+	Id   string
+	Name string
+}
+
+func (e *Party) TableName() string {
+	return "party"
+}
+
+func (e *Party) EntityFields() *PartyEntityFields {
+	return &PartyEntityFields{
+		Id:   "Id",
+		Name: "Name",
+	}
+}
+
+func (e *Party) TableColumns() *PartyTableColumns {
+	return &PartyTableColumns{
+		Id:   "id",
+		Name: "name",
+	}
+}
+
+type PartyColumnCriteria struct {
+	Id   accessor.TableColumn
+	Name accessor.TableColumn
+}
+
+var PartyCols = PartyColumnCriteria{
+	Id:   accessor.NewTableColumn("party", "id"),
+	Name: accessor.NewTableColumn("party", "name"),
+}
+
+type PartyWithUpdateTracker struct {
+	Party
+	trackMap map[string]map[string]bool
+}
+
+func (e *PartyWithUpdateTracker) registerChange(tbl string, col string) {
+	if e.trackMap == nil {
+		e.trackMap = make(map[string]map[string]bool)
+	}
+
+	if m, ok := e.trackMap[tbl]; ok {
+		m[col] = true
+	} else {
+		m = make(map[string]bool)
+		e.trackMap[tbl] = m
+
+		m[col] = true
+	}
+}
+
+func (e *PartyWithUpdateTracker) MarkChanged(tbl string, col string) {
+	e.registerChange(tbl, col)
+}
+
+func (e *PartyWithUpdateTracker) ColumnsChanged(tbl ...string) []string {
+	cols := []string{}
+
+	if tbl == nil {
+		tbl = []string{"party"}
+	}
+
+	if e.trackMap != nil {
+		m := e.trackMap[tbl[0]]
+		for col := range m {
+			cols = append(cols, col)
+		}
+	}
+
+	return cols
+}
+
+func (e *PartyWithUpdateTracker) SetId(val int) *PartyWithUpdateTracker {
+	e.Id = val
+	e.registerChange("party", "id")
+	return e
+}
+
+func (e *PartyWithUpdateTracker) SetName(val string) *PartyWithUpdateTracker {
+	e.Name = val
+	e.registerChange("party", "name")
+	return e
+}
@@ -2,6 +2,10 @@
 // THIS FILE SHOULD NOT BE EDITED BY HAND
 package enhancer
 
+import (
+	"github.com/kelveny/gdbc/pkg/accessor"
+)
+
 type PersonEntityFields struct {
 	FirstName string
 	LastName  string
@@ -34,6 +38,18 @@ func (e *Person) TableColumns() *PersonTableColumns {
 	}
 }
 
+type PersonColumnCriteria struct {
+	FirstName accessor.TableColumn
+	LastName  accessor.TableColumn
+	Email     accessor.TableColumn
+}
+
+var PersonCols = PersonColumnCriteria{
+	FirstName: accessor.NewTableColumn("person", "first_name"),
+	LastName:  accessor.NewTableColumn("person", "last_name"),
+	Email:     accessor.NewTableColumn("person", "email"),
+}
+
 type PersonWithUpdateTracker struct {
 	Person
 	trackMap map[string]map[string]bool
@@ -54,6 +70,10 @@ func (e *PersonWithUpdateTracker) registerChange(tbl string, col string) {
 	}
 }
 
+func (e *PersonWithUpdateTracker) MarkChanged(tbl string, col string) {
+	e.registerChange(tbl, col)
+}
+
 func (e *PersonWithUpdateTracker) ColumnsChanged(tbl ...string) []string {
 	cols := []string{}
 
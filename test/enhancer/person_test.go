@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Masterminds/squirrel"
 	"github.com/jmoiron/sqlx"
 	"github.com/kelveny/gdbc/pkg/accessor"
 	"github.com/stretchr/testify/require"
@@ -180,3 +181,38 @@ func (s *TestSuite) TestCreateReadDelete() {
 	assert.True(err == nil)
 	assert.True(affected == 1)
 }
+
+func (s *TestSuite) TestColumnCriteria() {
+	assert := require.New(s.T())
+
+	a := accessor.New(s.Db)
+
+	var p []Person
+	err := a.EntitySelect(
+		context.Background(),
+		&p,
+		"person",
+		func(builder squirrel.SelectBuilder) accessor.Sqlizer {
+			return builder.
+				Where(PersonCols.LastName.Eq("test")).
+				Where(PersonCols.FirstName.In("foo", "bar")).
+				OrderBy(PersonCols.FirstName.Desc())
+		},
+	)
+	assert.NoError(err)
+	assert.Equal(2, len(p))
+	assert.Equal("foo", p[0].FirstName)
+	assert.Equal("bar", p[1].FirstName)
+
+	pp := Person{}
+	err = a.SqlizerGet(context.Background(), &pp, func(builder squirrel.StatementBuilderType) accessor.Sqlizer {
+		return builder.Select("*").From(pp.TableName()).Where(PersonCols.Email.Like("baz%"))
+	})
+	assert.Error(err)
+
+	err = a.SqlizerGet(context.Background(), &pp, func(builder squirrel.StatementBuilderType) accessor.Sqlizer {
+		return builder.Select("*").From(pp.TableName()).Where(PersonCols.Email.Like("bar%"))
+	})
+	assert.NoError(err)
+	assert.Equal("bar", pp.FirstName)
+}
@@ -0,0 +1,13 @@
+package enhancer
+
+//go:generate gdbc -entity Party -table party
+type Party struct {
+	Id   int    `db:"id"`
+	Name string `db:"name"`
+}
+
+//go:generate gdbc -entity Staff -table staff
+type Staff struct {
+	Party `db:",table=party,join=party_id:id"`
+	Title string `db:"title"`
+}
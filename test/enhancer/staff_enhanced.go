@@ -0,0 +1,102 @@
+// CODE GENERATED AUTOMATICALLY WITH github.com/kelveny/gdbc entity enhancer
+// THIS FILE SHOULD NOT BE EDITED BY HAND
+package enhancer
+
+import (
+	"github.com/kelveny/gdbc/pkg/accessor"
+)
+
+type StaffEntityFields struct {
+	Title string
+}
+
+type StaffTableColumns struct {
+	Title string
+}
+
+func (e *Staff) TableName() string {
+	return "staff"
+}
+
+func (e *Staff) EntityFields() *StaffEntityFields {
+	return &StaffEntityFields{
+		Title: "Title",
+	}
+}
+
+func (e *Staff) TableColumns() *StaffTableColumns {
+	return &StaffTableColumns{
+		Title: "title",
+	}
+}
+
+type StaffColumnCriteria struct {
+	Title accessor.TableColumn
+	Id    accessor.TableColumn
+	Name  accessor.TableColumn
+}
+
+var StaffCols = StaffColumnCriteria{
+	Title: accessor.NewTableColumn("staff", "title"),
+	Id:    accessor.NewTableColumn("party", "id"),
+	Name:  accessor.NewTableColumn("party", "name"),
+}
+
+type StaffWithUpdateTracker struct {
+	Staff
+	trackMap map[string]map[string]bool
+}
+
+func (e *StaffWithUpdateTracker) registerChange(tbl string, col string) {
+	if e.trackMap == nil {
+		e.trackMap = make(map[string]map[string]bool)
+	}
+
+	if m, ok := e.trackMap[tbl]; ok {
+		m[col] = true
+	} else {
+		m = make(map[string]bool)
+		e.trackMap[tbl] = m
+
+		m[col] = true
+	}
+}
+
+func (e *StaffWithUpdateTracker) MarkChanged(tbl string, col string) {
+	e.registerChange(tbl, col)
+}
+
+func (e *StaffWithUpdateTracker) ColumnsChanged(tbl ...string) []string {
+	cols := []string{}
+
+	if tbl == nil {
+		tbl = []string{"staff"}
+	}
+
+	if e.trackMap != nil {
+		m := e.trackMap[tbl[0]]
+		for col := range m {
+			cols = append(cols, col)
+		}
+	}
+
+	return cols
+}
+
+func (e *StaffWithUpdateTracker) SetTitle(val string) *StaffWithUpdateTracker {
+	e.Title = val
+	e.registerChange("staff", "title")
+	return e
+}
+
+func (e *StaffWithUpdateTracker) SetId(val int) *StaffWithUpdateTracker {
+	e.Id = val
+	e.registerChange("party", "id")
+	return e
+}
+
+func (e *StaffWithUpdateTracker) SetName(val string) *StaffWithUpdateTracker {
+	e.Name = val
+	e.registerChange("party", "name")
+	return e
+}
@@ -0,0 +1,62 @@
+package enhancer
+
+import (
+	"context"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/kelveny/gdbc/pkg/accessor"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *TestSuite) TestJoinColumns() {
+	assert := require.New(s.T())
+
+	_ = s.Db.MustExec(`
+CREATE TABLE IF NOT EXISTS party (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name text
+);
+
+CREATE TABLE IF NOT EXISTS staff (
+	party_id integer primary key,
+	title text
+);
+	`)
+	defer func() {
+		_ = s.Db.MustExec(`
+DROP TABLE IF EXISTS staff;
+DROP TABLE IF EXISTS party;
+		`)
+	}()
+
+	assert.Equal("party.name", StaffCols.Name.String())
+	assert.Equal("staff.title", StaffCols.Title.String())
+
+	a := accessor.New(s.Db)
+	ctx := context.Background()
+
+	e := Staff{Party: Party{Name: "foo"}, Title: "engineer"}
+	assert.NoError(a.Create(ctx, &e, e.TableName()))
+	assert.True(e.Id > 0)
+
+	u := StaffWithUpdateTracker{}
+	u.Id = e.Id
+	u.SetName("bar").SetTitle("manager")
+	_, err := a.Update(ctx, &u, u.TableName())
+	assert.NoError(err)
+
+	var list []Staff
+	assert.NoError(a.EntitySelect(ctx, &list, e.TableName(), func(builder squirrel.SelectBuilder) accessor.Sqlizer {
+		return builder.Where(StaffCols.Title.Eq("manager"))
+	}))
+	assert.Equal(1, len(list))
+	assert.Equal(e.Id, list[0].Id)
+	assert.Equal("bar", list[0].Name)
+
+	_, err = a.Delete(ctx, &Staff{Party: Party{Id: e.Id}}, e.TableName())
+	assert.NoError(err)
+
+	cnt, err := a.Count(ctx, Staff{}, e.TableName(), nil)
+	assert.NoError(err)
+	assert.Equal(int64(0), cnt)
+}
@@ -0,0 +1,10 @@
+package enhancer
+
+//go:generate gdbc -entity Ticket -table ticket
+type Ticket struct {
+	Id        int     `db:"id"`
+	Title     string  `db:"title"`
+	Code      string  `db:"code,readonly"`
+	CreatedBy string  `db:"created_by,insertonly"`
+	ClosedBy  *string `db:"closed_by,updateonly"`
+}
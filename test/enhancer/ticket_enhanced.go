@@ -0,0 +1,134 @@
+// CODE GENERATED AUTOMATICALLY WITH github.com/kelveny/gdbc entity enhancer
+// THIS FILE SHOULD NOT BE EDITED BY HAND
+package enhancer
+
+import (
+	"github.com/kelveny/gdbc/pkg/accessor"
+)
+
+type TicketEntityFields struct {
+	Id        string
+	Title     string
+	Code      string
+	CreatedBy string
+	ClosedBy  string
+}
+
+type TicketTableColumns struct {
+	Id        string
+	Title     string
+	Code      string
+	CreatedBy string
+	ClosedBy  string
+}
+
+func (e *Ticket) TableName() string {
+	return "ticket"
+}
+
+func (e *Ticket) EntityFields() *TicketEntityFields {
+	return &TicketEntityFields{
+		Id:        "Id",
+		Title:     "Title",
+		Code:      "Code",
+		CreatedBy: "CreatedBy",
+		ClosedBy:  "ClosedBy",
+	}
+}
+
+func (e *Ticket) TableColumns() *TicketTableColumns {
+	return &TicketTableColumns{
+		Id:        "id",
+		Title:     "title",
+		Code:      "code",
+		CreatedBy: "created_by",
+		ClosedBy:  "closed_by",
+	}
+}
+
+type TicketColumnCriteria struct {
+	Id        accessor.TableColumn
+	Title     accessor.TableColumn
+	Code      accessor.TableColumn
+	CreatedBy accessor.TableColumn
+	ClosedBy  accessor.TableColumn
+}
+
+var TicketCols = TicketColumnCriteria{
+	Id:        accessor.NewTableColumn("ticket", "id"),
+	Title:     accessor.NewTableColumn("ticket", "title"),
+	Code:      accessor.NewTableColumn("ticket", "code"),
+	CreatedBy: accessor.NewTableColumn("ticket", "created_by"),
+	ClosedBy:  accessor.NewTableColumn("ticket", "closed_by"),
+}
+
+type TicketWithUpdateTracker struct {
+	Ticket
+	trackMap map[string]map[string]bool
+}
+
+func (e *TicketWithUpdateTracker) registerChange(tbl string, col string) {
+	if e.trackMap == nil {
+		e.trackMap = make(map[string]map[string]bool)
+	}
+
+	if m, ok := e.trackMap[tbl]; ok {
+		m[col] = true
+	} else {
+		m = make(map[string]bool)
+		e.trackMap[tbl] = m
+
+		m[col] = true
+	}
+}
+
+func (e *TicketWithUpdateTracker) MarkChanged(tbl string, col string) {
+	e.registerChange(tbl, col)
+}
+
+func (e *TicketWithUpdateTracker) ColumnsChanged(tbl ...string) []string {
+	cols := []string{}
+
+	if tbl == nil {
+		tbl = []string{"ticket"}
+	}
+
+	if e.trackMap != nil {
+		m := e.trackMap[tbl[0]]
+		for col := range m {
+			cols = append(cols, col)
+		}
+	}
+
+	return cols
+}
+
+func (e *TicketWithUpdateTracker) SetId(val int) *TicketWithUpdateTracker {
+	e.Id = val
+	e.registerChange("ticket", "id")
+	return e
+}
+
+func (e *TicketWithUpdateTracker) SetTitle(val string) *TicketWithUpdateTracker {
+	e.Title = val
+	e.registerChange("ticket", "title")
+	return e
+}
+
+func (e *TicketWithUpdateTracker) SetCreatedBy(val string) *TicketWithUpdateTracker {
+	e.CreatedBy = val
+	e.registerChange("ticket", "created_by")
+	return e
+}
+
+func (e *TicketWithUpdateTracker) SetClosedBy(val *string) *TicketWithUpdateTracker {
+	e.ClosedBy = val
+	e.registerChange("ticket", "closed_by")
+	return e
+}
+
+func (e *TicketWithUpdateTracker) SetClosedByNull() *TicketWithUpdateTracker {
+	e.ClosedBy = nil
+	e.registerChange("ticket", "closed_by")
+	return e
+}
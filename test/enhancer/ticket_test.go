@@ -0,0 +1,107 @@
+package enhancer
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/kelveny/gdbc/pkg/accessor"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *TestSuite) TestColumnWriteModes() {
+	assert := require.New(s.T())
+
+	_ = s.Db.MustExec(`
+CREATE TABLE IF NOT EXISTS ticket (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	title text,
+	code text GENERATED ALWAYS AS (upper(title)) VIRTUAL,
+	created_by text,
+	closed_by text
+);
+	`)
+	defer func() {
+		_ = s.Db.MustExec(`DROP TABLE IF EXISTS ticket;`)
+	}()
+
+	// no setters are generated for readonly fields
+	_, ok := reflect.TypeOf(&TicketWithUpdateTracker{}).MethodByName("SetCode")
+	assert.False(ok)
+
+	a := accessor.New(s.Db)
+	ctx := context.Background()
+
+	closedBy := "bar"
+	t := Ticket{Title: "fix it", Code: "ignored", CreatedBy: "foo", ClosedBy: &closedBy}
+	assert.NoError(a.Create(ctx, &t, t.TableName()))
+	assert.Equal("FIX IT", t.Code)
+	assert.Equal("foo", t.CreatedBy)
+	assert.Nil(t.ClosedBy)
+
+	closedBy = "baz"
+	t.Title = "fixed"
+	t.CreatedBy = "changed"
+	t.ClosedBy = &closedBy
+	_, err := a.Update(ctx, &t, t.TableName())
+	assert.NoError(err)
+
+	r := Ticket{Id: t.Id}
+	assert.NoError(a.Read(ctx, &r, r.TableName()))
+	assert.Equal("FIXED", r.Code)
+	assert.Equal("foo", r.CreatedBy)
+	assert.Equal("baz", *r.ClosedBy)
+
+	// tracked changes of insertonly columns are not written either
+	u := TicketWithUpdateTracker{}
+	u.Id = t.Id
+	u.SetCreatedBy("changed")
+	result, err := a.Update(ctx, &u, u.TableName())
+	assert.NoError(err)
+	affected, err := result.RowsAffected()
+	assert.NoError(err)
+	assert.True(affected == 0)
+
+	_, err = a.UpdateWhere(ctx, Ticket{}, t.TableName(),
+		map[string]any{TicketCols.CreatedBy.String(): "changed"},
+		squirrel.Eq{TicketCols.Id.String(): t.Id},
+	)
+	assert.Error(err)
+
+	_, err = a.UpdateWhere(ctx, Ticket{}, t.TableName(),
+		map[string]any{TicketCols.Code.String(): "changed"},
+		squirrel.Eq{TicketCols.Id.String(): t.Id},
+	)
+	assert.Error(err)
+}
+
+func (s *TestSuite) TestUpdateReturning() {
+	assert := require.New(s.T())
+
+	_ = s.Db.MustExec(`
+CREATE TABLE IF NOT EXISTS ticket (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	title text,
+	code text GENERATED ALWAYS AS (upper(title)) VIRTUAL,
+	created_by text,
+	closed_by text
+);
+	`)
+	defer func() {
+		_ = s.Db.MustExec(`DROP TABLE IF EXISTS ticket;`)
+	}()
+
+	a := accessor.New(s.Db)
+	ctx := context.Background()
+
+	t := Ticket{Title: "fix it", CreatedBy: "foo"}
+	assert.NoError(a.Create(ctx, &t, t.TableName()))
+
+	// columns that are not tracked are read back as well
+	u := TicketWithUpdateTracker{}
+	u.Id = t.Id
+	u.SetTitle("fixed")
+	assert.NoError(a.UpdateReturning(ctx, &u, u.TableName()))
+	assert.Equal("FIXED", u.Code)
+	assert.Equal("foo", u.CreatedBy)
+}
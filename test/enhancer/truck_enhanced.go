@@ -0,0 +1,118 @@
+// CODE GENERATED AUTOMATICALLY WITH github.com/kelveny/gdbc entity enhancer
+// THIS FILE SHOULD NOT BE EDITED BY HAND
+package enhancer
+
+import (
+	"github.com/kelveny/gdbc/pkg/accessor"
+)
+
+type TruckEntityFields struct {
+	Payload string
+	Id      string
+	Make    string
+}
+
+type TruckTableColumns struct {
+	Payload string
+	Id      string
+	Make    string
+}
+
+func (e *Truck) TableName() string {
+	return "vehicle"
+}
+
+func (e *Truck) DiscriminatorColumn() string {
+	return "kind"
+}
+
+func (e *Truck) DiscriminatorValue() string {
+	return "truck"
+}
+
+func (e *Truck) EntityFields() *TruckEntityFields {
+	return &TruckEntityFields{
+		Payload: "Payload",
+		Id:      "Id",
+		Make:    "Make",
+	}
+}
+
+func (e *Truck) TableColumns() *TruckTableColumns {
+	return &TruckTableColumns{
+		Payload: "payload",
+		Id:      "id",
+		Make:    "make",
+	}
+}
+
+type TruckColumnCriteria struct {
+	Payload accessor.TableColumn
+	Id      accessor.TableColumn
+	Make    accessor.TableColumn
+}
+
+var TruckCols = TruckColumnCriteria{
+	Payload: accessor.NewTableColumn("vehicle", "payload"),
+	Id:      accessor.NewTableColumn("vehicle", "id"),
+	Make:    accessor.NewTableColumn("vehicle", "make"),
+}
+
+type TruckWithUpdateTracker struct {
+	Truck
+	trackMap map[string]map[string]bool
+}
+
+func (e *TruckWithUpdateTracker) registerChange(tbl string, col string) {
+	if e.trackMap == nil {
+		e.trackMap = make(map[string]map[string]bool)
+	}
+
+	if m, ok := e.trackMap[tbl]; ok {
+		m[col] = true
+	} else {
+		m = make(map[string]bool)
+		e.trackMap[tbl] = m
+
+		m[col] = true
+	}
+}
+
+func (e *TruckWithUpdateTracker) MarkChanged(tbl string, col string) {
+	e.registerChange(tbl, col)
+}
+
+func (e *TruckWithUpdateTracker) ColumnsChanged(tbl ...string) []string {
+	cols := []string{}
+
+	if tbl == nil {
+		tbl = []string{"vehicle"}
+	}
+
+	if e.trackMap != nil {
+		m := e.trackMap[tbl[0]]
+		for col := range m {
+			cols = append(cols, col)
+		}
+	}
+
+	return cols
+}
+
+func (e *TruckWithUpdateTracker) SetPayload(val float64) *TruckWithUpdateTracker {
+	e.Payload = val
+	e.registerChange("vehicle", "payload")
+	return e
+}
+
+func (e *TruckWithUpdateTracker) SetId(val int) *TruckWithUpdateTracker {
+	e.Id = val
+	e.registerChange("vehicle", "id")
+	return e
+}
+
+func (e *TruckWithUpdateTracker) SetMake(val string) *TruckWithUpdateTracker {
+	e.Make = val
+	e.registerChange("vehicle", "make")
+	return e
+}
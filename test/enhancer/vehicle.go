@@ -0,0 +1,19 @@
+package enhancer
+
+//go:generate gdbc -entity Vehicle -table vehicle
+type Vehicle struct {
+	Id   int    `db:"id"`
+	Make string `db:"make"`
+}
+
+//go:generate gdbc -entity Car -table vehicle
+type Car struct {
+	Vehicle `db:",discriminator=kind,value=car"`
+	Seats   int `db:"seats"`
+}
+
+//go:generate gdbc -entity Truck -table vehicle
+type Truck struct {
+	Vehicle `db:",discriminator=kind,value=truck"`
+	Payload float64 `db:"payload"`
+}
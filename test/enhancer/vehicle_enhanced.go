@@ -0,0 +1,98 @@
+// CODE GENERATED AUTOMATICALLY WITH github.com/kelveny/gdbc entity enhancer
+// THIS FILE SHOULD NOT BE EDITED BY HAND
+package enhancer
+
+import (
+	"github.com/kelveny/gdbc/pkg/accessor"
+)
+
+type VehicleEntityFields struct {
+	Id   string
+	Make string
+}
+
+type VehicleTableColumns struct {
+	Id   string
+	Make string
+}
+
+func (e *Vehicle) TableName() string {
+	return "vehicle"
+}
+
+func (e *Vehicle) EntityFields() *VehicleEntityFields {
+	return &VehicleEntityFields{
+		Id:   "Id",
+		Make: "Make",
+	}
+}
+
+func (e *Vehicle) TableColumns() *VehicleTableColumns {
+	return &VehicleTableColumns{
+		Id:   "id",
+		Make: "make",
+	}
+}
+
+type VehicleColumnCriteria struct {
+	Id   accessor.TableColumn
+	Make accessor.TableColumn
+}
+
+var VehicleCols = VehicleColumnCriteria{
+	Id:   accessor.NewTableColumn("vehicle", "id"),
+	Make: accessor.NewTableColumn("vehicle", "make"),
+}
+
+type VehicleWithUpdateTracker struct {
+	Vehicle
+	trackMap map[string]map[string]bool
+}
+
+func (e *VehicleWithUpdateTracker) registerChange(tbl string, col string) {
+	if e.trackMap == nil {
+		e.trackMap = make(map[string]map[string]bool)
+	}
+
+	if m, ok := e.trackMap[tbl]; ok {
+		m[col] = true
+	} else {
+		m = make(map[string]bool)
+		e.trackMap[tbl] = m
+
+		m[col] = true
+	}
+}
+
+func (e *VehicleWithUpdateTracker) MarkChanged(tbl string, col string) {
+	e.registerChange(tbl, col)
+}
+
+func (e *VehicleWithUpdateTracker) ColumnsChanged(tbl ...string) []string {
+	cols := []string{}
+
+	if tbl == nil {
+		tbl = []string{"vehicle"}
+	}
+
+	if e.trackMap != nil {
+		m := e.trackMap[tbl[0]]
+		for col := range m {
+			cols = append(cols, col)
+		}
+	}
+
+	return cols
+}
+
+func (e *VehicleWithUpdateTracker) SetId(val int) *VehicleWithUpdateTracker {
+	e.Id = val
+	e.registerChange("vehicle", "id")
+	return e
+}
+
+func (e *VehicleWithUpdateTracker) SetMake(val string) *VehicleWithUpdateTracker {
+	e.Make = val
+	e.registerChange("vehicle", "make")
+	return e
+}
@@ -0,0 +1,118 @@
+package enhancer
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/kelveny/gdbc/pkg/accessor"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *TestSuite) TestSingleTableInheritance() {
+	assert := require.New(s.T())
+
+	_ = s.Db.MustExec(`
+CREATE TABLE IF NOT EXISTS vehicle (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	kind text,
+	make text,
+	seats integer,
+	payload real
+);
+	`)
+	defer func() {
+		_ = s.Db.MustExec(`DROP TABLE IF EXISTS vehicle;`)
+	}()
+
+	a := accessor.New(s.Db)
+	ctx := context.Background()
+
+	c := Car{Vehicle: Vehicle{Make: "foo"}, Seats: 4}
+	assert.Equal("vehicle", c.TableName())
+	assert.Equal("kind", c.DiscriminatorColumn())
+	assert.Equal("car", c.DiscriminatorValue())
+	assert.NoError(a.Create(ctx, &c, c.TableName()))
+
+	t := Truck{Vehicle: Vehicle{Make: "bar"}, Payload: 1.5}
+	assert.NoError(a.Create(ctx, &t, t.TableName()))
+
+	r := Truck{}
+	r.Id = c.Id
+	assert.ErrorIs(a.Read(ctx, &r, r.TableName()), sql.ErrNoRows)
+
+	r.Id = t.Id
+	assert.NoError(a.Read(ctx, &r, r.TableName()))
+	assert.Equal(t, r)
+
+	// rows of all subtypes are visible through the base type
+	var vehicles []Vehicle
+	assert.NoError(a.EntitySelect(ctx, &vehicles, "vehicle", func(builder squirrel.SelectBuilder) accessor.Sqlizer {
+		return builder.OrderBy(VehicleCols.Id.Asc())
+	}))
+	assert.Equal(2, len(vehicles))
+
+	var cars []Car
+	assert.NoError(a.EntitySelect(ctx, &cars, c.TableName(), func(builder squirrel.SelectBuilder) accessor.Sqlizer {
+		return builder.Where(CarCols.Make.Like("%"))
+	}))
+	assert.Equal([]Car{c}, cars)
+
+	u := CarWithUpdateTracker{}
+	u.Id = c.Id
+	u.SetSeats(2).SetMake("baz")
+	_, err := a.Update(ctx, &u, u.TableName())
+	assert.NoError(err)
+
+	rc := Car{}
+	rc.Id = c.Id
+	assert.NoError(a.Read(ctx, &rc, rc.TableName()))
+	assert.Equal(2, rc.Seats)
+	assert.Equal("baz", rc.Make)
+
+	cnt, err := a.Count(ctx, Truck{}, t.TableName(), nil)
+	assert.NoError(err)
+	assert.Equal(int64(1), cnt)
+}
+
+func (s *TestSuite) TestPolymorphicVehicles() {
+	assert := require.New(s.T())
+
+	_ = s.Db.MustExec(`
+CREATE TABLE IF NOT EXISTS vehicle (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	kind text,
+	make text,
+	seats integer,
+	payload real
+);
+	`)
+	defer func() {
+		_ = s.Db.MustExec(`DROP TABLE IF EXISTS vehicle;`)
+	}()
+
+	assert.NoError(accessor.RegisterHierarchy(&Vehicle{}, &Car{}, &Truck{}))
+	defer accessor.UnregisterHierarchy(&Vehicle{})
+
+	a := accessor.New(s.Db)
+	ctx := context.Background()
+
+	v := Vehicle{Make: "foo"}
+	assert.NoError(a.Create(ctx, &v, v.TableName()))
+
+	c := Car{Vehicle: Vehicle{Make: "bar"}, Seats: 4}
+	assert.NoError(a.Create(ctx, &c, c.TableName()))
+
+	t := Truck{Vehicle: Vehicle{Make: "baz"}, Payload: 1.5}
+	assert.NoError(a.Create(ctx, &t, t.TableName()))
+
+	entity, err := a.ReadPolymorphic(ctx, c.Id, &Vehicle{})
+	assert.NoError(err)
+	assert.Equal(&c, entity)
+
+	entities, err := a.SelectPolymorphic(ctx, &Vehicle{}, func(builder squirrel.SelectBuilder) accessor.Sqlizer {
+		return builder.OrderBy(VehicleCols.Id.Asc())
+	})
+	assert.NoError(err)
+	assert.Equal([]any{&v, &c, &t}, entities)
+}